@@ -15,6 +15,7 @@ import { authService } from './services/authService.js';
 import { rateLimitService, extractRateLimitIdentifier, addRateLimitHeaders } from './services/rateLimitService.js';
 import { validationService } from './services/validationService.js';
 import { cacheService, CacheKeys } from './services/cacheService.js';
+import { testRunnerService } from './services/testRunnerService.js';
 
 /**
  * ALCS MCP Server
@@ -357,7 +358,8 @@ async function main() {
 
   /**
    * Graceful shutdown handler
-   * Waits for in-flight requests to complete before shutting down
+   * Cancels running tests (removing their containers) and waits for
+   * in-flight requests to complete before shutting down
    */
   const shutdown = async (signal: string) => {
     if (isShuttingDown) {
@@ -369,6 +371,9 @@ async function main() {
     logger.info(`Received ${signal}, initiating graceful shutdown...`);
     logger.info(`Current in-flight requests: ${inFlightRequests}`);
 
+    // Test runs return partial results once their containers are cleaned up
+    testRunnerService.cancelAll();
+
     // Wait for in-flight requests to complete (max 30 seconds)
     const shutdownTimeout = 30000;
     const startTime = Date.now();
@@ -0,0 +1,97 @@
+/**
+ * ALCS Runner
+ *
+ * Library entry point for running Go test suites in-process, for services
+ * that embed ALCS instead of calling the MCP tools. Everything the tools can
+ * configure is expressed through RunnerOptions; nothing is read from
+ * process.argv or config files. Live go test events and finished summaries
+ * are delivered to injected handlers.
+ *
+ *   const runner = new Runner({ parallel: 4, eventHandlers: [myProgressUI] });
+ *   const summary = await runner.run({ workspacePath: '/src/mod', packages: ['./api/...'] });
+ */
+
+import { GoTestRunner } from './services/testRunners/goTestRunner';
+import { EventHandler } from './services/testRunners/goTestEventStream';
+import { createRunSummary, SummaryHandler } from './services/reporters/jsonSummaryReporter';
+import { RunSummary, TestExecutionOptions } from './types/mcp';
+
+export type { EventHandler, GoTestEvent } from './services/testRunners/goTestEventStream';
+export type { SummaryHandler } from './services/reporters/jsonSummaryReporter';
+export type { RunSummary, TestCaseResult, TestExecutionOptions } from './types/mcp';
+
+export interface RunnerOptions extends TestExecutionOptions {
+  eventHandlers?: EventHandler[];     // Live go test events, in arrival order
+  summaryHandlers?: SummaryHandler[]; // Called with the summary of every run
+}
+
+export interface RunRequest {
+  workspacePath: string;              // Go module root
+  packages?: string[];                // Package patterns (default: ./...)
+  options?: TestExecutionOptions;     // Per-run overrides of the runner's options
+}
+
+export class Runner {
+  private options: RunnerOptions;
+
+  constructor(options: RunnerOptions = {}) {
+    this.options = options;
+  }
+
+  /**
+   * Run a suite and return its summary
+   * Test failures are reported in the summary; only a run that could not
+   * execute at all (missing toolchain, unreadable config) rejects. Benchmark,
+   * dry-run and list modes produce no test summary, and a go_versions matrix
+   * produces one per version, so they are rejected; use GoTestRunner.execute
+   * for them.
+   * @param request What to run
+   * @param signal Cancels the run; the summary then has exit_status 130
+   * @returns Summary of the run
+   * @throws If go test could not be run, or a mode without a summary was requested
+   */
+  async run(request: RunRequest, signal?: AbortSignal): Promise<RunSummary> {
+    const { eventHandlers, summaryHandlers, ...defaults } = this.options;
+    const options: TestExecutionOptions = {
+      ...defaults,
+      ...request.options,
+      packages: request.packages || request.options?.packages || defaults.packages,
+    };
+
+    const mode = options.bench ? 'bench' : options.dry_run ? 'dry_run' : options.list ? 'list' : undefined;
+    if (mode) {
+      throw new Error(`Runner does not support ${mode}: it produces no test run summary`);
+    }
+    if (options.go_versions && options.go_versions.length > 0) {
+      throw new Error('Runner does not support go_versions: it produces one summary per Go version, not one run summary');
+    }
+
+    const captured: { summary?: RunSummary } = {};
+    const capture: SummaryHandler = { handleSummary: summary => { captured.summary = summary; } };
+    const goTestRunner = new GoTestRunner(eventHandlers, [...(summaryHandlers || []), capture]);
+
+    const startedAt = new Date();
+    const result = await goTestRunner.execute(request.workspacePath, '', request.workspacePath, options, signal);
+    if (captured.summary) {
+      return captured.summary;
+    }
+
+    if (!result.success) {
+      throw new Error(result.failures[0]?.error_message || 'go test failed without a summary');
+    }
+
+    // Nothing to run, e.g. no packages affected since options.since_ref
+    const empty = createRunSummary({
+      framework: goTestRunner.framework,
+      startedAt,
+      finishedAt: new Date(),
+      tests: [],
+      coveragePercentage: 0,
+      success: true,
+    });
+    for (const handler of summaryHandlers || []) {
+      await handler.handleSummary(empty);
+    }
+    return empty;
+  }
+}
@@ -0,0 +1,175 @@
+/**
+ * Benchmark Service
+ *
+ * Parses `go test -bench -benchmem` result lines and compares two runs.
+ * Format:
+ *   BenchmarkParse/small-8   	  500000	      2345 ns/op	     512 B/op	       7 allocs/op
+ * Results are matched across runs by package and name; the -GOMAXPROCS
+ * suffix is kept on the result but ignored for matching since it differs
+ * between machines.
+ */
+
+import {
+  BenchDelta,
+  BenchMetric,
+  BenchResult,
+  BenchmarkComparison,
+  Regression,
+} from '../types/mcp';
+
+const BENCH_LINE = /^(Benchmark\S*?)(?:-(\d+))?\s+(\d+)\s+(.*\bns\/op\b.*)$/;
+
+const METRICS: BenchMetric[] = ['ns_per_op', 'bytes_per_op', 'allocs_per_op'];
+
+export class BenchmarkService {
+  /**
+   * Parse benchmark result lines
+   * @param output go test output (other lines are ignored)
+   * @param pkg Package the output belongs to
+   * @returns Results in output order; -count reruns appear once per run
+   */
+  parse(output: string, pkg: string = ''): BenchResult[] {
+    const results: BenchResult[] = [];
+
+    for (const line of output.split('\n')) {
+      const match = line.trim().match(BENCH_LINE);
+      if (!match) {
+        continue;
+      }
+
+      const result: BenchResult = {
+        package: pkg,
+        name: match[1],
+        procs: match[2] ? parseInt(match[2], 10) : 1,
+        iterations: parseInt(match[3], 10),
+        ns_per_op: 0,
+      };
+
+      // The remainder is "<value> <unit>" pairs separated by tabs/spaces
+      const fields = match[4].trim().split(/\s+/);
+      for (let i = 0; i + 1 < fields.length; i += 2) {
+        const value = parseFloat(fields[i]);
+        if (isNaN(value)) {
+          continue;
+        }
+        this.setMetric(result, fields[i + 1], value);
+      }
+
+      results.push(result);
+    }
+
+    return results;
+  }
+
+  /**
+   * Benchmarks that got worse by more than the threshold
+   * @param base Baseline results
+   * @param current New results
+   * @param threshold Allowed increase in percent, e.g. 10
+   */
+  compare(base: BenchResult[], current: BenchResult[], threshold: number): Regression[] {
+    return this.diff(base, current, threshold).regressions;
+  }
+
+  /**
+   * Per-metric deltas between two runs
+   * Benchmarks present in only one run are listed as added/removed and never
+   * count as regressions. Repeated runs (-count) are averaged.
+   * @param base Baseline results
+   * @param current New results
+   * @param threshold Allowed increase in percent
+   */
+  diff(base: BenchResult[], current: BenchResult[], threshold: number): BenchmarkComparison {
+    const baseByKey = this.average(base);
+    const currentByKey = this.average(current);
+    const deltas: BenchDelta[] = [];
+
+    for (const [key, now] of currentByKey) {
+      const then = baseByKey.get(key);
+      if (!then) {
+        continue;
+      }
+
+      for (const metric of METRICS) {
+        const before = then[metric];
+        const after = now[metric];
+        if (before === undefined || after === undefined) {
+          continue;
+        }
+
+        deltas.push({
+          package: now.package,
+          name: now.name,
+          metric,
+          base: before,
+          current: after,
+          delta_percent: before === 0 ? (after === 0 ? 0 : null) : ((after - before) / before) * 100,
+        });
+      }
+    }
+
+    // Lower is better for every tracked metric; growing from zero always counts
+    const regressions = deltas.filter(d =>
+      d.delta_percent === null ? d.current > d.base : d.delta_percent > threshold
+    );
+
+    return {
+      deltas,
+      regressions,
+      added: Array.from(currentByKey.keys()).filter(k => !baseByKey.has(k)).sort(),
+      removed: Array.from(baseByKey.keys()).filter(k => !currentByKey.has(k)).sort(),
+      threshold_percent: threshold,
+    };
+  }
+
+  /**
+   * Record a value under its unit
+   * Units are case-insensitive so b/op and B/op both map to bytes.
+   */
+  private setMetric(result: BenchResult, unit: string, value: number): void {
+    switch (unit.toLowerCase()) {
+      case 'ns/op':
+        result.ns_per_op = value;
+        break;
+      case 'b/op':
+        result.bytes_per_op = value;
+        break;
+      case 'allocs/op':
+        result.allocs_per_op = value;
+        break;
+      default:
+        result.metrics = { ...result.metrics, [unit]: value };
+    }
+  }
+
+  /**
+   * Average repeated results for the same benchmark, keyed by "package name"
+   */
+  private average(results: BenchResult[]): Map<string, BenchResult> {
+    const groups = new Map<string, BenchResult[]>();
+    for (const result of results) {
+      const key = `${result.package} ${result.name}`.trim();
+      groups.set(key, [...(groups.get(key) || []), result]);
+    }
+
+    const averaged = new Map<string, BenchResult>();
+    for (const [key, runs] of groups) {
+      const mean = (metric: BenchMetric): number | undefined => {
+        const values = runs.map(r => r[metric]).filter((v): v is number => v !== undefined);
+        return values.length > 0 ? values.reduce((sum, v) => sum + v, 0) / values.length : undefined;
+      };
+
+      averaged.set(key, {
+        ...runs[0],
+        ns_per_op: mean('ns_per_op') || 0,
+        bytes_per_op: mean('bytes_per_op'),
+        allocs_per_op: mean('allocs_per_op'),
+      });
+    }
+
+    return averaged;
+  }
+}
+
+// Export singleton instance
+export const benchmarkService = new BenchmarkService();
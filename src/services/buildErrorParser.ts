@@ -0,0 +1,67 @@
+/**
+ * Build Error Parser
+ *
+ * Extracts compiler diagnostics from `go build` / `go test` output.
+ * Format:
+ *   # example.com/mod/calc [example.com/mod/calc.test]
+ *   ./calc.go:12:9: undefined: Sum
+ *   ./calc.go:20:2: cannot use x (variable of type int) as string value in return statement
+ *   	have (int)
+ *   	want (string)
+ * Tab-indented continuation lines belong to the preceding error. Package
+ * headers and other tool chatter are ignored.
+ */
+
+import { CompilerError } from '../types/mcp';
+
+const ERROR_LINE = /^(\S+?\.go):(\d+)(?::(\d+))?: (.+)$/;
+
+export class BuildErrorParser {
+  /**
+   * Parse compiler errors
+   * @param output Build output for one or more packages
+   * @returns Errors in output order, without duplicates
+   */
+  parse(output: string): CompilerError[] {
+    const errors: CompilerError[] = [];
+    let last: CompilerError | undefined;
+
+    for (const line of output.split('\n')) {
+      const match = line.trim().match(ERROR_LINE);
+      if (match) {
+        last = {
+          file: match[1],
+          line: parseInt(match[2], 10),
+          column: match[3] ? parseInt(match[3], 10) : undefined,
+          message: match[4],
+        };
+        errors.push(last);
+      } else if (last && line.startsWith('\t')) {
+        last.message += `\n${line.trim()}`;
+      } else {
+        last = undefined;
+      }
+    }
+
+    // Packages built once per dependent (parallel mode) repeat the same diagnostics
+    const seen = new Set<string>();
+    return errors.filter(e => {
+      const key = `${e.file}:${e.line}:${e.column}: ${e.message}`;
+      if (seen.has(key)) {
+        return false;
+      }
+      seen.add(key);
+      return true;
+    });
+  }
+
+  /**
+   * One-line description of an error, as the compiler printed it
+   */
+  format(error: CompilerError): string {
+    return `${error.file}:${error.line}${error.column !== undefined ? `:${error.column}` : ''}: ${error.message.split('\n')[0]}`;
+  }
+}
+
+// Export singleton instance
+export const buildErrorParser = new BuildErrorParser();
@@ -0,0 +1,167 @@
+/**
+ * Changed Line Coverage
+ *
+ * Coverage of only the lines a change added or modified, for "new code must
+ * be X% covered" checks that do not hold legacy code to the same bar.
+ *
+ * A changed line counts when a statement block puts code on it. Profile
+ * blocks include the braces around them, so the columns a block covers are
+ * checked against the line's text from the diff: a line whose covered part
+ * is only braces, `else`, or a comment (a func signature ending in `{`, a
+ * closing `}`) is not executable. A line split between a block that ran and
+ * one that did not counts as uncovered.
+ */
+
+import { execFile } from 'child_process';
+import { promisify } from 'util';
+import {
+  ChangedCoverageReport,
+  ChangedFileCoverage,
+  ChangedLine,
+  GoCoverageBlock,
+  GoCoverageProfile,
+  LineDiff,
+} from '../types/mcp';
+
+const execFileAsync = promisify(execFile);
+
+const HUNK_HEADER = /^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@/;
+
+export class ChangedLineCoverageService {
+  /**
+   * Lines changed since a git ref
+   * @param moduleRoot Module root (inside a git checkout); paths are relative to it
+   * @param ref Git ref to diff against, e.g. origin/main
+   */
+  async changedLinesSince(moduleRoot: string, ref: string): Promise<LineDiff> {
+    const { stdout } = await execFileAsync(
+      'git',
+      ['diff', '--unified=0', '--relative', '--no-color', '--no-ext-diff', `${ref}...HEAD`],
+      { cwd: moduleRoot, maxBuffer: 50 * 1024 * 1024 }
+    );
+
+    return this.parseDiff(stdout);
+  }
+
+  /**
+   * Parse a unified diff into the added lines of each file
+   * Deleted files and removed lines are dropped; a modified line appears as
+   * removed and re-added, so it is kept.
+   * @param diff Output of git diff
+   */
+  parseDiff(diff: string): LineDiff {
+    const files: LineDiff['files'] = {};
+    let current: ChangedLine[] | undefined;
+    let inHunk = false;
+    let newLine = 0;
+
+    for (const line of diff.split('\n')) {
+      if (line.startsWith('diff --git ')) {
+        current = undefined;
+        inHunk = false;
+        continue;
+      }
+
+      if (!inHunk && line.startsWith('+++ ')) {
+        const target = line.slice(4).trim();
+        current = target === '/dev/null' ? undefined : (files[target.replace(/^b\//, '')] = []);
+        continue;
+      }
+
+      const hunk = line.match(HUNK_HEADER);
+      if (hunk) {
+        inHunk = true;
+        newLine = parseInt(hunk[1], 10);
+        continue;
+      }
+
+      if (!inHunk) {
+        continue;
+      }
+      if (line.startsWith('+')) {
+        current?.push({ line: newLine, text: line.slice(1) });
+        newLine++;
+      } else if (line.startsWith(' ')) {
+        newLine++;
+      }
+    }
+
+    for (const [file, lines] of Object.entries(files)) {
+      if (lines.length === 0) {
+        delete files[file]; // Mode changes and renames without edits
+      }
+    }
+
+    return { files };
+  }
+
+  /**
+   * Coverage of the changed executable lines
+   * Diff paths are matched to profile file names by suffix, since the
+   * profile names files by import path (example.com/mod/pkg/file.go).
+   * @param profile Merged coverage profile
+   * @param diff Changed lines
+   * @returns Totals, and the uncovered lines of each file
+   */
+  changedLineCoverage(profile: GoCoverageProfile, diff: LineDiff): ChangedCoverageReport {
+    const files: ChangedFileCoverage[] = [];
+
+    for (const diffPath of Object.keys(diff.files).sort()) {
+      const fileName = Object.keys(profile.files).find(f => f === diffPath || f.endsWith(`/${diffPath}`));
+      if (!fileName) {
+        continue; // Tests, non-Go files, and packages that did not run
+      }
+
+      const blocks = profile.files[fileName].filter(b => b.num_statements > 0);
+      const file: ChangedFileCoverage = { file: fileName, covered: 0, executable: 0, uncovered_lines: [] };
+
+      for (const changed of diff.files[diffPath]) {
+        const executing = blocks.filter(b => b.start_line <= changed.line && changed.line <= b.end_line)
+          .filter(b => this.hasCode(changed, b));
+        if (executing.length === 0) {
+          continue;
+        }
+
+        file.executable++;
+        if (executing.every(b => b.count > 0)) {
+          file.covered++;
+        } else {
+          file.uncovered_lines.push(changed.line);
+        }
+      }
+
+      if (file.executable > 0) {
+        files.push(file);
+      }
+    }
+
+    const covered = files.reduce((sum, f) => sum + f.covered, 0);
+    const executable = files.reduce((sum, f) => sum + f.executable, 0);
+
+    return {
+      covered_lines: covered,
+      executable_lines: executable,
+      percentage: executable === 0 ? 100 : (covered / executable) * 100,
+      files,
+    };
+  }
+
+  /**
+   * Whether the part of the line a block covers holds code
+   * Profile columns are 1-based and the end column is exclusive.
+   */
+  private hasCode(changed: ChangedLine, block: GoCoverageBlock): boolean {
+    const from = changed.line === block.start_line ? block.start_col - 1 : 0;
+    const to = changed.line === block.end_line ? block.end_col - 1 : changed.text.length;
+
+    const code = changed.text.slice(Math.max(from, 0), to)
+      .replace(/\/\*.*?\*\//g, '')
+      .replace(/\/\/.*$/, '')
+      .replace(/\belse\b/g, '')
+      .replace(/[\s{}()]/g, '');
+    return code.length > 0;
+  }
+}
+
+// Export singleton instance
+export const changedLineCoverageService = new ChangedLineCoverageService();
@@ -0,0 +1,110 @@
+/**
+ * Coverage Gate
+ *
+ * Evaluates a Go coverage profile against per-package minimums.
+ * Package paths are matched against glob rules; the most specific matching
+ * rule (longest pattern) sets the requirement, otherwise the default applies.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import {
+  CoverageGateConfig,
+  CoverageGateRule,
+  CoverageViolation,
+  GoCoverageProfile,
+} from '../types/mcp';
+import { globToRegExp, matchesAnyGlob } from '../utils/globMatcher';
+import { logger } from './loggerService';
+
+export class CoverageGate {
+  private config: CoverageGateConfig;
+
+  constructor(config: CoverageGateConfig) {
+    this.config = config;
+  }
+
+  /**
+   * Load a gate from a JSON config file
+   * @param configPath Path to the gate config
+   * @returns Configured gate
+   */
+  static async fromFile(configPath: string): Promise<CoverageGate> {
+    const content = await fs.readFile(configPath, 'utf-8');
+    const config = JSON.parse(content) as CoverageGateConfig;
+
+    if (typeof config.default_minimum !== 'number') {
+      throw new Error(`Invalid coverage config ${configPath}: default_minimum must be a number`);
+    }
+
+    return new CoverageGate(config);
+  }
+
+  /**
+   * Evaluate a profile against the configured minimums
+   * @param profile Merged coverage profile
+   * @returns Violations, sorted by package (empty when the gate passes)
+   */
+  evaluate(profile: GoCoverageProfile): CoverageViolation[] {
+    const violations: CoverageViolation[] = [];
+    const packages = this.summarizePackages(profile);
+
+    for (const pkg of Array.from(packages.keys()).sort()) {
+      const { covered, total } = packages.get(pkg)!;
+      if (total === 0) {
+        continue;
+      }
+
+      const actual = (covered / total) * 100;
+      const rule = this.findRule(pkg);
+      const required = rule ? rule.minimum : this.config.default_minimum;
+
+      if (actual < required) {
+        violations.push({ package: pkg, actual, required, pattern: rule?.pattern });
+      }
+    }
+
+    if (violations.length > 0) {
+      logger.warn(`Coverage gate failed for ${violations.length} of ${packages.size} packages`);
+    }
+
+    return violations;
+  }
+
+  /**
+   * Sum statements per package, skipping excluded files
+   */
+  private summarizePackages(profile: GoCoverageProfile): Map<string, { covered: number; total: number }> {
+    const packages = new Map<string, { covered: number; total: number }>();
+    const exclude = this.config.exclude || [];
+
+    for (const [fileName, blocks] of Object.entries(profile.files)) {
+      if (matchesAnyGlob(fileName, exclude)) {
+        continue;
+      }
+
+      const pkg = path.posix.dirname(fileName);
+      const summary = packages.get(pkg) || { covered: 0, total: 0 };
+
+      for (const block of blocks) {
+        summary.total += block.num_statements;
+        if (block.count > 0) {
+          summary.covered += block.num_statements;
+        }
+      }
+
+      packages.set(pkg, summary);
+    }
+
+    return packages;
+  }
+
+  /**
+   * Find the most specific rule matching a package path
+   */
+  private findRule(pkg: string): CoverageGateRule | undefined {
+    return (this.config.rules || [])
+      .filter(rule => globToRegExp(rule.pattern).test(pkg))
+      .sort((a, b) => b.pattern.length - a.pattern.length)[0];
+  }
+}
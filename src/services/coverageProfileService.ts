@@ -0,0 +1,325 @@
+/**
+ * Coverage Profile Service
+ *
+ * Block-level handling of Go coverage profiles (coverage.out).
+ * Parses, merges, filters, and writes profiles so coverage from sharded test
+ * runs can be combined into a single report.
+ */
+
+import * as fs from 'fs/promises';
+import { GoCoverageBlock, GoCoverageMode, GoCoverageProfile } from '../types/mcp';
+import { matchesAnyGlob } from '../utils/globMatcher';
+import { logger } from './loggerService';
+
+// Format: file.go:startLine.startCol,endLine.endCol numStmt count
+const BLOCK_LINE_PATTERN = /^(.+):(\d+)\.(\d+),(\d+)\.(\d+)\s+(\d+)\s+(\d+)$/;
+
+export class CoverageMergeError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'CoverageMergeError';
+  }
+}
+
+export class CoverageProfileService {
+  /**
+   * Parse the contents of a Go coverage profile
+   * @param content Raw coverage.out content
+   * @returns Parsed profile
+   */
+  parseProfile(content: string): GoCoverageProfile {
+    const profile: GoCoverageProfile = { mode: 'set', files: {} };
+
+    for (const rawLine of content.split('\n')) {
+      const line = rawLine.trim();
+      if (!line) {
+        continue;
+      }
+
+      if (line.startsWith('mode:')) {
+        profile.mode = line.slice('mode:'.length).trim() as GoCoverageMode;
+        continue;
+      }
+
+      const match = line.match(BLOCK_LINE_PATTERN);
+      if (!match) {
+        logger.warn(`Skipping malformed coverage profile line: ${line}`);
+        continue;
+      }
+
+      const fileName = match[1];
+      const blocks = profile.files[fileName] || [];
+      blocks.push({
+        start_line: parseInt(match[2], 10),
+        start_col: parseInt(match[3], 10),
+        end_line: parseInt(match[4], 10),
+        end_col: parseInt(match[5], 10),
+        num_statements: parseInt(match[6], 10),
+        count: parseInt(match[7], 10),
+      });
+      profile.files[fileName] = blocks;
+    }
+
+    return profile;
+  }
+
+  /**
+   * Read and parse a Go coverage profile file
+   * @param profilePath Path to coverage.out
+   * @returns Parsed profile
+   */
+  async readProfile(profilePath: string): Promise<GoCoverageProfile> {
+    const content = await fs.readFile(profilePath, 'utf-8');
+    return this.parseProfile(content);
+  }
+
+  /**
+   * Serialize a profile back into the coverage.out format
+   * Output is accepted by `go tool cover`, gocov, and gocov-xml.
+   * @param profile Profile to serialize
+   * @returns coverage.out content
+   */
+  formatProfile(profile: GoCoverageProfile): string {
+    const lines = [`mode: ${profile.mode}`];
+
+    for (const fileName of Object.keys(profile.files).sort()) {
+      const blocks = [...profile.files[fileName]].sort(this.compareBlocks);
+      for (const block of blocks) {
+        lines.push(
+          `${fileName}:${block.start_line}.${block.start_col},${block.end_line}.${block.end_col} ` +
+          `${block.num_statements} ${block.count}`
+        );
+      }
+    }
+
+    return lines.join('\n') + '\n';
+  }
+
+  /**
+   * Write a profile to disk in the coverage.out format
+   * @param profilePath Destination path
+   * @param profile Profile to write
+   */
+  async writeProfile(profilePath: string, profile: GoCoverageProfile): Promise<void> {
+    await fs.writeFile(profilePath, this.formatProfile(profile), 'utf-8');
+  }
+
+  /**
+   * Drop every block of files matching an exclude pattern
+   * Patterns match the file name exactly as it appears in the profile
+   * (import path form, e.g. example.com/mod/api/v1/user.pb.go); unanchored
+   * patterns match at any depth, so `*.pb.go`, `vendor/`, and `mocks/` work
+   * as expected.
+   * @param profile Profile to filter (not modified)
+   * @param exclude Glob patterns
+   * @returns Profile without the excluded files
+   */
+  filter(profile: GoCoverageProfile, exclude: string[]): GoCoverageProfile {
+    const files: GoCoverageProfile['files'] = {};
+    let dropped = 0;
+
+    for (const [fileName, blocks] of Object.entries(profile.files)) {
+      if (matchesAnyGlob(fileName, exclude)) {
+        dropped++;
+      } else {
+        files[fileName] = blocks;
+      }
+    }
+
+    if (dropped > 0) {
+      logger.info(`Excluded ${dropped} files from coverage`);
+    }
+    return { mode: profile.mode, files };
+  }
+
+  /**
+   * Merge multiple profiles into one
+   * Identical blocks are deduplicated and their hit counts summed.
+   * @param profiles Profiles to merge (e.g. one per test shard)
+   * @returns Merged profile
+   * @throws CoverageMergeError if profiles use different modes or disagree on block boundaries
+   */
+  mergeProfiles(...profiles: GoCoverageProfile[]): GoCoverageProfile {
+    if (profiles.length === 0) {
+      return { mode: 'set', files: {} };
+    }
+
+    const mode = profiles[0].mode;
+    const byFile = new Map<string, GoCoverageBlock[][]>();
+
+    for (const profile of profiles) {
+      if (profile.mode !== mode) {
+        throw new CoverageMergeError(
+          `Cannot merge coverage profiles with different modes: ${mode} and ${profile.mode}`
+        );
+      }
+
+      for (const [fileName, blocks] of Object.entries(profile.files)) {
+        byFile.set(fileName, [...(byFile.get(fileName) || []), blocks]);
+      }
+    }
+
+    const result: GoCoverageProfile = { mode, files: {} };
+    for (const [fileName, blockLists] of byFile.entries()) {
+      result.files[fileName] = this.mergeFile(fileName, blockLists, mode);
+    }
+
+    logger.info(
+      `Merged ${profiles.length} coverage profiles covering ${Object.keys(result.files).length} files`
+    );
+
+    return result;
+  }
+
+  /**
+   * Union profiles of the same source built under different configurations
+   * A block covered in any profile is covered in the union, e.g. a line only
+   * exercised on one Go version of a matrix. A file missing from some
+   * profiles (build constraints left it out there) keeps the blocks of the
+   * profiles that have it. A file whose blocks disagree between profiles, as
+   * when toolchains instrument it differently, cannot be unioned block by
+   * block: the profile covering most of its statements is kept and the file
+   * is reported as a conflict.
+   * @param profiles Profiles to union; profiles with different modes are unioned in set mode
+   * @returns The union, and the files kept from a single profile
+   */
+  unionProfiles(...profiles: GoCoverageProfile[]): { profile: GoCoverageProfile; conflicts: string[] } {
+    const mode: GoCoverageMode = profiles.length > 0 && profiles.every(p => p.mode === profiles[0].mode)
+      ? profiles[0].mode
+      : 'set';
+    const byFile = new Map<string, GoCoverageBlock[][]>();
+
+    for (const profile of profiles) {
+      for (const [fileName, blocks] of Object.entries(profile.files)) {
+        const normalized = mode === profile.mode ? blocks : blocks.map(b => ({ ...b, count: Math.min(b.count, 1) }));
+        byFile.set(fileName, [...(byFile.get(fileName) || []), normalized]);
+      }
+    }
+
+    const result: GoCoverageProfile = { mode, files: {} };
+    const conflicts: string[] = [];
+    for (const [fileName, blockLists] of byFile.entries()) {
+      try {
+        result.files[fileName] = this.mergeFile(fileName, blockLists, mode);
+      } catch (error) {
+        if (!(error instanceof CoverageMergeError)) {
+          throw error;
+        }
+        logger.warn(`${error.message} Keeping the most covered profile of the file.`);
+        const covered = (blocks: GoCoverageBlock[]) => this.summarize(blocks).covered;
+        result.files[fileName] = [...blockLists.reduce((best, b) => (covered(b) > covered(best) ? b : best))]
+          .sort(this.compareBlocks);
+        conflicts.push(fileName);
+      }
+    }
+
+    return { profile: result, conflicts: conflicts.sort() };
+  }
+
+  /**
+   * Statements in a profile and how many of them ran
+   * @returns Counts and the covered percentage (0-100; 0 for an empty profile)
+   */
+  statementCoverage(profile: GoCoverageProfile): { covered: number; total: number; percentage: number } {
+    const { covered, total } = this.summarize(Object.values(profile.files).flat());
+    return { covered, total, percentage: total > 0 ? (covered / total) * 100 : 0 };
+  }
+
+  /**
+   * Read and merge multiple coverage profile files
+   * @param profilePaths Paths to coverage.out files
+   * @returns Merged profile
+   */
+  async mergeProfileFiles(profilePaths: string[]): Promise<GoCoverageProfile> {
+    const profiles = await Promise.all(profilePaths.map(p => this.readProfile(p)));
+    return this.mergeProfiles(...profiles);
+  }
+
+  /**
+   * Merge one file's blocks from several profiles
+   * @throws CoverageMergeError if the profiles disagree on block boundaries
+   */
+  private mergeFile(fileName: string, blockLists: GoCoverageBlock[][], mode: GoCoverageMode): GoCoverageBlock[] {
+    const fileBlocks = new Map<string, GoCoverageBlock>();
+
+    for (const blocks of blockLists) {
+      for (const block of blocks) {
+        const startKey = `${block.start_line}.${block.start_col}`;
+        const existing = fileBlocks.get(startKey);
+
+        if (!existing) {
+          fileBlocks.set(startKey, { ...block });
+          continue;
+        }
+
+        if (
+          existing.end_line !== block.end_line ||
+          existing.end_col !== block.end_col ||
+          existing.num_statements !== block.num_statements
+        ) {
+          throw new CoverageMergeError(
+            `Conflicting coverage blocks for ${fileName} at ${startKey}: ` +
+            `${this.describeBlock(existing)} vs ${this.describeBlock(block)}. ` +
+            'The profiles were likely produced from different source.'
+          );
+        }
+
+        existing.count = mode === 'set'
+          ? Math.max(existing.count, block.count)
+          : existing.count + block.count;
+      }
+    }
+
+    const merged = Array.from(fileBlocks.values()).sort(this.compareBlocks);
+    this.assertNoOverlap(fileName, merged);
+    return merged;
+  }
+
+  private summarize(blocks: GoCoverageBlock[]): { covered: number; total: number } {
+    let covered = 0;
+    let total = 0;
+    for (const block of blocks) {
+      total += block.num_statements;
+      if (block.count > 0) {
+        covered += block.num_statements;
+      }
+    }
+    return { covered, total };
+  }
+
+  /**
+   * Order blocks by start position
+   */
+  private compareBlocks(a: GoCoverageBlock, b: GoCoverageBlock): number {
+    return a.start_line - b.start_line || a.start_col - b.start_col;
+  }
+
+  /**
+   * Ensure sorted blocks do not overlap
+   * Overlapping blocks with different starts mean the shards saw different source.
+   */
+  private assertNoOverlap(fileName: string, blocks: GoCoverageBlock[]): void {
+    for (let i = 1; i < blocks.length; i++) {
+      const prev = blocks[i - 1];
+      const cur = blocks[i];
+      const overlaps =
+        prev.end_line > cur.start_line ||
+        (prev.end_line === cur.start_line && prev.end_col > cur.start_col);
+
+      if (overlaps) {
+        throw new CoverageMergeError(
+          `Overlapping coverage blocks for ${fileName}: ` +
+          `${this.describeBlock(prev)} and ${this.describeBlock(cur)}. ` +
+          'The profiles were likely produced from different source.'
+        );
+      }
+    }
+  }
+
+  private describeBlock(block: GoCoverageBlock): string {
+    return `${block.start_line}.${block.start_col},${block.end_line}.${block.end_col}`;
+  }
+}
+
+// Export singleton instance
+export const coverageProfileService = new CoverageProfileService();
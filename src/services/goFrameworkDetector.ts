@@ -0,0 +1,60 @@
+/**
+ * Go Framework Detector
+ *
+ * Decides how a Go package's tests should be run by inspecting its test
+ * sources: packages importing Ginkgo or calling RunSpecs go to the Ginkgo
+ * runner, everything else (plain testing, testify suites) to go test.
+ * Per-package overrides take precedence when the heuristic guesses wrong.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { GoFramework } from '../types/mcp';
+import { globToRegExp } from '../utils/globMatcher';
+
+const GINKGO_IMPORT = /"github\.com\/onsi\/ginkgo(?:\/v2)?(?:\/[^"]*)?"/;
+const RUN_SPECS_CALL = /\bRunSpecs\s*\(/;
+
+export class GoFrameworkDetector {
+  /**
+   * Detect the framework used by a package's tests
+   * @param pkgDir Package directory
+   * @returns Ginkgo when any test file uses it, otherwise Standard
+   * @throws If the directory cannot be read
+   */
+  async detect(pkgDir: string): Promise<GoFramework> {
+    const entries = await fs.readdir(pkgDir);
+    const testFiles = entries.filter(name => name.endsWith('_test.go')).sort();
+
+    for (const file of testFiles) {
+      const source = await fs.readFile(path.join(pkgDir, file), 'utf-8');
+      if (GINKGO_IMPORT.test(source) || RUN_SPECS_CALL.test(source)) {
+        return GoFramework.Ginkgo;
+      }
+    }
+
+    return GoFramework.Standard;
+  }
+
+  /**
+   * Resolve a package's framework, honoring overrides
+   * The most specific (longest) matching override pattern wins.
+   * @param importPath Package import path, matched against override globs
+   * @param pkgDir Package directory, inspected when no override matches
+   * @param overrides Import path glob -> framework
+   */
+  async resolve(
+    importPath: string,
+    pkgDir: string,
+    overrides: Record<string, GoFramework> = {}
+  ): Promise<GoFramework> {
+    const pattern = Object.keys(overrides)
+      .filter(p => globToRegExp(p).test(importPath))
+      .sort((a, b) => b.length - a.length)[0];
+
+    return pattern !== undefined ? overrides[pattern] : this.detect(pkgDir);
+  }
+}
+
+// Export singleton instance
+export const goFrameworkDetector = new GoFrameworkDetector();
@@ -0,0 +1,221 @@
+/**
+ * Go Package Selector
+ *
+ * Incremental test selection for Go modules.
+ * Maps changed files to their owning packages and walks the reverse import
+ * graph so only packages affected by a change are tested.
+ */
+
+import { execFile } from 'child_process';
+import { promisify } from 'util';
+import * as path from 'path';
+import { logger } from './loggerService';
+
+const execFileAsync = promisify(execFile);
+
+// Module and workspace files; changing one can change every package's dependencies
+const MODULE_FILES = new Set(['go.mod', 'go.sum', 'go.work', 'go.work.sum']);
+
+/**
+ * go command flag for a set of build tags; empty when there are none
+ * Every go list and go test of a run takes the same flag, so discovery and
+ * execution see the same files.
+ */
+export function buildTagFlags(tags?: string[]): string[] {
+  const set = (tags || []).map(t => t.trim()).filter(Boolean);
+  return set.length > 0 ? [`-tags=${set.join(',')}`] : [];
+}
+
+export interface GoPackageInfo {
+  ImportPath: string;
+  Name?: string;
+  Dir: string;
+  Imports?: string[];
+  TestImports?: string[];
+  XTestImports?: string[];
+  GoFiles?: string[];
+  CgoFiles?: string[];
+  TestGoFiles?: string[];
+  XTestGoFiles?: string[];
+  EmbedFiles?: string[];
+  TestEmbedFiles?: string[];
+  XTestEmbedFiles?: string[];
+}
+
+export class GoPackageSelector {
+  /**
+   * List files changed since a git ref
+   * @param moduleRoot Module root (inside a git checkout)
+   * @param ref Git ref to diff against, e.g. origin/main
+   * @returns Changed file paths relative to the module root
+   */
+  async changedFilesSince(moduleRoot: string, ref: string): Promise<string[]> {
+    const { stdout } = await execFileAsync(
+      'git',
+      ['diff', '--name-only', '--relative', `${ref}...HEAD`],
+      { cwd: moduleRoot, maxBuffer: 10 * 1024 * 1024 }
+    );
+
+    return stdout.split('\n').map(l => l.trim()).filter(Boolean);
+  }
+
+  /**
+   * Compute packages affected by a set of changed files
+   * @param moduleRoot Module root directory (containing go.mod)
+   * @param changed Changed file paths, relative to moduleRoot or absolute
+   * @param tags Build tags, so files behind a build constraint map to their package
+   * @returns Sorted import paths of changed packages and their transitive dependents
+   */
+  async affectedPackages(moduleRoot: string, changed: string[], tags: string[] = []): Promise<string[]> {
+    const packages = await this.listPackages(moduleRoot, tags);
+    return this.resolveAffected(packages, changed, moduleRoot);
+  }
+
+  /**
+   * List packages in the module with their imports
+   * @param tags Build tags; file lists and imports only include files they enable
+   * @param patterns Package patterns to list (default: every package in the module)
+   */
+  async listPackages(moduleRoot: string, tags: string[] = [], patterns: string[] = ['./...']): Promise<GoPackageInfo[]> {
+    const { stdout } = await execFileAsync('go', ['list', '-e', '-json', ...buildTagFlags(tags), ...patterns], {
+      cwd: moduleRoot,
+      maxBuffer: 50 * 1024 * 1024,
+    });
+
+    return this.parseJsonStream(stdout) as GoPackageInfo[];
+  }
+
+  /**
+   * Resolve affected packages from a package list
+   * Changes propagate through regular imports. A package that only imports a
+   * changed package from its tests is affected, but its own dependents are not.
+   * A change to go.mod, go.sum or go.work affects every package.
+   */
+  resolveAffected(packages: GoPackageInfo[], changed: string[], moduleRoot: string): string[] {
+    const moduleFile = changed.find(file => MODULE_FILES.has(path.basename(file)));
+    if (moduleFile) {
+      logger.info(`${moduleFile} changed; all ${packages.length} packages are affected`);
+      return packages.map(p => p.ImportPath).sort();
+    }
+
+    const byImportPath = new Map(packages.map(p => [p.ImportPath, p]));
+
+    // Reverse edges: import path -> packages importing it
+    const importedBy = new Map<string, string[]>();
+    const testImportedBy = new Map<string, string[]>();
+    for (const pkg of packages) {
+      for (const imp of pkg.Imports || []) {
+        if (byImportPath.has(imp)) {
+          importedBy.set(imp, [...(importedBy.get(imp) || []), pkg.ImportPath]);
+        }
+      }
+      for (const imp of [...(pkg.TestImports || []), ...(pkg.XTestImports || [])]) {
+        if (byImportPath.has(imp) && imp !== pkg.ImportPath) {
+          testImportedBy.set(imp, [...(testImportedBy.get(imp) || []), pkg.ImportPath]);
+        }
+      }
+    }
+
+    const affected = new Set<string>();
+    const queue: string[] = [];
+
+    for (const file of changed) {
+      const owner = this.findOwningPackage(packages, path.resolve(moduleRoot, file));
+      if (!owner) {
+        logger.debug(`Changed file ${file} does not belong to any package`);
+        continue;
+      }
+      if (!affected.has(owner.ImportPath)) {
+        affected.add(owner.ImportPath);
+        queue.push(owner.ImportPath);
+      }
+    }
+
+    const propagated = new Set<string>();
+    while (queue.length > 0) {
+      const current = queue.shift()!;
+      if (propagated.has(current)) {
+        continue;
+      }
+      propagated.add(current);
+
+      for (const dependent of importedBy.get(current) || []) {
+        affected.add(dependent);
+        queue.push(dependent);
+      }
+      for (const dependent of testImportedBy.get(current) || []) {
+        affected.add(dependent);
+      }
+    }
+
+    const result = Array.from(affected).sort();
+    logger.info(`${changed.length} changed files affect ${result.length} of ${packages.length} packages`);
+    return result;
+  }
+
+  /**
+   * Find the package whose directory most closely encloses a file
+   * Non-Go files (testdata, embedded assets) belong to the nearest package above them.
+   */
+  private findOwningPackage(packages: GoPackageInfo[], absFile: string): GoPackageInfo | undefined {
+    let best: GoPackageInfo | undefined;
+
+    for (const pkg of packages) {
+      const rel = path.relative(pkg.Dir, absFile);
+      if (rel.startsWith('..') || path.isAbsolute(rel)) {
+        continue;
+      }
+      if (!best || pkg.Dir.length > best.Dir.length) {
+        best = pkg;
+      }
+    }
+
+    return best;
+  }
+
+  /**
+   * Parse a stream of concatenated JSON objects (as printed by go list -json)
+   */
+  private parseJsonStream(stdout: string): any[] {
+    const objects: any[] = [];
+    let depth = 0;
+    let start = -1;
+    let inString = false;
+    let escaped = false;
+
+    for (let i = 0; i < stdout.length; i++) {
+      const ch = stdout[i];
+
+      if (inString) {
+        if (escaped) {
+          escaped = false;
+        } else if (ch === '\\') {
+          escaped = true;
+        } else if (ch === '"') {
+          inString = false;
+        }
+        continue;
+      }
+
+      if (ch === '"') {
+        inString = true;
+      } else if (ch === '{') {
+        if (depth === 0) {
+          start = i;
+        }
+        depth++;
+      } else if (ch === '}') {
+        depth--;
+        if (depth === 0 && start >= 0) {
+          objects.push(JSON.parse(stdout.slice(start, i + 1)));
+          start = -1;
+        }
+      }
+    }
+
+    return objects;
+  }
+}
+
+// Export singleton instance
+export const goPackageSelector = new GoPackageSelector();
@@ -0,0 +1,222 @@
+/**
+ * Goroutine Leak Check
+ *
+ * Finds goroutines a test package leaves running, in the spirit of
+ * go.uber.org/goleak but without changes to the tested code. Each checked
+ * package gets a generated TestMain, added through `go test -overlay` so the
+ * workspace is never modified, which records the running goroutines before
+ * m.Run and reports the ones started since that are still alive afterwards:
+ *
+ *   alcs: goroutine leak check: 1 goroutines leaked
+ *   goroutine 21 [chan receive]:
+ *   example.com/mod/worker.(*Pool).loop(0xc000120000)
+ *   	/src/worker/pool.go:42 +0x5d
+ *   created by example.com/mod/worker.Start in goroutine 7
+ *   	/src/worker/pool.go:18 +0x8a
+ *
+ *   alcs: end goroutine leak check
+ *
+ * A leak fails the package. Packages that define their own TestMain cannot
+ * take the generated one and are skipped with a warning.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { GoroutineLeak } from '../types/mcp';
+import { GoPackageInfo } from './goPackageSelector';
+import { logger } from './loggerService';
+import { matchesAnyGlob } from '../utils/globMatcher';
+
+export const LEAK_CHECK_FILE = 'zz_alcs_leakcheck_test.go';
+
+const BEGIN = 'alcs: goroutine leak check:';
+const END = 'alcs: end goroutine leak check';
+
+const TEST_MAIN = /^func\s+TestMain\s*\(/m;
+const GOROUTINE_HEADER = /^goroutine (\d+) \[([^\]]*)\]:$/;
+
+/**
+ * Go source of the generated TestMain
+ * Goroutines still winding down get a grace period, as goleak allows;
+ * signal handling goroutines the runtime starts on demand are not leaks.
+ * @param packageName Package clause of the package's internal tests
+ * @param graceMs How long leaked goroutines get to exit
+ */
+export function renderLeakCheck(packageName: string, graceMs: number = 1000): string {
+  return `// Code generated by alcs for the goroutine leak check. DO NOT EDIT.
+
+package ${packageName}
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	before := alcsGoroutines()
+	code := m.Run()
+
+	var leaked []string
+	for deadline := time.Now().Add(${graceMs} * time.Millisecond); ; time.Sleep(50 * time.Millisecond) {
+		leaked = leaked[:0]
+		for id, stack := range alcsGoroutines() {
+			if _, ok := before[id]; !ok {
+				leaked = append(leaked, stack)
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	if len(leaked) > 0 {
+		fmt.Printf("${BEGIN} %d goroutines leaked\\n", len(leaked))
+		for _, stack := range leaked {
+			fmt.Printf("%s\\n\\n", stack)
+		}
+		fmt.Println("${END}")
+		if code == 0 {
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+func alcsGoroutines() map[string]string {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	goroutines := map[string]string{}
+	for _, stack := range strings.Split(string(buf), "\\n\\n") {
+		stack = strings.TrimSpace(stack)
+		header := strings.SplitN(stack, " ", 3)
+		if len(header) < 3 || header[0] != "goroutine" || alcsRuntimeOwned(stack) {
+			continue
+		}
+		goroutines[header[1]] = stack
+	}
+	return goroutines
+}
+
+func alcsRuntimeOwned(stack string) bool {
+	for _, fn := range []string{"os/signal.signal_recv", "os/signal.loop", "runtime.ensureSigM", "runtime/trace.Start"} {
+		if strings.Contains(stack, "\\n"+fn+"(") || strings.Contains(stack, "\\n"+fn+".") {
+			return true
+		}
+	}
+	return false
+}
+`;
+}
+
+export class GoroutineLeakCheck {
+  /**
+   * Generate the TestMain of every package matching the patterns
+   * @param packages Packages of the run, as listed by go list
+   * @param patterns Import path globs of the packages to check
+   * @param outDir Where the generated files and overlay are written
+   * @returns Path of the -overlay file, or undefined when no package can be checked
+   */
+  async prepare(packages: GoPackageInfo[], patterns: string[], outDir: string): Promise<string | undefined> {
+    const replace: Record<string, string> = {};
+
+    for (const pkg of packages.filter(p => matchesAnyGlob(p.ImportPath, patterns))) {
+      const testFiles = [...(pkg.TestGoFiles || []), ...(pkg.XTestGoFiles || [])];
+      if (testFiles.length === 0 || !pkg.Name) {
+        continue;
+      }
+      if (await this.definesTestMain(pkg.Dir, testFiles)) {
+        logger.warn(`${pkg.ImportPath} defines its own TestMain; skipping its goroutine leak check`);
+        continue;
+      }
+
+      const generated = path.join(outDir, `${Object.keys(replace).length}_${LEAK_CHECK_FILE}`);
+      await fs.mkdir(outDir, { recursive: true });
+      await fs.writeFile(generated, renderLeakCheck(pkg.Name), 'utf-8');
+      replace[path.join(pkg.Dir, LEAK_CHECK_FILE)] = generated;
+    }
+
+    const count = Object.keys(replace).length;
+    if (count === 0) {
+      logger.warn('No package matching leak_check can be checked for goroutine leaks');
+      return undefined;
+    }
+
+    const overlay = path.join(outDir, 'overlay.json');
+    await fs.writeFile(overlay, JSON.stringify({ Replace: replace }, null, 2) + '\n', 'utf-8');
+    logger.info(`Checking ${count} packages for leaked goroutines`);
+    return overlay;
+  }
+
+  /**
+   * Leaked goroutines reported in a package's output, by goroutine ID
+   * @param output Output printed outside of any test
+   */
+  parse(output: string): Omit<GoroutineLeak, 'package'>[] {
+    const leaks: Omit<GoroutineLeak, 'package'>[] = [];
+    let report: string[] | undefined;
+
+    for (const line of output.split('\n')) {
+      if (line.startsWith(BEGIN)) {
+        report = [];
+      } else if (line.startsWith(END) && report) {
+        leaks.push(...this.parseStacks(report.join('\n')));
+        report = undefined;
+      } else if (report) {
+        report.push(line);
+      }
+    }
+
+    return leaks.sort((a, b) => a.goroutine - b.goroutine);
+  }
+
+  /**
+   * Stacks as printed by runtime.Stack, separated by blank lines
+   */
+  private parseStacks(text: string): Omit<GoroutineLeak, 'package'>[] {
+    const leaks: Omit<GoroutineLeak, 'package'>[] = [];
+
+    for (const block of text.split(/\n\s*\n/)) {
+      const lines = block.trim().split('\n');
+      const header = lines[0].match(GOROUTINE_HEADER);
+      if (!header) {
+        continue;
+      }
+
+      const createdBy = lines.find(l => l.startsWith('created by '));
+      leaks.push({
+        goroutine: Number(header[1]),
+        state: header[2],
+        function: (lines[1] || '').replace(/\([^()]*\)$/, ''), // Without the argument list
+        created_by: createdBy?.slice('created by '.length).replace(/ in goroutine \d+$/, ''),
+        stack: lines.join('\n'),
+      });
+    }
+
+    return leaks;
+  }
+
+  private async definesTestMain(dir: string, files: string[]): Promise<boolean> {
+    for (const file of files) {
+      const source = await fs.readFile(path.join(dir, file), 'utf-8').catch(() => '');
+      if (TEST_MAIN.test(source)) {
+        return true;
+      }
+    }
+    return false;
+  }
+}
+
+// Export singleton instance
+export const goroutineLeakCheck = new GoroutineLeakCheck();
@@ -0,0 +1,115 @@
+/**
+ * Lint Service
+ *
+ * Runs golangci-lint over a Go module and normalizes its JSON report into
+ * LintFinding records that can be merged into a test execution result.
+ * golangci-lint exits 1 when it finds issues; only other exit codes (or
+ * output that is not a JSON report) are treated as a tool failure.
+ */
+
+import { execFile } from 'child_process';
+import { promisify } from 'util';
+import { LintConfig, LintFinding, LintSeverity } from '../types/mcp';
+import { logger } from './loggerService';
+
+const execFileAsync = promisify(execFile);
+
+// Issues found; the report on stdout is still complete
+const EXIT_ISSUES_FOUND = 1;
+
+const SEVERITY_RANK: Record<LintSeverity, number> = {
+  info: 0,
+  warning: 1,
+  error: 2,
+};
+
+export class LintService {
+  /**
+   * Run golangci-lint
+   * @param dir Module directory to lint
+   * @param cfg Config file pass-through and timeout
+   * @returns Findings in report order
+   * @throws If golangci-lint is missing, crashes, or produces no readable report
+   */
+  async run(dir: string, cfg: LintConfig = {}): Promise<LintFinding[]> {
+    const args = ['run', '--out-format', 'json'];
+    if (cfg.config_path) {
+      args.push('--config', cfg.config_path);
+    }
+    if (cfg.timeout_seconds) {
+      args.push(`--timeout=${cfg.timeout_seconds}s`);
+    }
+    args.push('./...');
+
+    let stdout: string;
+    try {
+      ({ stdout } = await execFileAsync('golangci-lint', args, {
+        cwd: dir,
+        maxBuffer: 10 * 1024 * 1024,
+      }));
+    } catch (error: any) {
+      if (error.code !== EXIT_ISSUES_FOUND || !error.stdout) {
+        const detail = (error.stderr || '').trim() || error.message;
+        throw new Error(`golangci-lint failed (exit ${error.code}): ${detail}`);
+      }
+      stdout = error.stdout;
+    }
+
+    const findings = this.parseReport(stdout);
+    logger.info(`golangci-lint reported ${findings.length} findings in ${dir}`);
+    return findings;
+  }
+
+  /**
+   * Parse the `--out-format json` report
+   * @param stdout golangci-lint stdout
+   * @returns Normalized findings
+   */
+  parseReport(stdout: string): LintFinding[] {
+    let report: any;
+    try {
+      report = JSON.parse(stdout);
+    } catch (error: any) {
+      throw new Error(`Unreadable golangci-lint report: ${error.message}`);
+    }
+
+    return (report.Issues || []).map((issue: any): LintFinding => ({
+      linter: issue.FromLinter || 'unknown',
+      file: issue.Pos?.Filename || '',
+      line: issue.Pos?.Line || 0,
+      col: issue.Pos?.Column || 0,
+      message: issue.Text || '',
+      severity: this.mapSeverity(issue.Severity),
+    }));
+  }
+
+  /**
+   * Findings at or above a severity threshold
+   * @param findings Findings to filter
+   * @param threshold Minimum severity
+   */
+  atOrAbove(findings: LintFinding[], threshold: LintSeverity): LintFinding[] {
+    return findings.filter(f => SEVERITY_RANK[f.severity] >= SEVERITY_RANK[threshold]);
+  }
+
+  /**
+   * Map golangci-lint severities to ours
+   * Issues carry no severity unless the config sets one; golangci-lint
+   * treats those as failures, so they count as errors.
+   */
+  private mapSeverity(severity: string | undefined): LintSeverity {
+    switch ((severity || '').toLowerCase()) {
+      case 'info':
+      case 'hint':
+        return 'info';
+      case 'warning':
+      case 'warn':
+        return 'warning';
+      default:
+        return 'error';
+    }
+  }
+}
+
+// Export singleton instance
+export const lintService = new LintService();
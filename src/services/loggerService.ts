@@ -10,6 +10,7 @@ import 'winston-daily-rotate-file';
 import { configManager } from './configService';
 import path from 'path';
 import fs from 'fs';
+import { colorEnabled, stripAnsi } from '../utils/terminalColor';
 
 // Ensure the log directory exists
 const logDirectory = path.dirname(configManager.config.log_path);
@@ -35,7 +36,8 @@ const jsonFormat = winston.format.combine(
     const log: any = {
       timestamp: info.timestamp,
       level: info.level,
-      message: info.message,
+      // Rendered tables may be colored for the console; files stay plain
+      message: typeof info.message === 'string' ? stripAnsi(info.message) : info.message,
       service: SERVICE_NAME,
       version: SERVICE_VERSION,
       environment: NODE_ENV,
@@ -57,10 +59,11 @@ const jsonFormat = winston.format.combine(
 
 /**
  * Human-readable format for development console
+ * Levels are colored only on a terminal and when NO_COLOR is unset.
  */
 const consoleFormat = winston.format.combine(
   winston.format.timestamp({ format: 'YYYY-MM-DD HH:mm:ss' }),
-  winston.format.colorize(),
+  ...(colorEnabled('auto', process.stdout) ? [winston.format.colorize()] : []),
   winston.format.printf(info => {
     const metadata = info.metadata && Object.keys(info.metadata).length > 0
       ? ` ${JSON.stringify(info.metadata)}`
@@ -29,6 +29,8 @@ export class MetricsService {
   public testExecutionsFailed: Counter;
   public testExecutionDuration: Histogram;
   public testCoverage: Histogram;
+  public testExecutionsInFlight: Gauge;
+  public testLastRunCoverage: Gauge;
 
   // Static Analysis Metrics
   public staticAnalysisTotal: Counter;
@@ -48,14 +50,15 @@ export class MetricsService {
   // System Metrics
   public requestsTotal: Counter;
   public dockerContainerFailures: Counter;
+  public dockerContainersActive: Gauge;
 
   // Database Metrics
   public databasePoolConnectionsInUse: Gauge;
   public databasePoolConnectionsMax: Gauge;
   public databaseQueryDuration: Histogram;
 
-  constructor() {
-    this.registry = register;
+  constructor(registry: Registry = register) {
+    this.registry = registry;
 
     // Session Metrics
     this.sessionsTotal = new Counter({
@@ -154,6 +157,19 @@ export class MetricsService {
       registers: [this.registry]
     });
 
+    this.testExecutionsInFlight = new Gauge({
+      name: 'alcs_test_executions_in_flight',
+      help: 'Number of test executions currently running',
+      registers: [this.registry]
+    });
+
+    this.testLastRunCoverage = new Gauge({
+      name: 'alcs_test_last_run_coverage_percentage',
+      help: 'Coverage percentage of the most recent test execution',
+      labelNames: ['framework', 'language'],
+      registers: [this.registry]
+    });
+
     // Static Analysis Metrics
     this.staticAnalysisTotal = new Counter({
       name: 'alcs_static_analysis_total',
@@ -237,6 +253,12 @@ export class MetricsService {
       registers: [this.registry]
     });
 
+    this.dockerContainersActive = new Gauge({
+      name: 'alcs_docker_containers_active',
+      help: 'Number of ALCS test containers currently running',
+      registers: [this.registry]
+    });
+
     // Database Metrics
     this.databasePoolConnectionsInUse = new Gauge({
       name: 'alcs_database_pool_connections_in_use',
@@ -339,6 +361,21 @@ export class MetricsService {
 
     this.testExecutionDuration.observe({ framework, language }, durationSeconds);
     this.testCoverage.observe({ framework, language }, coverage);
+    this.testLastRunCoverage.set({ framework, language }, coverage);
+  }
+
+  /**
+   * Record test execution start
+   */
+  recordTestExecutionStart(): void {
+    this.testExecutionsInFlight.inc();
+  }
+
+  /**
+   * Record test execution end (success or failure)
+   */
+  recordTestExecutionEnd(): void {
+    this.testExecutionsInFlight.dec();
   }
 
   /**
@@ -415,6 +452,20 @@ export class MetricsService {
     this.dockerContainerFailures.inc({ reason });
   }
 
+  /**
+   * Record Docker container started
+   */
+  recordContainerStarted(): void {
+    this.dockerContainersActive.inc();
+  }
+
+  /**
+   * Record Docker container removed
+   */
+  recordContainerRemoved(): void {
+    this.dockerContainersActive.dec();
+  }
+
   /**
    * Update database pool metrics
    */
@@ -0,0 +1,125 @@
+/**
+ * Race Report Parser
+ *
+ * Extracts `WARNING: DATA RACE` blocks printed by binaries built with -race.
+ * Format:
+ *   ==================
+ *   WARNING: DATA RACE
+ *   Write at 0x00c0000a4010 by goroutine 8:
+ *     example.com/calc.(*Counter).Inc()
+ *         /src/calc/counter.go:12 +0x44
+ *
+ *   Previous read at 0x00c0000a4010 by goroutine 7:
+ *     ...
+ *
+ *   Goroutine 8 (running) created at:
+ *     ...
+ *   ==================
+ */
+
+import { DataRace, RaceAccess } from '../types/mcp';
+
+const RACE_HEADER = 'WARNING: DATA RACE';
+const RACE_SEPARATOR = /^==================\s*$/;
+const ACCESS_LINE = /^(Previous )?((?:atomic )?(?:read|write)) at (0x[0-9a-fA-F]+) by (main goroutine|goroutine (\d+)):\s*$/i;
+
+export class RaceReportParser {
+  /**
+   * Parse every data race report in a chunk of test output
+   * @param output Test output (may contain other log lines and assertion failures)
+   * @returns Parsed races in the order they were reported
+   */
+  parse(output: string): DataRace[] {
+    if (!output.includes(RACE_HEADER)) {
+      return [];
+    }
+
+    const races: DataRace[] = [];
+    const lines = output.split('\n');
+
+    for (let i = 0; i < lines.length; i++) {
+      if (lines[i].trim() !== RACE_HEADER) {
+        continue;
+      }
+
+      // The block runs until the closing separator (or end of output if truncated)
+      let end = i + 1;
+      while (end < lines.length && !RACE_SEPARATOR.test(lines[end].trim())) {
+        end++;
+      }
+
+      const race = this.parseBlock(lines.slice(i, end));
+      if (race) {
+        races.push(race);
+      }
+      i = end;
+    }
+
+    return races;
+  }
+
+  /**
+   * Parse one block starting at the WARNING line
+   */
+  private parseBlock(block: string[]): DataRace | undefined {
+    let current: RaceAccess | undefined;
+    let previous: RaceAccess | undefined;
+    let address = '';
+    let section: { access: RaceAccess; frames: string[] } | undefined;
+
+    const closeSection = () => {
+      if (section) {
+        section.access.stack = this.dedent(section.frames);
+      }
+      section = undefined;
+    };
+
+    for (const line of block.slice(1)) {
+      const match = line.trim().match(ACCESS_LINE);
+
+      if (match) {
+        closeSection();
+        const access: RaceAccess = {
+          operation: match[2].toLowerCase(),
+          goroutine: match[5] || 'main',
+          stack: '',
+        };
+        if (match[1]) {
+          previous = access;
+        } else {
+          current = access;
+          address = match[3];
+        }
+        section = { access, frames: [] };
+      } else if (/^Goroutine \d+ /.test(line.trim())) {
+        // Creation stacks follow the two accesses; not part of either access
+        closeSection();
+      } else if (section && line.trim()) {
+        section.frames.push(line);
+      }
+    }
+    closeSection();
+
+    if (!current || !previous) {
+      return undefined;
+    }
+
+    return {
+      address,
+      current,
+      previous,
+      raw: block.join('\n').trim(),
+    };
+  }
+
+  /**
+   * Strip the common leading indentation from stack frames
+   */
+  private dedent(lines: string[]): string {
+    const indent = Math.min(...lines.map(l => l.match(/^\s*/)![0].length));
+    return lines.map(l => l.slice(indent)).join('\n');
+  }
+}
+
+// Export singleton instance
+export const raceReportParser = new RaceReportParser();
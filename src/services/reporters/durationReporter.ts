@@ -0,0 +1,116 @@
+/**
+ * Duration Reporter
+ *
+ * Aggregates test durations into a DurationReport: the slowest tests of the
+ * whole run and each package's total test time, so it is clear whether one
+ * package dominates a slow suite or the time is spread out. Only top-level
+ * tests are counted; a subtest's time is already part of its parent's.
+ */
+
+import { DurationReport, PackageDuration, TestCaseResult } from '../../types/mcp';
+import { createPalette, Palette } from '../../utils/terminalColor';
+
+export const DEFAULT_SLOWEST = 10;
+
+export interface DurationTextOptions {
+  color?: boolean;            // Bold headers, failed tests in red
+}
+
+/**
+ * Build the report
+ * Package-level pseudo-tests ([build failed], [package], ...) are left out.
+ * @param tests Every test of the run
+ * @param slowest How many tests to list
+ */
+export function buildDurationReport(tests: TestCaseResult[], slowest: number = DEFAULT_SLOWEST): DurationReport {
+  const topLevel = tests.filter(t => !t.name.includes('/') && !t.name.startsWith('['));
+  const total = topLevel.reduce((sum, t) => sum + t.duration_ms, 0);
+
+  const packages = new Map<string, PackageDuration>();
+  for (const test of topLevel) {
+    const pkg = packages.get(test.package) || { package: test.package, duration_ms: 0, tests: 0, percentage: 0 };
+    pkg.duration_ms += test.duration_ms;
+    pkg.tests++;
+    packages.set(test.package, pkg);
+  }
+  for (const pkg of packages.values()) {
+    pkg.percentage = total === 0 ? 0 : (pkg.duration_ms / total) * 100;
+  }
+
+  const byDuration = <T extends { package: string; duration_ms: number }>(a: T, b: T) =>
+    b.duration_ms - a.duration_ms || a.package.localeCompare(b.package);
+
+  return {
+    total_ms: total,
+    slowest: [...topLevel]
+      .sort((a, b) => byDuration(a, b) || a.name.localeCompare(b.name))
+      .slice(0, Math.max(0, slowest))
+      .map(t => ({ package: t.package, name: t.name, status: t.status, duration_ms: t.duration_ms })),
+    packages: Array.from(packages.values()).sort(byDuration),
+  };
+}
+
+export class DurationReporter {
+  /**
+   * Render the report as two text tables, slowest tests then packages
+   */
+  renderText(report: DurationReport, options: DurationTextOptions = {}): string {
+    if (report.slowest.length === 0 && report.packages.length === 0) {
+      return 'No test durations recorded\n';
+    }
+
+    const palette = createPalette(Boolean(options.color));
+    const tests = this.table(
+      ['Duration', 'Package', 'Test'],
+      report.slowest.map(t => [this.formatDuration(t.duration_ms), t.package, t.name]),
+      [0],
+      palette,
+      report.slowest.map(t => (t.status === 'passed' || t.status === 'skipped' ? undefined : palette.red))
+    );
+    const packages = this.table(
+      ['Duration', 'Share', 'Tests', 'Package'],
+      report.packages.map(p => [this.formatDuration(p.duration_ms), `${p.percentage.toFixed(1)}%`, String(p.tests), p.package]),
+      [0, 1, 2],
+      palette
+    );
+
+    return [
+      `Slowest ${report.slowest.length} tests:`,
+      tests,
+      `Test time by package (${this.formatDuration(report.total_ms)} total):`,
+      packages,
+    ].join('\n');
+  }
+
+  /**
+   * Format a duration the way go test prints elapsed time
+   */
+  private formatDuration(ms: number): string {
+    return `${(ms / 1000).toFixed(2)}s`;
+  }
+
+  /**
+   * Align columns, then color: escape sequences would count toward the widths
+   */
+  private table(
+    header: string[],
+    rows: string[][],
+    rightAligned: number[],
+    palette: Palette,
+    rowColors: (((text: string) => string) | undefined)[] = []
+  ): string {
+    const widths = header.map((h, i) => Math.max(h.length, ...rows.map(r => r[i].length)));
+    const line = (cells: string[]) => cells
+      .map((c, i) => rightAligned.includes(i) ? c.padStart(widths[i]) : c.padEnd(widths[i]))
+      .join('  ')
+      .trimEnd();
+
+    return [
+      palette.bold(line(header)),
+      ...rows.map((row, i) => (rowColors[i] || (text => text))(line(row))),
+    ].join('\n') + '\n';
+  }
+}
+
+// Export singleton instance
+export const durationReporter = new DurationReporter();
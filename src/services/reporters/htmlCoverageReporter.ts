@@ -0,0 +1,314 @@
+/**
+ * HTML Coverage Reporter
+ *
+ * Renders a RunSummary's merged Go coverage profile as browsable, self-contained HTML:
+ * an index of packages sorted by coverage and one page per source file with
+ * covered/uncovered spans highlighted, similar to `go tool cover -html`.
+ * Lines where some blocks ran and others did not are marked as partial.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { GoCoverageBlock, RunSummary } from '../../types/mcp';
+import { logger } from '../loggerService';
+
+/**
+ * Read-only source tree keyed by the file names used in the profile
+ * (e.g. example.com/mod/pkg/file.go)
+ */
+export interface SourceFS {
+  readFile(fileName: string): Promise<string>;
+}
+
+export type LineCoverage = 'covered' | 'uncovered' | 'partial' | 'none';
+
+interface FileSummary {
+  fileName: string;
+  page: string;
+  covered: number;
+  total: number;
+}
+
+type Mark = 'cov' | 'uncov' | undefined;
+
+const STYLE = `
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+td, th { padding: 2px 12px; text-align: left; }
+th { border-bottom: 1px solid #ccc; }
+.pct { text-align: right; font-variant-numeric: tabular-nums; }
+pre { font-family: monospace; font-size: 13px; line-height: 1.4; margin: 0; }
+.line { white-space: pre; }
+.line::before { content: attr(data-n); display: inline-block; width: 4em; padding-right: 1em; color: #999; text-align: right; }
+.line.covered::before { border-right: 4px solid #2a2; }
+.line.uncovered::before { border-right: 4px solid #c33; }
+.line.partial::before { border-right: 4px solid #e90; }
+.cov { background: #dfd; }
+.uncov { background: #fdd; }
+`;
+
+export class HtmlCoverageReporter {
+  /**
+   * Write the HTML report
+   * @param dir Output directory (created if missing)
+   * @param summary Run summary; a run without a profile gets an empty index
+   * @param sources Source tree to read files from
+   * @returns Path of the generated index.html
+   */
+  async writeHtmlCoverage(dir: string, summary: RunSummary, sources: SourceFS): Promise<string> {
+    await fs.mkdir(dir, { recursive: true });
+    const profile = summary.coverage.profile || { mode: 'set', files: {} };
+
+    const summaries: FileSummary[] = [];
+    const usedPages = new Set<string>();
+
+    for (const fileName of Object.keys(profile.files).sort()) {
+      const blocks = profile.files[fileName];
+      const page = this.pageName(fileName, usedPages);
+      const { covered, total } = this.countStatements(blocks);
+
+      let source: string | undefined;
+      try {
+        source = await sources.readFile(fileName);
+      } catch (error: any) {
+        logger.warn(`Source for ${fileName} not available for coverage report: ${error.message}`);
+      }
+
+      await fs.writeFile(path.join(dir, page), this.renderFile(fileName, blocks, source), 'utf-8');
+      summaries.push({ fileName, page, covered, total });
+    }
+
+    const indexPath = path.join(dir, 'index.html');
+    await fs.writeFile(indexPath, this.renderIndex(summaries, profile.mode, summary), 'utf-8');
+    logger.info(`Wrote HTML coverage report for ${summaries.length} files to ${indexPath}`);
+
+    return indexPath;
+  }
+
+  /**
+   * Classify each source line by the blocks that cover it
+   * @param lineTexts Source lines
+   * @param blocks Coverage blocks for the file
+   * @returns Per-line classification (index 0 is line 1)
+   */
+  classifyLines(lineTexts: string[], blocks: GoCoverageBlock[]): LineCoverage[] {
+    return this.markColumns(lineTexts, blocks).map((marks, i) => this.classify(lineTexts[i], marks));
+  }
+
+  /**
+   * Render the package index, lowest coverage first
+   */
+  private renderIndex(files: FileSummary[], mode: string, summary: RunSummary): string {
+    const packages = new Map<string, FileSummary[]>();
+    for (const file of files) {
+      const pkg = path.posix.dirname(file.fileName);
+      packages.set(pkg, [...(packages.get(pkg) || []), file]);
+    }
+
+    const rows = Array.from(packages.entries())
+      .map(([pkg, pkgFiles]) => ({
+        pkg,
+        files: pkgFiles,
+        covered: pkgFiles.reduce((sum, f) => sum + f.covered, 0),
+        total: pkgFiles.reduce((sum, f) => sum + f.total, 0),
+      }))
+      .sort((a, b) => this.percent(a.covered, a.total) - this.percent(b.covered, b.total) || a.pkg.localeCompare(b.pkg));
+
+    const covered = files.reduce((sum, f) => sum + f.covered, 0);
+    const total = files.reduce((sum, f) => sum + f.total, 0);
+
+    const body = [
+      `<h1>Coverage: ${this.formatPercent(covered, total)}</h1>`,
+      `<p>${total} statements, mode: ${this.escape(mode)}</p>`,
+      `<p>Run ${summary.exit_status === 0 ? 'passed' : 'failed'}: ` +
+      `${summary.totals.passed}/${summary.totals.total} tests passed` +
+      `${summary.metadata.git_sha ? ` at ${this.escape(summary.metadata.git_sha.slice(0, 12))}` : ''}, ` +
+      `started ${this.escape(summary.metadata.started_at)}</p>`,
+      '<table>',
+      '<tr><th>Package / file</th><th class="pct">Coverage</th><th class="pct">Statements</th></tr>',
+    ];
+
+    for (const row of rows) {
+      body.push(
+        `<tr><th>${this.escape(row.pkg)}</th><th class="pct">${this.formatPercent(row.covered, row.total)}</th>` +
+        `<th class="pct">${row.covered}/${row.total}</th></tr>`
+      );
+      for (const file of row.files) {
+        body.push(
+          `<tr><td><a href="${this.escape(file.page)}">${this.escape(path.posix.basename(file.fileName))}</a></td>` +
+          `<td class="pct">${this.formatPercent(file.covered, file.total)}</td>` +
+          `<td class="pct">${file.covered}/${file.total}</td></tr>`
+        );
+      }
+    }
+
+    body.push('</table>');
+    return this.document('Coverage report', body.join('\n'));
+  }
+
+  /**
+   * Render one source file with coverage highlighting
+   */
+  renderFile(fileName: string, blocks: GoCoverageBlock[], source: string | undefined): string {
+    const { covered, total } = this.countStatements(blocks);
+    const header =
+      `<p><a href="index.html">&larr; index</a></p>\n` +
+      `<h1>${this.escape(fileName)}: ${this.formatPercent(covered, total)}</h1>`;
+
+    if (source === undefined) {
+      return this.document(fileName, `${header}\n<p>Source not available.</p>`);
+    }
+
+    const lineTexts = source.replace(/\n$/, '').split('\n');
+    const marks = this.markColumns(lineTexts, blocks);
+
+    const lines = lineTexts.map((text, i) => {
+      const status = this.classify(text, marks[i]);
+      const cls = status === 'none' ? 'line' : `line ${status}`;
+      return `<span class="${cls}" data-n="${i + 1}">${this.renderSpans(text, marks[i])}</span>`;
+    });
+
+    return this.document(fileName, `${header}\n<pre>${lines.join('')}</pre>`);
+  }
+
+  /**
+   * Mark every column covered by a block as hit or missed
+   * Profile columns are 1-based and the end column is exclusive.
+   */
+  private markColumns(lineTexts: string[], blocks: GoCoverageBlock[]): Mark[][] {
+    const marks: Mark[][] = lineTexts.map(text => new Array<Mark>(text.length).fill(undefined));
+
+    for (const block of blocks) {
+      if (block.num_statements === 0) {
+        continue;
+      }
+
+      const mark: Mark = block.count > 0 ? 'cov' : 'uncov';
+      for (let line = block.start_line; line <= block.end_line && line <= lineTexts.length; line++) {
+        const lineMarks = marks[line - 1];
+        const from = line === block.start_line ? block.start_col - 1 : 0;
+        const to = line === block.end_line ? Math.min(block.end_col - 1, lineMarks.length) : lineMarks.length;
+
+        for (let col = Math.max(from, 0); col < to; col++) {
+          lineMarks[col] = mark;
+        }
+      }
+    }
+
+    return marks;
+  }
+
+  /**
+   * A line is partial when its code is split between hit and missed blocks
+   * Whitespace is ignored so indentation never makes a line partial.
+   */
+  private classify(text: string, marks: Mark[]): LineCoverage {
+    let hit = false;
+    let missed = false;
+
+    for (let col = 0; col < text.length; col++) {
+      if (/\s/.test(text[col])) {
+        continue;
+      }
+      hit = hit || marks[col] === 'cov';
+      missed = missed || marks[col] === 'uncov';
+    }
+
+    if (hit && missed) {
+      return 'partial';
+    }
+    return hit ? 'covered' : missed ? 'uncovered' : 'none';
+  }
+
+  /**
+   * Wrap runs of equally-marked characters in spans
+   */
+  private renderSpans(text: string, marks: Mark[]): string {
+    let html = '';
+    let start = 0;
+
+    for (let col = 1; col <= text.length; col++) {
+      if (col === text.length || marks[col] !== marks[start]) {
+        const segment = this.escape(text.slice(start, col));
+        html += marks[start] ? `<span class="${marks[start]}">${segment}</span>` : segment;
+        start = col;
+      }
+    }
+
+    return html + '\n';
+  }
+
+  private countStatements(blocks: GoCoverageBlock[]): { covered: number; total: number } {
+    let covered = 0;
+    let total = 0;
+
+    for (const block of blocks) {
+      total += block.num_statements;
+      if (block.count > 0) {
+        covered += block.num_statements;
+      }
+    }
+
+    return { covered, total };
+  }
+
+  /**
+   * Flatten a profile file name into a unique page name
+   */
+  private pageName(fileName: string, used: Set<string>): string {
+    const base = fileName.replace(/[^A-Za-z0-9._-]+/g, '_');
+    let page = `${base}.html`;
+    for (let n = 2; used.has(page); n++) {
+      page = `${base}_${n}.html`;
+    }
+    used.add(page);
+    return page;
+  }
+
+  private percent(covered: number, total: number): number {
+    return total === 0 ? 100 : (covered / total) * 100;
+  }
+
+  private formatPercent(covered: number, total: number): string {
+    return `${this.percent(covered, total).toFixed(1)}%`;
+  }
+
+  private document(title: string, body: string): string {
+    return [
+      '<!DOCTYPE html>',
+      '<html><head><meta charset="utf-8">',
+      `<title>${this.escape(title)}</title>`,
+      `<style>${STYLE}</style>`,
+      '</head><body>',
+      body,
+      '</body></html>',
+      '',
+    ].join('\n');
+  }
+
+  private escape(value: string): string {
+    return value
+      .replace(/&/g, '&amp;')
+      .replace(/</g, '&lt;')
+      .replace(/>/g, '&gt;')
+      .replace(/"/g, '&quot;');
+  }
+}
+
+/**
+ * Source tree for a module checkout: strips the module path from profile file
+ * names and reads the remainder relative to the module root
+ * @param moduleRoot Directory containing go.mod
+ * @param modulePath Module path declared in go.mod, e.g. example.com/mod
+ */
+export function moduleSourceFS(moduleRoot: string, modulePath: string): SourceFS {
+  return {
+    readFile: async (fileName: string) => {
+      const rel = fileName.startsWith(`${modulePath}/`) ? fileName.slice(modulePath.length + 1) : fileName;
+      return fs.readFile(path.join(moduleRoot, rel), 'utf-8');
+    },
+  };
+}
+
+// Export singleton instance
+export const htmlCoverageReporter = new HtmlCoverageReporter();
@@ -0,0 +1,120 @@
+/**
+ * JSON Summary Reporter
+ *
+ * Builds the RunSummary for a finished run and writes it as the canonical
+ * machine-readable artifact. The JUnit and HTML reporters render from the
+ * same RunSummary, so every report agrees on what happened.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { BuildFailure, ChangedCoverageReport, GoCoverageProfile, RunSummary, ShuffleInfo, TestCaseResult, TestFramework } from '../../types/mcp';
+import { logger } from '../loggerService';
+import { buildDurationReport } from './durationReporter';
+
+// Conventional exit status for a run interrupted by SIGINT
+export const EXIT_CANCELLED = 130;
+
+// Bump on any change that could break existing consumers (renamed or removed fields)
+export const RUN_SUMMARY_SCHEMA_VERSION = 1;
+
+export interface RunSummaryInput {
+  framework: TestFramework;
+  startedAt: Date;
+  finishedAt: Date;
+  goVersion?: string;
+  gitSha?: string;
+  runId?: string;
+  tests: TestCaseResult[];
+  coveragePercentage: number;
+  coverageProfile?: GoCoverageProfile;
+  changedCoverage?: ChangedCoverageReport;
+  lintFindings?: number;
+  buildFailures?: BuildFailure[];
+  shuffle?: ShuffleInfo;
+  slowest?: number;
+  success: boolean;
+  cancelled?: boolean;
+  abortedEarly?: boolean;
+}
+
+/**
+ * Receives the RunSummary once a run finishes, e.g. to capture it in-process
+ */
+export interface SummaryHandler {
+  handleSummary(summary: RunSummary): void | Promise<void>;
+}
+
+interface SummaryOutput {
+  write(text: string): unknown;
+}
+
+/**
+ * Assemble a RunSummary from the results of a run
+ * @param input Run results
+ * @returns Summary stamped with the current schema version
+ */
+export function createRunSummary(input: RunSummaryInput): RunSummary {
+  const count = (...statuses: string[]) => input.tests.filter(t => statuses.includes(t.status)).length;
+
+  return {
+    schemaVersion: RUN_SUMMARY_SCHEMA_VERSION,
+    metadata: {
+      framework: input.framework,
+      started_at: input.startedAt.toISOString(),
+      finished_at: input.finishedAt.toISOString(),
+      duration_ms: input.finishedAt.getTime() - input.startedAt.getTime(),
+      go_version: input.goVersion,
+      git_sha: input.gitSha,
+      run_id: input.runId,
+    },
+    tests: input.tests,
+    totals: {
+      passed: count('passed'),
+      failed: count('failed', 'error', 'timed_out'),
+      skipped: count('skipped'),
+      cancelled: count('cancelled'),
+      not_run: count('not_run'),
+      panicked: input.tests.filter(t => t.panic).length,
+      total: input.tests.length,
+    },
+    coverage: {
+      percentage: input.coveragePercentage,
+      profile: input.coverageProfile,
+      changed: input.changedCoverage,
+    },
+    lint: {
+      findings_count: input.lintFindings || 0,
+    },
+    build_failures: input.buildFailures || [],
+    durations: buildDurationReport(input.tests, input.slowest),
+    shuffle: input.shuffle,
+    exit_status: input.cancelled ? EXIT_CANCELLED : input.success ? 0 : 1,
+    aborted_early: input.abortedEarly || undefined,
+  };
+}
+
+export class JsonSummaryReporter {
+  /**
+   * Serialize a summary to a stream
+   * @param out Destination, e.g. process.stdout
+   * @param summary Run summary
+   */
+  writeJson(out: SummaryOutput, summary: RunSummary): void {
+    out.write(JSON.stringify(summary, null, 2) + '\n');
+  }
+
+  /**
+   * Write a summary to disk
+   * @param outputPath Destination file path
+   * @param summary Run summary
+   */
+  async writeReport(outputPath: string, summary: RunSummary): Promise<void> {
+    await fs.mkdir(path.dirname(outputPath), { recursive: true });
+    await fs.writeFile(outputPath, JSON.stringify(summary, null, 2) + '\n', 'utf-8');
+    logger.info(`Wrote run summary (schema v${summary.schemaVersion}) to ${outputPath}`);
+  }
+}
+
+// Export singleton instance
+export const jsonSummaryReporter = new JsonSummaryReporter();
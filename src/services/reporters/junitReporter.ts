@@ -0,0 +1,138 @@
+/**
+ * JUnit Reporter
+ *
+ * Renders a RunSummary's per-test results as JUnit XML for CI dashboards (Jenkins, GitLab).
+ * Failures map to <failure>, skips and cancelled or unrun tests to <skipped>, and panics/build failures/timeouts to <error>;
+ * panics and build failures use type="panic" and type="build" so dashboards can tell them from a failing test.
+ * Data races are reported as additional <error type="data_race"> elements.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { RunSummary, TestCaseResult } from '../../types/mcp';
+import { logger } from '../loggerService';
+
+export class JUnitReporter {
+  /**
+   * Generate a JUnit XML document
+   * @param summary Run summary
+   * @param suiteName Name of the top-level <testsuite>
+   * @returns JUnit XML content
+   */
+  generate(summary: RunSummary, suiteName: string = 'alcs'): string {
+    const results = summary.tests;
+    const failures = results.filter(r => r.status === 'failed').length;
+    const errors = results.filter(r => r.status === 'error' || r.status === 'timed_out').length;
+    const skipped = results.filter(r => r.status === 'skipped' || r.status === 'cancelled' || r.status === 'not_run').length;
+    const totalMs = results.reduce((sum, r) => sum + r.duration_ms, 0);
+
+    const lines = [
+      '<?xml version="1.0" encoding="UTF-8"?>',
+      `<testsuite name="${this.escape(suiteName)}" tests="${results.length}" ` +
+      `failures="${failures}" errors="${errors}" skipped="${skipped}" ` +
+      `time="${this.formatSeconds(totalMs)}" timestamp="${summary.metadata.started_at}">`,
+    ];
+
+    for (const result of results) {
+      lines.push(...this.renderTestCase(result));
+    }
+
+    lines.push('</testsuite>');
+    return lines.join('\n') + '\n';
+  }
+
+  /**
+   * Write a JUnit XML report to disk
+   * @param outputPath Destination file path
+   * @param summary Run summary
+   * @param suiteName Name of the top-level <testsuite>
+   */
+  async writeReport(
+    outputPath: string,
+    summary: RunSummary,
+    suiteName: string = 'alcs'
+  ): Promise<void> {
+    await fs.mkdir(path.dirname(outputPath), { recursive: true });
+    await fs.writeFile(outputPath, this.generate(summary, suiteName), 'utf-8');
+    logger.info(`Wrote JUnit report with ${summary.tests.length} test cases to ${outputPath}`);
+  }
+
+  /**
+   * Render a single <testcase> element
+   */
+  private renderTestCase(result: TestCaseResult): string[] {
+    const open =
+      `  <testcase classname="${this.escape(result.package)}" name="${this.escape(result.name)}" ` +
+      `time="${this.formatSeconds(result.duration_ms)}"`;
+    const children = [...this.renderOutcome(result), ...this.renderDataRaces(result)];
+
+    if (children.length === 0) {
+      return [`${open}/>`];
+    }
+
+    return [`${open}>`, ...children, '  </testcase>'];
+  }
+
+  /**
+   * Render the element describing a non-passing outcome
+   */
+  private renderOutcome(result: TestCaseResult): string[] {
+    const message = this.escape(result.failure_message || '');
+    const body = this.escape(result.output);
+
+    switch (result.status) {
+      case 'failed':
+        return [`    <failure message="${message}" type="failure">${body}</failure>`];
+      case 'error':
+        return [`    <error message="${message}" type="${this.errorType(result)}">${body}</error>`];
+      case 'timed_out':
+        return [`    <error message="${message}" type="timeout">${this.escape(result.goroutine_dump || result.output)}</error>`];
+      case 'skipped':
+        return [`    <skipped message="${message}"/>`];
+      case 'cancelled':
+        return ['    <skipped message="cancelled"/>'];
+      case 'not_run':
+        return [`    <skipped message="${message}"/>`];
+      default:
+        return [];
+    }
+  }
+
+  private errorType(result: TestCaseResult): string {
+    if (result.panic) {
+      return 'panic';
+    }
+    return result.name === '[build failed]' ? 'build' : 'error';
+  }
+
+  /**
+   * Render race reports as distinct errors so they are not mistaken for assertion failures
+   */
+  private renderDataRaces(result: TestCaseResult): string[] {
+    return (result.data_races || []).map(race =>
+      `    <error message="${this.escape(`Data race at ${race.address}`)}" type="data_race">` +
+      `${this.escape(race.raw)}</error>`
+    );
+  }
+
+  private formatSeconds(ms: number): string {
+    return (ms / 1000).toFixed(3);
+  }
+
+  /**
+   * Escape text for XML attributes and content
+   * Strips control characters that are invalid in XML 1.0.
+   */
+  private escape(value: string): string {
+    return value
+      .replace(/[\u0000-\u0008\u000B\u000C\u000E-\u001F]/g, '')
+      .replace(/&/g, '&amp;')
+      .replace(/</g, '&lt;')
+      .replace(/>/g, '&gt;')
+      .replace(/"/g, '&quot;')
+      .replace(/'/g, '&apos;');
+  }
+}
+
+// Export singleton instance
+export const junitReporter = new JUnitReporter();
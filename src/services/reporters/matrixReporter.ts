@@ -0,0 +1,139 @@
+/**
+ * Matrix Reporter
+ *
+ * Combines the runs of one suite across Go versions into a MatrixReport:
+ * each test's outcome per version, with tests whose outcome differs between
+ * versions flagged so a failure only on one toolchain stands out. Coverage
+ * is reported per version and unified: the union of every version's
+ * profile, so code only exercised on some toolchains counts as covered.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { GoCoverageProfile, MatrixReport, MatrixTestRow, TestExecutionResult, UnifiedCoverage } from '../../types/mcp';
+import { coverageProfileService } from '../coverageProfileService';
+import { logger } from '../loggerService';
+
+export interface MatrixRunResult {
+  goVersion: string;
+  image: string;
+  result: TestExecutionResult;
+  coverageProfile?: GoCoverageProfile; // Missing when the version produced none
+}
+
+/**
+ * Build the combined report
+ * @param runs One run per Go version, in matrix order
+ * @param unifiedCoverage From unifyCoverage
+ */
+export function buildMatrixReport(runs: MatrixRunResult[], unifiedCoverage?: UnifiedCoverage): MatrixReport {
+  const rows = new Map<string, MatrixTestRow>();
+
+  for (const run of runs) {
+    for (const testCase of run.result.test_cases || []) {
+      const key = `${testCase.package} ${testCase.name}`;
+      const row = rows.get(key) || { package: testCase.package, name: testCase.name, statuses: {}, version_specific: false };
+      row.statuses[run.goVersion] = testCase.status;
+      rows.set(key, row);
+    }
+  }
+
+  for (const row of rows.values()) {
+    const statuses = runs.map(r => row.statuses[r.goVersion]);
+    row.version_specific = statuses.some(s => s !== statuses[0]);
+  }
+
+  return {
+    go_versions: runs.map(r => r.goVersion),
+    runs: runs.map(r => ({
+      go_version: r.goVersion,
+      image: r.image,
+      success: r.result.success,
+      passed_tests: r.result.passed_tests,
+      failed_tests: r.result.failed_tests,
+      total_tests: r.result.total_tests,
+      coverage_percentage: r.result.coverage_percentage,
+    })),
+    tests: Array.from(rows.values()).sort((a, b) => a.package.localeCompare(b.package) || a.name.localeCompare(b.name)),
+    unified_coverage: unifiedCoverage,
+  };
+}
+
+/**
+ * Union the coverage profiles of every version that produced one
+ * @returns The unioned profile and its summary, or undefined when no version produced a profile
+ */
+export function unifyCoverage(runs: MatrixRunResult[]): { coverage: UnifiedCoverage; profile: GoCoverageProfile } | undefined {
+  const profiled = runs.filter(r => r.coverageProfile);
+  if (profiled.length === 0) {
+    return undefined;
+  }
+
+  const profiles = profiled.map(r => r.coverageProfile!);
+  const { profile, conflicts } = coverageProfileService.unionProfiles(...profiles);
+  const { covered, total, percentage } = coverageProfileService.statementCoverage(profile);
+
+  return {
+    profile,
+    coverage: {
+      percentage,
+      statements_covered: covered,
+      statements_total: total,
+      missing_versions: runs.filter(r => !r.coverageProfile).map(r => r.goVersion),
+      version_specific_files: Object.keys(profile.files)
+        .filter(file => profiles.some(p => !p.files[file]))
+        .sort(),
+      conflicting_files: conflicts,
+    },
+  };
+}
+
+export class MatrixReporter {
+  /**
+   * Render the tests whose outcome depends on the Go version as a text table
+   * @returns The table, or a one-line note when every version agrees
+   */
+  renderText(report: MatrixReport): string {
+    const specific = report.tests.filter(t => t.version_specific);
+    if (specific.length === 0) {
+      return `All tests behave the same on Go ${report.go_versions.join(', ')}\n${this.renderCoverage(report)}`;
+    }
+
+    const header = ['Test', ...report.go_versions.map(v => `go${v}`)];
+    const rows = specific.map(t => [
+      `${t.package} ${t.name}`.trim(),
+      ...report.go_versions.map(v => t.statuses[v] || '-'),
+    ]);
+    const widths = header.map((h, i) => Math.max(h.length, ...rows.map(r => r[i].length)));
+    const line = (cells: string[]) => cells.map((c, i) => c.padEnd(widths[i])).join('  ').trimEnd();
+
+    return [line(header), ...rows.map(line)].join('\n') + '\n' + this.renderCoverage(report);
+  }
+
+  /**
+   * One line with the unified and per-version coverage, when there is any
+   */
+  private renderCoverage(report: MatrixReport): string {
+    const unified = report.unified_coverage;
+    if (!unified) {
+      return '';
+    }
+    const perVersion = report.runs.map(r => `go${r.go_version} ${r.coverage_percentage.toFixed(1)}%`).join(', ');
+    const missing = unified.missing_versions.length > 0 ? `; no profile from go${unified.missing_versions.join(', go')}` : '';
+    return `Unified coverage: ${unified.percentage.toFixed(1)}% of ${unified.statements_total} statements (${perVersion}${missing})\n`;
+  }
+
+  /**
+   * Write the report as JSON
+   * @param outputPath Destination file path
+   * @param report Matrix report
+   */
+  async writeReport(outputPath: string, report: MatrixReport): Promise<void> {
+    await fs.mkdir(path.dirname(outputPath), { recursive: true });
+    await fs.writeFile(outputPath, JSON.stringify(report, null, 2) + '\n', 'utf-8');
+    logger.info(`Wrote Go version matrix report for ${report.go_versions.join(', ')} to ${outputPath}`);
+  }
+}
+
+// Export singleton instance
+export const matrixReporter = new MatrixReporter();
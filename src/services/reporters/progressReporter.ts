@@ -0,0 +1,84 @@
+/**
+ * Progress Reporter
+ *
+ * Default CLI event handler: renders a compact live status line while go test
+ * runs. On a TTY the line is redrawn in place; otherwise one line is printed
+ * per finished package so CI logs stay readable. Verdicts and totals are
+ * colored as the color mode and NO_COLOR allow for the output stream.
+ */
+
+import { ColorMode } from '../../types/mcp';
+import { colorEnabled, createPalette, Palette } from '../../utils/terminalColor';
+import { EventHandler, GoTestEvent } from '../testRunners/goTestEventStream';
+
+interface ProgressOutput {
+  write(text: string): unknown;
+  isTTY?: boolean;
+}
+
+export class ProgressReporter implements EventHandler {
+  private out: ProgressOutput;
+  private palette: Palette;
+  private passed = 0;
+  private failed = 0;
+  private skipped = 0;
+  private current = '';
+
+  constructor(out: ProgressOutput = process.stderr, color: ColorMode = 'auto') {
+    this.out = out;
+    this.palette = createPalette(colorEnabled(color, out));
+  }
+
+  handleEvent(event: GoTestEvent): void {
+    if (event.action === 'output') {
+      return;
+    }
+
+    if (event.test) {
+      if (event.action === 'run' || event.action === 'cont') {
+        this.current = `${event.package} ${event.test}`;
+      } else if (event.action === 'pass') {
+        this.passed++;
+      } else if (event.action === 'fail') {
+        this.failed++;
+      } else if (event.action === 'skip') {
+        this.skipped++;
+      }
+      this.redraw();
+      return;
+    }
+
+    // Package verdict
+    if (event.action === 'pass' || event.action === 'fail' || event.action === 'skip') {
+      const { green, red, yellow } = this.palette;
+      const verdict = event.action === 'pass' ? green('ok  ') : event.action === 'fail' ? red('FAIL') : yellow('skip');
+      const elapsed = event.elapsed_ms !== undefined ? ` ${(event.elapsed_ms / 1000).toFixed(2)}s` : '';
+      this.clearLine();
+      this.out.write(`${verdict} ${event.package}${elapsed}\n`);
+      this.current = '';
+      this.redraw();
+    }
+  }
+
+  /**
+   * Render the running totals
+   */
+  formatStatus(): string {
+    const { green, red, yellow } = this.palette;
+    const count = (n: number, label: string, color: (text: string) => string) => n > 0 ? color(`${n} ${label}`) : `${n} ${label}`;
+    const totals = `${count(this.passed, 'passed', green)}, ${count(this.failed, 'failed', red)}, ${count(this.skipped, 'skipped', yellow)}`;
+    return this.current ? `${totals} | ${this.current}` : totals;
+  }
+
+  private redraw(): void {
+    if (this.out.isTTY) {
+      this.out.write(`\r\x1b[K${this.formatStatus()}`);
+    }
+  }
+
+  private clearLine(): void {
+    if (this.out.isTTY) {
+      this.out.write('\r\x1b[K');
+    }
+  }
+}
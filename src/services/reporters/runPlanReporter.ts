@@ -0,0 +1,81 @@
+/**
+ * Run Plan Reporter
+ *
+ * Renders the RunPlan of a dry run: the selected packages, the go test
+ * processes they would run as, the containers that would be created, and the
+ * estimated duration. Output depends only on the plan, so two plans can be
+ * diffed to see what a change to the selection did.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { PlannedProcess, RunPlan } from '../../types/mcp';
+import { logger } from '../loggerService';
+
+export class RunPlanReporter {
+  /**
+   * Render the plan as text
+   */
+  renderText(plan: RunPlan): string {
+    const lines = [
+      `${plan.packages.length} packages selected${plan.since_ref ? ` (affected since ${plan.since_ref})` : ''}:`,
+      ...plan.packages.map(pkg => `  ${pkg}`),
+    ];
+
+    if (plan.cached.length > 0) {
+      lines.push(`${plan.cached.length} unchanged, reusing cached results:`, ...plan.cached.map(pkg => `  ${pkg}`));
+    }
+    if (plan.ginkgo_suites.length > 0) {
+      lines.push(`${plan.ginkgo_suites.length} Ginkgo suites:`, ...plan.ginkgo_suites.map(dir => `  ${dir}`));
+    }
+
+    lines.push(`${plan.processes.length} go test processes, up to ${plan.concurrency} at once:`);
+    const labels = plan.processes.map(p => this.describe(p));
+    const width = Math.max(0, ...labels.map(l => l.length));
+    plan.processes.forEach((process, i) => {
+      const estimate = process.estimated_ms !== undefined ? `~${this.formatDuration(process.estimated_ms)}` : 'no history';
+      lines.push(`  ${labels[i].padEnd(width)}  ${estimate}`);
+    });
+
+    if (plan.images.length > 0) {
+      lines.push(`${plan.containers} containers from ${plan.images.join(', ')}`);
+    }
+    if (plan.go_versions) {
+      lines.push(`Repeated on Go ${plan.go_versions.join(', ')}`);
+    }
+
+    if (plan.estimated_duration_ms === undefined) {
+      lines.push('Estimated duration: unknown (no timings_path)');
+    } else {
+      const partial = plan.unestimated_processes > 0
+        ? ` (${plan.unestimated_processes} processes without history not included)`
+        : '';
+      lines.push(`Estimated duration: ${this.formatDuration(plan.estimated_duration_ms)}${partial}`);
+    }
+
+    return lines.join('\n') + '\n';
+  }
+
+  /**
+   * Write the plan as JSON
+   * @param outputPath Destination file path
+   * @param plan Run plan
+   */
+  async writeReport(outputPath: string, plan: RunPlan): Promise<void> {
+    await fs.mkdir(path.dirname(outputPath), { recursive: true });
+    await fs.writeFile(outputPath, JSON.stringify(plan, null, 2) + '\n', 'utf-8');
+    logger.info(`Wrote run plan with ${plan.processes.length} processes to ${outputPath}`);
+  }
+
+  private describe(process: PlannedProcess): string {
+    const packages = process.packages.length === 1 ? process.packages[0] : `${process.packages.length} packages`;
+    return process.tests ? `${packages} -run ${process.tests.join(',')}` : packages;
+  }
+
+  private formatDuration(ms: number): string {
+    return `${(ms / 1000).toFixed(2)}s`;
+  }
+}
+
+// Export singleton instance
+export const runPlanReporter = new RunPlanReporter();
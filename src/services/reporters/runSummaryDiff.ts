@@ -0,0 +1,86 @@
+/**
+ * Run Summary Diff
+ *
+ * Compares a RunSummary against a baseline (typically the target branch's
+ * stored summary) so CI can report only the failures a change introduced.
+ * Tests are matched by package + name; no attempt is made to match renamed
+ * tests, which appear as one removed and one added test.
+ */
+
+import * as fs from 'fs/promises';
+import { DiffSummary, RunSummary, TestCaseResult, TestCaseStatus, TestDiffEntry } from '../../types/mcp';
+
+const FAILING: TestCaseStatus[] = ['failed', 'error', 'timed_out'];
+
+/**
+ * Classify every test by how it changed between two runs
+ * Tests that did not change state (e.g. passing in both) are omitted. If a
+ * run lists the same test twice, the later result wins.
+ * @param baseline Summary of the run to compare against
+ * @param current Summary of this run
+ */
+export function diffRunSummaries(baseline: RunSummary, current: RunSummary): DiffSummary {
+  const before = byKey(baseline.tests);
+  const after = byKey(current.tests);
+  const diff: DiffSummary = { newly_failing: [], newly_passing: [], still_failing: [], added: [], removed: [] };
+
+  for (const [key, now] of after) {
+    const then = before.get(key);
+    const entry: TestDiffEntry = {
+      package: now.package,
+      name: now.name,
+      baseline_status: then?.status,
+      current_status: now.status,
+    };
+
+    if (!then) {
+      diff.added.push(entry);
+    } else if (isFailing(now.status)) {
+      (isFailing(then.status) ? diff.still_failing : diff.newly_failing).push(entry);
+    } else if (isFailing(then.status) && now.status === 'passed') {
+      diff.newly_passing.push(entry);
+    }
+  }
+
+  for (const [key, then] of before) {
+    if (!after.has(key)) {
+      diff.removed.push({ package: then.package, name: then.name, baseline_status: then.status });
+    }
+  }
+
+  for (const entries of Object.values(diff)) {
+    entries.sort((a: TestDiffEntry, b: TestDiffEntry) => a.package.localeCompare(b.package) || a.name.localeCompare(b.name));
+  }
+  return diff;
+}
+
+/**
+ * Read a stored RunSummary JSON file
+ * @param summaryPath File written by summary_json_path
+ * @throws If the file is missing or is not a RunSummary
+ */
+export async function readRunSummary(summaryPath: string): Promise<RunSummary> {
+  return parseRunSummary(await fs.readFile(summaryPath, 'utf-8'), summaryPath);
+}
+
+/**
+ * Parse stored RunSummary JSON
+ * @param json Serialized summary
+ * @param source Where it came from, for the error message
+ * @throws If the JSON is not a RunSummary
+ */
+export function parseRunSummary(json: string, source: string): RunSummary {
+  const summary = JSON.parse(json);
+  if (typeof summary?.schemaVersion !== 'number' || !Array.isArray(summary.tests)) {
+    throw new Error(`${source} is not a run summary`);
+  }
+  return summary as RunSummary;
+}
+
+function byKey(tests: TestCaseResult[]): Map<string, TestCaseResult> {
+  return new Map(tests.map(t => [`${t.package} ${t.name}`, t]));
+}
+
+function isFailing(status: TestCaseStatus): boolean {
+  return FAILING.includes(status);
+}
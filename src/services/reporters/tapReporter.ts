@@ -0,0 +1,124 @@
+/**
+ * TAP Reporter
+ *
+ * Renders a RunSummary's per-test results as a TAP version 13 stream
+ * (https://testanything.org/tap-version-13-specification.html) for
+ * aggregators that do not read JUnit. Each test, subtests included, is one
+ * numbered test point described by its package and full name. Skipped tests
+ * carry a # SKIP directive; cancelled and unrun tests never finished, so they
+ * are reported as # TODO, which TAP consumers do not count as failures.
+ * Failures, errors and timeouts get a YAML diagnostic block with the failure
+ * message and the test's output.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { RunSummary, TestCaseResult } from '../../types/mcp';
+import { stripAnsi } from '../../utils/terminalColor';
+import { logger } from '../loggerService';
+
+// YAML allows tab, newline and printable characters only
+const NON_PRINTABLE = /[\x00-\x08\x0b-\x1f\x7f]/g;
+
+export class TapReporter {
+  /**
+   * Generate a TAP version 13 stream
+   * @param summary Run summary
+   * @returns TAP content
+   */
+  generate(summary: RunSummary): string {
+    const lines = ['TAP version 13', `1..${summary.tests.length}`];
+
+    summary.tests.forEach((result, i) => {
+      lines.push(...this.renderTestPoint(result, i + 1));
+    });
+
+    return lines.join('\n') + '\n';
+  }
+
+  /**
+   * Write a TAP report to disk
+   * @param outputPath Destination file path
+   * @param summary Run summary
+   */
+  async writeReport(outputPath: string, summary: RunSummary): Promise<void> {
+    await fs.mkdir(path.dirname(outputPath), { recursive: true });
+    await fs.writeFile(outputPath, this.generate(summary), 'utf-8');
+    logger.info(`Wrote TAP report with ${summary.tests.length} test points to ${outputPath}`);
+  }
+
+  /**
+   * Render the test line, plus a diagnostic block for failures
+   */
+  private renderTestPoint(result: TestCaseResult, number: number): string[] {
+    const description = this.escape(`${result.package} ${result.name}`.trim());
+
+    switch (result.status) {
+      case 'passed':
+        return [`ok ${number} - ${description}`];
+      case 'skipped':
+        return [`ok ${number} - ${description} # SKIP${this.reason(result.failure_message)}`];
+      case 'cancelled':
+        return [`not ok ${number} - ${description} # TODO cancelled before it finished`];
+      case 'not_run':
+        return [`not ok ${number} - ${description} # TODO${this.reason(result.failure_message || 'not run')}`];
+      default:
+        return [`not ok ${number} - ${description}`, ...this.renderDiagnostics(result)];
+    }
+  }
+
+  /**
+   * YAML block indented under the test line, between --- and ...
+   * Output goes in a literal block scalar with an explicit indentation
+   * indicator, since go test output lines start with spaces.
+   */
+  private renderDiagnostics(result: TestCaseResult): string[] {
+    const lines = [
+      '  ---',
+      `  message: ${this.quote(result.failure_message || result.status)}`,
+      `  severity: ${result.status === 'failed' ? 'fail' : result.status === 'timed_out' ? 'timeout' : 'error'}`,
+      `  package: ${this.quote(result.package)}`,
+      `  duration_ms: ${result.duration_ms}`,
+    ];
+    if (result.attempts !== undefined) {
+      lines.push(`  attempts: ${result.attempts}`);
+    }
+
+    const output = this.printable(result.goroutine_dump || result.output).replace(/\s+$/, '');
+    if (output) {
+      lines.push('  output: |2-', ...output.split('\n').map(line => `    ${line}`.trimEnd()));
+    }
+
+    lines.push('  ...');
+    return lines;
+  }
+
+  /**
+   * Directive reason: everything after # SKIP or # TODO, on one line
+   */
+  private reason(text?: string): string {
+    const reason = this.printable(text || '').replace(/\s+/g, ' ').trim();
+    return reason ? ` ${reason}` : '';
+  }
+
+  /**
+   * Descriptions end at an unescaped #, which would start a directive
+   */
+  private escape(description: string): string {
+    return this.printable(description).replace(/\s+/g, ' ').replace(/\\/g, '\\\\').replace(/#/g, '\\#');
+  }
+
+  /**
+   * A double-quoted YAML scalar; JSON string syntax is valid YAML
+   */
+  private quote(text: string): string {
+    return JSON.stringify(text);
+  }
+
+  private printable(text: string): string {
+    return stripAnsi(text).replace(/\r\n?/g, '\n').replace(NON_PRINTABLE, '');
+  }
+}
+
+// Export singleton instance
+export const tapReporter = new TapReporter();
@@ -0,0 +1,40 @@
+/**
+ * Test Inventory Reporter
+ *
+ * Writes the TestInventory of a list-mode run: every top-level test the run
+ * would execute, one flat entry per test, for tools that build their own
+ * allocation plans. Unlike a RunPlan it says nothing about processes or
+ * containers, and its order depends only on the tests, so two inventories
+ * can be diffed.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { TestInventory } from '../../types/mcp';
+import { logger } from '../loggerService';
+
+// Bump on any change that could break existing consumers (renamed or removed fields)
+export const TEST_INVENTORY_SCHEMA_VERSION = 1;
+
+export class TestInventoryReporter {
+  /**
+   * Render the inventory as JSON
+   */
+  renderJson(inventory: TestInventory): string {
+    return JSON.stringify(inventory, null, 2) + '\n';
+  }
+
+  /**
+   * Write the inventory as JSON
+   * @param outputPath Destination file path
+   * @param inventory Test inventory
+   */
+  async writeReport(outputPath: string, inventory: TestInventory): Promise<void> {
+    await fs.mkdir(path.dirname(outputPath), { recursive: true });
+    await fs.writeFile(outputPath, this.renderJson(inventory), 'utf-8');
+    logger.info(`Wrote inventory of ${inventory.tests.length} tests to ${outputPath}`);
+  }
+}
+
+// Export singleton instance
+export const testInventoryReporter = new TestInventoryReporter();
@@ -0,0 +1,136 @@
+/**
+ * Test List Reporter
+ *
+ * Renders per-test results as a readable text listing for CI logs. With
+ * subtest collapsing, a table-driven test whose subtests all passed becomes
+ * one line ("TestFoo: 142 subtests passed"), while one with failures lists
+ * only its failing subtests by full name. Collapsing only affects this
+ * listing; the RunSummary and the JSON and JUnit reports keep every subtest.
+ */
+
+import { TestCaseResult, TestCaseStatus } from '../../types/mcp';
+import { createPalette, Palette } from '../../utils/terminalColor';
+
+export interface TestListEntry {
+  package: string;
+  name: string;
+  status: TestCaseStatus;
+  duration_ms: number;
+  passed_subtests?: number;   // Set when the entry stands in for subtests that all passed
+  skipped_subtests?: number;
+}
+
+export interface TestListOptions {
+  collapseSubtests?: boolean;
+  color?: boolean;            // Color status labels
+}
+
+// Subtests that are neither of these keep a collapsed parent expanded
+const QUIET: TestCaseStatus[] = ['passed', 'skipped'];
+
+const LABELS: Record<TestCaseStatus, string> = {
+  passed: 'ok',
+  failed: 'FAIL',
+  error: 'ERROR',
+  timed_out: 'TIMEOUT',
+  skipped: 'skip',
+  cancelled: 'cancel',
+  not_run: 'not run',
+};
+
+/**
+ * Fold each top-level test's subtests into it when they all passed
+ * Otherwise only the non-passing subtests are kept, innermost first: a
+ * failing leaf stands for the parents that failed because of it. Tests keep
+ * the order they ran in.
+ * @param tests Every test of the run, subtests included
+ */
+export function collapseSubtests(tests: TestCaseResult[]): TestListEntry[] {
+  const groups = new Map<string, { top: string; tests: TestCaseResult[] }>();
+  for (const test of tests) {
+    const top = test.name.split('/')[0];
+    const key = `${test.package}\0${top}`;
+    const group = groups.get(key) || { top, tests: [] };
+    group.tests.push(test);
+    groups.set(key, group);
+  }
+
+  const entries: TestListEntry[] = [];
+  for (const { top, tests: group } of groups.values()) {
+    const parent = group.find(t => t.name === top);
+    const subtests = group.filter(t => t !== parent);
+    const pkg = group[0].package;
+
+    const loud = subtests.filter(t => !QUIET.includes(t.status));
+    if (loud.length === 0 && (!parent || QUIET.includes(parent.status))) {
+      const passed = subtests.filter(t => t.status === 'passed').length;
+      const skipped = subtests.length - passed;
+      entries.push({
+        package: pkg,
+        name: top,
+        status: parent?.status || 'passed',
+        duration_ms: parent?.duration_ms ?? subtests.reduce((sum, t) => sum + t.duration_ms, 0),
+        passed_subtests: subtests.length > 0 ? passed : undefined,
+        skipped_subtests: skipped > 0 ? skipped : undefined,
+      });
+      continue;
+    }
+
+    const leaves = loud.filter(t => !loud.some(other => other.name.startsWith(`${t.name}/`)));
+    const shown = leaves.length > 0 ? leaves : [parent!]; // The parent failed outside its subtests
+    entries.push(...shown.map(t => ({ package: t.package, name: t.name, status: t.status, duration_ms: t.duration_ms })));
+  }
+
+  return entries;
+}
+
+export class TestListReporter {
+  /**
+   * Render the results grouped by package
+   * @param tests Every test of the run
+   * @param options collapseSubtests folds fully passing subtests into their parent; color colors status labels
+   */
+  renderText(tests: TestCaseResult[], options: TestListOptions = {}): string {
+    const entries: TestListEntry[] = options.collapseSubtests
+      ? collapseSubtests(tests)
+      : tests.map(t => ({ package: t.package, name: t.name, status: t.status, duration_ms: t.duration_ms }));
+    entries.sort((a, b) => a.package.localeCompare(b.package)); // Stable: run order within a package
+    if (entries.length === 0) {
+      return 'No tests ran\n';
+    }
+
+    const palette = createPalette(Boolean(options.color));
+    const lines: string[] = [];
+    let pkg: string | undefined;
+    for (const entry of entries) {
+      if (entry.package !== pkg) {
+        pkg = entry.package;
+        lines.push(palette.bold(pkg || '(no package)'));
+      }
+      const label = LABELS[entry.status];
+      const padding = ' '.repeat(Math.max(0, 7 - label.length)); // Outside the color, which has no width
+      lines.push(`  ${this.colorFor(entry.status, palette)(label)}${padding} ${this.describe(entry)} (${(entry.duration_ms / 1000).toFixed(2)}s)`);
+    }
+
+    return lines.join('\n') + '\n';
+  }
+
+  private colorFor(status: TestCaseStatus, palette: Palette): (text: string) => string {
+    if (status === 'passed') {
+      return palette.green;
+    }
+    return status === 'skipped' || status === 'cancelled' || status === 'not_run' ? palette.yellow : palette.red;
+  }
+
+  private describe(entry: TestListEntry): string {
+    if (entry.passed_subtests === undefined) {
+      return entry.name;
+    }
+
+    const skipped = entry.skipped_subtests ? `, ${entry.skipped_subtests} skipped` : '';
+    return `${entry.name}: ${entry.passed_subtests} subtests passed${skipped}`;
+  }
+}
+
+// Export singleton instance
+export const testListReporter = new TestListReporter();
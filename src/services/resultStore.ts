@@ -0,0 +1,91 @@
+/**
+ * Result Store
+ *
+ * Keeps RunSummary history where it outlives the CI worker that produced it,
+ * keyed by run ID, so later runs can read baselines and trends back.
+ * FileResultStore writes one JSON file per run into a directory (a mounted
+ * volume or network share); S3ResultStore keeps the same files as objects in
+ * an S3-compatible bucket.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { RunSummary } from '../types/mcp';
+import { parseRunSummary } from './reporters/runSummaryDiff';
+
+// Run IDs become file names and object keys
+const RUN_ID = /^[\w.-]+$/;
+
+export interface ResultStore {
+  /**
+   * Store a run's summary, replacing any stored under the same ID
+   */
+  put(runId: string, summary: RunSummary): Promise<void>;
+
+  /**
+   * Read a stored summary
+   * @throws If no run is stored under the ID
+   */
+  get(runId: string): Promise<RunSummary>;
+
+  /**
+   * IDs of every stored run, oldest first
+   */
+  list(): Promise<string[]>;
+}
+
+/**
+ * @throws If the ID could escape the store's directory or prefix
+ */
+export function validateRunId(runId: string): void {
+  if (!RUN_ID.test(runId) || runId === '.' || runId === '..') {
+    throw new Error(`Invalid run ID "${runId}": use letters, digits, '.', '_' and '-'`);
+  }
+}
+
+export class FileResultStore implements ResultStore {
+  constructor(private dir: string) {}
+
+  async put(runId: string, summary: RunSummary): Promise<void> {
+    validateRunId(runId);
+    await fs.mkdir(this.dir, { recursive: true });
+    await fs.writeFile(this.file(runId), JSON.stringify(summary, null, 2) + '\n', 'utf-8');
+  }
+
+  async get(runId: string): Promise<RunSummary> {
+    validateRunId(runId);
+    let json: string;
+    try {
+      json = await fs.readFile(this.file(runId), 'utf-8');
+    } catch (error: any) {
+      throw new Error(error.code === 'ENOENT' ? `No run ${runId} in ${this.dir}` : error.message);
+    }
+    return parseRunSummary(json, this.file(runId));
+  }
+
+  /**
+   * Ordered by modification time, so a summary stored again counts as new
+   */
+  async list(): Promise<string[]> {
+    let entries: string[];
+    try {
+      entries = await fs.readdir(this.dir);
+    } catch (error: any) {
+      if (error.code === 'ENOENT') {
+        return [];
+      }
+      throw error;
+    }
+
+    const runs = await Promise.all(entries
+      .filter(e => e.endsWith('.json'))
+      .map(async e => ({ id: e.slice(0, -'.json'.length), mtime: (await fs.stat(path.join(this.dir, e))).mtimeMs })));
+    return runs
+      .sort((a, b) => a.mtime - b.mtime || a.id.localeCompare(b.id))
+      .map(r => r.id);
+  }
+
+  private file(runId: string): string {
+    return path.join(this.dir, `${runId}.json`);
+  }
+}
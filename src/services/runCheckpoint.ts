@@ -0,0 +1,253 @@
+/**
+ * Run Checkpoint
+ *
+ * Lets a run that was killed part way, e.g. by a CI job time limit, continue
+ * where it stopped instead of starting over. A checkpointed run stores its
+ * partial RunSummary in the result store every time one of its go test
+ * processes exits, with a checkpoint recording the run's selection, its
+ * schedule of processes (one per package, or per -run shard) and which of
+ * them passed or failed.
+ *
+ * A run resuming from the checkpoint replays the stored schedule rather than
+ * planning a new one, so shards and the shuffle seed stay as they were, and
+ * runs only the processes still pending. A process in flight when the run was
+ * killed never reported, so it runs again. Results and coverage of the
+ * finished ones come from the stored summary.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import {
+  BuildFailure,
+  CheckpointedRun,
+  CheckpointSelection,
+  GoCoverageProfile,
+  RunCheckpoint,
+  RunSummary,
+  TestCaseResult,
+  TestExecutionOptions,
+} from '../types/mcp';
+import { coverageProfileService } from './coverageProfileService';
+import { logger } from './loggerService';
+import { ResultStore } from './resultStore';
+import { currentRunLog } from './runLog';
+
+// One scheduled go test process: a package, or a -run shard of one
+export interface ScheduledRun {
+  pkg: string;
+  tests?: string[];
+}
+
+// Partial results a checkpoint is rendered from
+export type CheckpointRenderer = (tests: TestCaseResult[], buildFailures: BuildFailure[], profile?: GoCoverageProfile) => RunSummary;
+
+const FAILING = ['failed', 'error', 'timed_out'];
+
+/**
+ * The part of a run's options that decides which tests it runs
+ * @param packages Packages as selected, before they are scheduled
+ * @param shuffleSeed Base seed the run resolved, if shuffled
+ */
+export function checkpointSelection(packages: string[], options: TestExecutionOptions, shuffleSeed?: number): CheckpointSelection {
+  return {
+    packages,
+    tags: options.tags,
+    run: options.run,
+    labels: options.labels,
+    exclude_labels: options.exclude_labels,
+    shards: options.shards,
+    shuffle_seed: shuffleSeed,
+    race: options.race,
+    count: options.count,
+  };
+}
+
+/**
+ * Checkpoint of a stored summary, if the options can resume it
+ * The stored packages and shuffle seed are reused as they are, so only
+ * options that would select other tests are compared.
+ * @throws If the summary has no checkpoint or was checkpointed with other options
+ */
+export function resumableCheckpoint(summary: RunSummary, runId: string, options: TestExecutionOptions): RunCheckpoint {
+  const checkpoint = summary.checkpoint;
+  if (!checkpoint) {
+    throw new Error(`Run ${runId} has no checkpoint to resume`);
+  }
+
+  const stored = checkpoint.selection;
+  const current = checkpointSelection(stored.packages, options, stored.shuffle_seed);
+  for (const key of Object.keys(current) as (keyof CheckpointSelection)[]) {
+    if (JSON.stringify(current[key]) !== JSON.stringify(stored[key])) {
+      throw new Error(`Cannot resume run ${runId}: ${key} differs from its checkpoint`);
+    }
+  }
+
+  const shuffled = options.shuffle !== undefined && options.shuffle !== 'off';
+  if (shuffled !== (stored.shuffle_seed !== undefined)) {
+    throw new Error(`Cannot resume run ${runId}: shuffle differs from its checkpoint`);
+  }
+
+  return checkpoint;
+}
+
+/**
+ * Whether a stored summary belongs to a checkpointed run that did not finish
+ */
+export function isUnfinishedCheckpoint(summary: RunSummary): boolean {
+  if (!summary.checkpoint) {
+    return false;
+  }
+  const runs = summary.checkpoint.runs;
+  return !runs || runs.some(r => r.status === 'pending');
+}
+
+export class RunCheckpointer {
+  private runs?: CheckpointedRun[];
+  private tracked = new Map<ScheduledRun, CheckpointedRun>();
+  private tests: TestCaseResult[] = [];
+  private buildFailures: BuildFailure[] = [];
+  private profile?: GoCoverageProfile;
+  private saving: Promise<void> = Promise.resolve();
+
+  // Results the resumed run restores rather than reruns
+  readonly restoredTests: TestCaseResult[] = [];
+  readonly restoredBuildFailures: BuildFailure[] = [];
+  private restoredProfile?: GoCoverageProfile;
+
+  /**
+   * @param runId ID the checkpoint is stored under
+   * @param render Builds the partial summary; its checkpoint is set here
+   * @param resumed Summary stored by the interrupted run, when resuming
+   */
+  constructor(
+    private store: ResultStore,
+    readonly runId: string,
+    private selection: CheckpointSelection,
+    private render: CheckpointRenderer,
+    resumed?: RunSummary
+  ) {
+    if (resumed) {
+      this.runs = resumed.checkpoint?.runs?.map(r => ({ ...r }));
+      this.restoredTests.push(...resumed.tests.map(t => ({ ...t, resumed: true })));
+      this.restoredBuildFailures.push(...resumed.build_failures);
+      this.restoredProfile = resumed.coverage.profile;
+      this.tests.push(...this.restoredTests);
+      this.buildFailures.push(...this.restoredBuildFailures);
+      this.profile = this.restoredProfile;
+    }
+  }
+
+  /**
+   * Every scheduled process finished; a resumed run has nothing left to run
+   */
+  get finished(): boolean {
+    return this.runs !== undefined && this.runs.every(r => r.status !== 'pending');
+  }
+
+  /**
+   * The processes to run, tracked so their completion is checkpointed
+   * A resumed run gets the pending part of the stored schedule. Otherwise the
+   * planned schedule is stored, all pending, before anything runs. Processes
+   * scheduled after the first call, such as reruns, are not tracked.
+   * @param plan Schedule of a run that has none stored yet
+   */
+  async schedule(plan: () => Promise<ScheduledRun[]>): Promise<ScheduledRun[]> {
+    if (this.tracked.size > 0 || (this.runs && this.finished)) {
+      return plan();
+    }
+
+    if (this.runs) {
+      const pending = this.runs.filter(r => r.status === 'pending');
+      logger.info(`Resuming run ${this.runId}: ${this.runs.length - pending.length} of ${this.runs.length} go test processes finished, running ${pending.length}`);
+      return pending.map(state => this.track({ pkg: state.package, tests: state.tests }, state));
+    }
+
+    const scheduled = await plan();
+    this.runs = scheduled.map(run => ({ package: run.pkg, tests: run.tests, status: 'pending' as const }));
+    scheduled.forEach((run, i) => this.track(run, this.runs![i]));
+    logger.info(`Checkpointing run ${this.runId}; resume it with resume_run_id=${this.runId}`);
+    await this.save();
+    return scheduled;
+  }
+
+  /**
+   * Record a process that exited and store the checkpoint
+   * Not for cancelled processes: their results are partial, so they stay pending.
+   * @param profilePath Coverage profile the process wrote
+   */
+  async complete(run: ScheduledRun, tests: TestCaseResult[], buildFailures: BuildFailure[], profilePath: string): Promise<void> {
+    const state = this.tracked.get(run);
+    if (!state) {
+      return;
+    }
+
+    const profile = await coverageProfileService.readProfile(profilePath).catch(() => undefined);
+    state.status = buildFailures.length > 0 || tests.some(t => FAILING.includes(t.status)) ? 'failed' : 'passed';
+    this.tests.push(...tests);
+    this.buildFailures.push(...buildFailures);
+    if (profile) {
+      this.profile = this.profile ? coverageProfileService.mergeProfiles(this.profile, profile) : profile;
+    }
+
+    currentRunLog().debug('Checkpointed', { package: run.pkg, status: state.status });
+    await this.save();
+  }
+
+  /**
+   * Progress to attach to the run's final summary
+   */
+  progress(): RunCheckpoint {
+    return { selection: this.selection, runs: this.runs?.map(r => ({ ...r })) };
+  }
+
+  /**
+   * Fold the restored coverage into the profile written by this run
+   * @param ranPackages Whether this run wrote a profile of its own
+   */
+  async restoreCoverage(coverageProfilePath: string, ranPackages: boolean): Promise<void> {
+    if (!this.restoredProfile) {
+      return;
+    }
+    try {
+      const profiles = [this.restoredProfile];
+      const own = ranPackages ? await coverageProfileService.readProfile(coverageProfilePath).catch(() => undefined) : undefined;
+      if (own) {
+        profiles.push(own);
+      }
+      await fs.mkdir(path.dirname(coverageProfilePath), { recursive: true });
+      await coverageProfileService.writeProfile(coverageProfilePath, coverageProfileService.mergeProfiles(...profiles));
+    } catch (error: any) {
+      logger.warn(`Failed to restore coverage of run ${this.runId}: ${error.message}`);
+    }
+  }
+
+  /**
+   * Wait for checkpoints still being stored
+   */
+  flush(): Promise<void> {
+    return this.saving;
+  }
+
+  private track(run: ScheduledRun, state: CheckpointedRun): ScheduledRun {
+    this.tracked.set(run, state);
+    return run;
+  }
+
+  /**
+   * Stores run one at a time, each with the state at the time it starts
+   * A store that cannot be reached is logged and does not fail the run.
+   */
+  private save(): Promise<void> {
+    this.saving = this.saving.then(async () => {
+      try {
+        await this.store.put(this.runId, {
+          ...this.render(this.tests, this.buildFailures, this.profile),
+          checkpoint: this.progress(),
+        });
+      } catch (error: any) {
+        logger.warn(`Failed to checkpoint run ${this.runId}: ${error.message}`);
+      }
+    });
+    return this.saving;
+  }
+}
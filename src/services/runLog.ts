@@ -0,0 +1,116 @@
+/**
+ * Run Log
+ *
+ * Structured per-run diagnostics. Every entry carries the run's fields
+ * (run_id, plus package and container_id where known), so filtering a
+ * debug-level log by package traces it from scheduling through container
+ * creation, cache lookup, retries, and completion.
+ *
+ * The active RunLog follows a run's async work (AsyncLocalStorage), so the
+ * sandbox and scheduler log with the run's fields without every call
+ * passing it along. Test output never goes through here; it belongs in the
+ * reports.
+ */
+
+import { AsyncLocalStorage } from 'async_hooks';
+import winston from 'winston';
+import { LogFormat, LogLevel } from '../types/mcp';
+import { logger } from './loggerService';
+
+export interface LogFields {
+  run_id?: string;
+  package?: string;
+  container_id?: string;
+  [key: string]: unknown;
+}
+
+export interface RunLogOptions {
+  level?: LogLevel;           // Default: the service log level
+  format?: LogFormat;         // Default: the service log format
+}
+
+const active = new AsyncLocalStorage<RunLog>();
+
+export class RunLog {
+  constructor(private fields: LogFields = {}, private sink: Pick<winston.Logger, 'log'> = logger) {}
+
+  get runId(): string | undefined {
+    return this.fields.run_id;
+  }
+
+  /**
+   * A log that adds fields to every entry, e.g. the package being run
+   */
+  with(fields: LogFields): RunLog {
+    return new RunLog({ ...this.fields, ...fields }, this.sink);
+  }
+
+  /**
+   * Make this the active log for everything fn starts
+   */
+  run<T>(fn: () => T): T {
+    return active.run(this, fn);
+  }
+
+  error(message: string, fields?: LogFields): void {
+    this.write('error', message, fields);
+  }
+
+  warn(message: string, fields?: LogFields): void {
+    this.write('warn', message, fields);
+  }
+
+  info(message: string, fields?: LogFields): void {
+    this.write('info', message, fields);
+  }
+
+  debug(message: string, fields?: LogFields): void {
+    this.write('debug', message, fields);
+  }
+
+  private write(level: LogLevel, message: string, fields?: LogFields): void {
+    this.sink.log(level, message, { ...this.fields, ...fields });
+  }
+}
+
+/**
+ * The log of the run this code is executing for, or an unscoped one
+ */
+export function currentRunLog(): RunLog {
+  return active.getStore() || new RunLog();
+}
+
+/**
+ * Create the log for a new run
+ * Without a level or format the service logger is used. Either one gets the
+ * run its own logger on stderr, leaving stdout to reports.
+ * @param runId Identifier stamped on every entry
+ * @param options Level and output format
+ */
+export function createRunLog(runId: string, options: RunLogOptions = {}): RunLog {
+  if (!options.level && !options.format) {
+    return new RunLog({ run_id: runId });
+  }
+
+  const format = options.format === 'json'
+    ? winston.format.combine(winston.format.timestamp(), winston.format.json())
+    : winston.format.combine(
+      winston.format.timestamp(),
+      winston.format.printf(({ timestamp, level, message, ...fields }) => {
+        const pairs = Object.entries(fields)
+          .filter(([, value]) => value !== undefined)
+          .map(([key, value]) => `${key}=${typeof value === 'string' && !/\s/.test(value) ? value : JSON.stringify(value)}`);
+        return [timestamp, level.toUpperCase(), message, ...pairs].join(' ');
+      })
+    );
+
+  const sink = winston.createLogger({
+    level: options.level || 'info',
+    format,
+    transports: [
+      new winston.transports.Console({ stderrLevels: ['error', 'warn', 'info', 'debug'] }),
+    ],
+  });
+
+  return new RunLog({ run_id: runId }, sink);
+}
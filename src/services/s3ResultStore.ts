@@ -0,0 +1,251 @@
+/**
+ * S3 Result Store
+ *
+ * ResultStore backed by an S3-compatible bucket: AWS S3, MinIO, Ceph, ...
+ * Each run is one JSON object under the configured prefix. Requests are
+ * signed with AWS Signature Version 4 here rather than through the AWS SDK,
+ * so installs that only use the filesystem store pull in nothing extra.
+ * Objects are addressed path-style ({endpoint}/{bucket}/{key}), which MinIO
+ * expects and AWS accepts.
+ *
+ * Credentials default to AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and, for
+ * temporary credentials, AWS_SESSION_TOKEN.
+ */
+
+import axios from 'axios';
+import crypto from 'crypto';
+import { RunSummary } from '../types/mcp';
+import { parseRunSummary } from './reporters/runSummaryDiff';
+import { ResultStore, validateRunId } from './resultStore';
+
+export interface S3Credentials {
+  accessKeyId: string;
+  secretAccessKey: string;
+  sessionToken?: string;
+}
+
+export interface S3ResultStoreConfig {
+  bucket: string;
+  prefix?: string;            // Key prefix, e.g. alcs/runs; a trailing / is implied
+  endpoint?: string;          // S3-compatible endpoint, e.g. http://minio:9000 (default: AWS for the region)
+  region?: string;            // Signing region (default: AWS_REGION, then us-east-1)
+  credentials?: S3Credentials; // Default: from the environment
+  timeout_ms?: number;
+}
+
+export interface SignableRequest {
+  method: string;
+  host: string;
+  path: string;               // Unencoded, starting with /
+  query?: Record<string, string>;
+  headers?: Record<string, string>;
+  body?: string;
+}
+
+/**
+ * Split an s3://bucket/prefix location
+ * @throws If the location has no bucket
+ */
+export function parseS3Location(location: string): { bucket: string; prefix: string } {
+  const match = location.match(/^s3:\/\/([^/]+)\/?(.*)$/);
+  if (!match) {
+    throw new Error(`Invalid S3 location "${location}": expected s3://bucket/prefix`);
+  }
+  return { bucket: match[1], prefix: match[2].replace(/\/+$/, '') };
+}
+
+/**
+ * Sign a request with AWS Signature Version 4
+ * Every given header is signed, plus host, x-amz-date and the payload hash.
+ * @returns Headers to send with the request, Authorization included
+ */
+export function signS3Request(req: SignableRequest, credentials: S3Credentials, region: string, date: Date): Record<string, string> {
+  const amzDate = date.toISOString().replace(/[-:]|\.\d{3}/g, '');
+  const day = amzDate.slice(0, 8);
+  const payloadHash = sha256(req.body || '');
+
+  const headers: Record<string, string> = {};
+  for (const [name, value] of Object.entries(req.headers || {})) {
+    headers[name.toLowerCase()] = value;
+  }
+  headers.host = req.host;
+  headers['x-amz-content-sha256'] = payloadHash;
+  headers['x-amz-date'] = amzDate;
+  if (credentials.sessionToken) {
+    headers['x-amz-security-token'] = credentials.sessionToken;
+  }
+
+  const names = Object.keys(headers).sort();
+  const query = req.query || {};
+  const canonicalRequest = [
+    req.method,
+    req.path.split('/').map(uriEncode).join('/'),
+    Object.keys(query).sort().map(k => `${uriEncode(k)}=${uriEncode(query[k])}`).join('&'),
+    ...names.map(n => `${n}:${headers[n].trim()}`),
+    '',
+    names.join(';'),
+    payloadHash,
+  ].join('\n');
+
+  const scope = `${day}/${region}/s3/aws4_request`;
+  const stringToSign = ['AWS4-HMAC-SHA256', amzDate, scope, sha256(canonicalRequest)].join('\n');
+  const signingKey = [region, 's3', 'aws4_request'].reduce(hmac, hmac(`AWS4${credentials.secretAccessKey}`, day));
+  const signature = crypto.createHmac('sha256', signingKey).update(stringToSign).digest('hex');
+
+  headers.authorization =
+    `AWS4-HMAC-SHA256 Credential=${credentials.accessKeyId}/${scope}, SignedHeaders=${names.join(';')}, Signature=${signature}`;
+  delete headers.host; // Set by the HTTP client from the URL
+  return headers;
+}
+
+export class S3ResultStore implements ResultStore {
+  private endpoint: URL;
+  private region: string;
+
+  constructor(private config: S3ResultStoreConfig, private now: () => Date = () => new Date()) {
+    this.region = config.region || process.env.AWS_REGION || 'us-east-1';
+    this.endpoint = new URL(config.endpoint || `https://s3.${this.region}.amazonaws.com`);
+  }
+
+  async put(runId: string, summary: RunSummary): Promise<void> {
+    validateRunId(runId);
+    const response = await this.request('PUT', this.objectPath(runId), {}, JSON.stringify(summary, null, 2) + '\n');
+    if (response.status !== 200) {
+      throw this.error(`store run ${runId}`, response);
+    }
+  }
+
+  async get(runId: string): Promise<RunSummary> {
+    validateRunId(runId);
+    const response = await this.request('GET', this.objectPath(runId));
+    if (response.status === 404) {
+      throw new Error(`No run ${runId} in s3://${this.config.bucket}/${this.prefix()}`);
+    }
+    if (response.status !== 200) {
+      throw this.error(`read run ${runId}`, response);
+    }
+    return parseRunSummary(response.data, `s3://${this.config.bucket}/${this.key(runId)}`);
+  }
+
+  /**
+   * Ordered by last modification, across every page of ListObjectsV2
+   */
+  async list(): Promise<string[]> {
+    const runs: { id: string; modified: string }[] = [];
+    let token: string | undefined;
+
+    do {
+      const query: Record<string, string> = { 'list-type': '2', prefix: this.prefix() };
+      if (token) {
+        query['continuation-token'] = token;
+      }
+      const response = await this.request('GET', `/${this.config.bucket}`, query);
+      if (response.status !== 200) {
+        throw this.error('list runs', response);
+      }
+
+      const xml: string = response.data;
+      for (const [, contents] of xml.matchAll(/<Contents>([\s\S]*?)<\/Contents>/g)) {
+        const key = unescapeXml(contents.match(/<Key>([\s\S]*?)<\/Key>/)?.[1] || '');
+        const rest = key.slice(this.prefix().length);
+        if (rest.endsWith('.json') && !rest.includes('/')) {
+          runs.push({ id: rest.slice(0, -'.json'.length), modified: contents.match(/<LastModified>(.*?)<\/LastModified>/)?.[1] || '' });
+        }
+      }
+      token = /<IsTruncated>true<\/IsTruncated>/.test(xml)
+        ? unescapeXml(xml.match(/<NextContinuationToken>(.*?)<\/NextContinuationToken>/)?.[1] || '') || undefined
+        : undefined;
+    } while (token);
+
+    return runs
+      .sort((a, b) => a.modified.localeCompare(b.modified) || a.id.localeCompare(b.id))
+      .map(r => r.id);
+  }
+
+  private async request(method: string, objectPath: string, query: Record<string, string> = {}, body?: string) {
+    const basePath = this.endpoint.pathname.replace(/\/+$/, '');
+    const headers = signS3Request(
+      {
+        method,
+        host: this.endpoint.host,
+        path: basePath + objectPath,
+        query,
+        headers: body !== undefined ? { 'content-type': 'application/json' } : {},
+        body,
+      },
+      this.credentials(),
+      this.region,
+      this.now()
+    );
+
+    const url = new URL(this.endpoint.origin);
+    url.pathname = basePath + objectPath;
+    for (const [k, v] of Object.entries(query)) {
+      url.searchParams.set(k, v);
+    }
+
+    return axios.request({
+      method,
+      url: url.toString(),
+      headers,
+      data: body,
+      timeout: this.config.timeout_ms ?? 30000,
+      responseType: 'text',
+      transformResponse: (data: string) => data, // Parsed here, as XML or a summary
+      validateStatus: () => true,
+    });
+  }
+
+  private credentials(): S3Credentials {
+    const credentials = this.config.credentials || {
+      accessKeyId: process.env.AWS_ACCESS_KEY_ID || '',
+      secretAccessKey: process.env.AWS_SECRET_ACCESS_KEY || '',
+      sessionToken: process.env.AWS_SESSION_TOKEN || undefined,
+    };
+    if (!credentials.accessKeyId || !credentials.secretAccessKey) {
+      throw new Error('S3 result store needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY');
+    }
+    return credentials;
+  }
+
+  private prefix(): string {
+    return this.config.prefix ? `${this.config.prefix.replace(/\/+$/, '')}/` : '';
+  }
+
+  private key(runId: string): string {
+    return `${this.prefix()}${runId}.json`;
+  }
+
+  private objectPath(runId: string): string {
+    return `/${this.config.bucket}/${this.key(runId)}`;
+  }
+
+  private error(action: string, response: { status: number; data: any }): Error {
+    const code = String(response.data || '').match(/<Code>(.*?)<\/Code>/)?.[1];
+    return new Error(`Failed to ${action}: HTTP ${response.status}${code ? ` ${code}` : ''}`);
+  }
+}
+
+function sha256(data: string): string {
+  return crypto.createHash('sha256').update(data).digest('hex');
+}
+
+function hmac(key: crypto.BinaryLike, data: string): Buffer {
+  return crypto.createHmac('sha256', key).update(data).digest();
+}
+
+/**
+ * RFC 3986 encoding, which SigV4 requires; encodeURIComponent leaves !'()* alone
+ */
+function uriEncode(value: string): string {
+  return encodeURIComponent(value).replace(/[!'()*]/g, c => `%${c.charCodeAt(0).toString(16).toUpperCase()}`);
+}
+
+function unescapeXml(value: string): string {
+  return value
+    .replace(/&lt;/g, '<')
+    .replace(/&gt;/g, '>')
+    .replace(/&quot;/g, '"')
+    .replace(/&apos;/g, "'")
+    .replace(/&amp;/g, '&');
+}
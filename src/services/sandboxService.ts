@@ -5,11 +5,19 @@
  * Implements security hardening, resource limits, and timeout enforcement.
  */
 
-import { execFile } from 'child_process';
+import { execFile, spawn, ChildProcess } from 'child_process';
 import { promisify } from 'util';
 import * as path from 'path';
+import * as os from 'os';
+import * as fs from 'fs/promises';
+import crypto from 'crypto';
 import { logger } from './loggerService';
-import { TestExecutionOptions } from '../types/mcp';
+import { currentRunLog } from './runLog';
+import { redactSecrets } from './testEnvironment';
+import { metricsService } from './metricsService';
+import { MountStrategy, TestExecutionOptions } from '../types/mcp';
+import { CancelledError, throwIfCancelled } from '../utils/cancellation';
+import { globToRegExp } from '../utils/globMatcher';
 
 const execFileAsync = promisify(execFile);
 
@@ -18,9 +26,35 @@ export interface SandboxConfig {
   timeout_seconds: number;    // Max execution time
   memory_limit_mb: number;    // Max memory
   cpu_limit: number;          // CPU shares (1.0 = 1 core)
+  pids_limit: number;         // Max processes (prevents fork bombs)
   network_mode: 'none' | 'bridge'; // Network access
+  network?: string;           // Docker network joined instead of network_mode, e.g. the one dependency services run on
   readonly_rootfs: boolean;   // Read-only root filesystem
   tmpfs_size_mb: number;      // Temporary filesystem size
+  env?: Record<string, string>; // Environment variables set in the container
+  secret_env?: Record<string, string>; // Set via a private --env-file so values stay off docker's command line
+  create_retries?: number;    // Overrides the service retry policy for transient create errors
+  artifact_paths?: string[];  // Container paths copied out before the container is removed
+  artifacts_dir?: string;     // Host directory; each container gets its own subdirectory
+  mount?: MountStrategy;      // Default: bind
+  source_volume?: string;     // Copy mounts: the volume from snapshotWorkspace
+  output_paths?: string[];    // Copy mounts: host paths under the workspace kept bind-mounted so output reaches the host
+  registry_mirror?: RegistryMirror; // Fallback for pulls that fail with a network error
+}
+
+export interface RegistryMirror {
+  prefix?: string;            // Prepended to image names no rule matches, e.g. registry.internal/
+  rules?: MirrorRule[];       // Tried in order; the first matching prefix wins
+}
+
+export interface MirrorRule {
+  from: string;               // Image name prefix, e.g. golang:
+  to: string;                 // Its replacement, e.g. registry.internal/library/golang:
+}
+
+export interface ArtifactCopyResult {
+  dir?: string;               // Host directory holding this container's artifacts, if any were copied
+  warnings: string[];         // Artifacts that existed but could not be copied
 }
 
 export interface SandboxExecutionResult {
@@ -29,17 +63,189 @@ export interface SandboxExecutionResult {
   stderr: string;
   timedOut: boolean;
   killedBySignal: boolean;
+  oomKilled: boolean;         // The kernel killed a process for exceeding memory_limit_mb
+  artifacts?: ArtifactCopyResult;
+}
+
+/**
+ * Thin wrapper over the docker CLI so tests can substitute a fake daemon
+ */
+export interface DockerClient {
+  run(
+    args: string[],
+    options?: { timeout?: number; maxBuffer?: number; signal?: AbortSignal }
+  ): Promise<{ stdout: string; stderr: string }>;
+  spawn(args: string[]): ChildProcess;
+}
+
+export interface RetryPolicy {
+  maxRetries: number;         // Retries after the first attempt
+  baseDelayMs: number;        // Doubled after every failed attempt
+  maxDelayMs: number;
+  sleep: (ms: number) => Promise<void>;
+}
+
+export const cliDockerClient: DockerClient = {
+  run: (args, options = {}) => execFileAsync('docker', args, options),
+  spawn: args => spawn('docker', args),
+};
+
+export const DEFAULT_RETRY_POLICY: RetryPolicy = {
+  maxRetries: 3,
+  baseDelayMs: 500,
+  maxDelayMs: 8000,
+  sleep: ms => new Promise(resolve => setTimeout(resolve, ms)),
+};
+
+// docker cp errors for a source path that was never created
+const MISSING_ARTIFACT = /could not find the file|no such container:path/i;
+
+// A hung daemon should count as transient rather than stall the run
+const CREATE_TIMEOUT_MS = 60000;
+
+// Large toolchain images can take minutes on a slow link
+const PULL_TIMEOUT_MS = 300000;
+
+// Left out of every workspace snapshot
+const SNAPSHOT_EXCLUDES = ['/.git/'];
+
+const IGNORE_FILES = ['.dockerignore', '.gitignore'];
+
+const TRANSIENT_DOCKER_ERRORS = [
+  /is already in use by container/i,
+  /cannot connect to the docker daemon/i,
+  /error during connect/i,
+  /connection (refused|reset)/i,
+  /context deadline exceeded/i,
+  /i\/o timeout/i,
+  /tls handshake timeout/i,
+  /unexpected eof/i,
+  /service unavailable/i,
+];
+
+// Pull failures that mean the registry could not be reached, as opposed to refusing the pull
+const NETWORK_PULL_ERRORS = [
+  /connection (refused|reset)/i,
+  /context deadline exceeded/i,
+  /i\/o timeout/i,
+  /tls handshake timeout/i,
+  /unexpected eof/i,
+  /service unavailable/i,
+  /no such host/i,
+  /dial tcp/i,
+  /network is unreachable/i,
+  /temporary failure in name resolution/i,
+  /request canceled/i,
+  /docker pull timed out/i,
+  /bad gateway|gateway time-?out/i,
+];
+
+// A mirror would refuse these the same way, or hide a typo behind a second failure
+const DEFINITIVE_PULL_ERRORS = [
+  /unauthorized/i,
+  /authentication required/i,
+  /denied/i,
+  /manifest (for .* )?(not found|unknown)/i,
+  /repository does not exist/i,
+];
+
+/**
+ * Whether a docker CLI error is worth retrying
+ * Only daemon availability problems and name conflicts qualify; anything
+ * else (missing image, invalid configuration) fails the same way every time.
+ */
+export function isTransient(error: any): boolean {
+  if (!error) {
+    return false;
+  }
+  if (error.killed || error.code === 'ETIMEDOUT') {
+    return true;
+  }
+
+  const text = `${error.stderr || ''}\n${error.message || ''}`;
+  return TRANSIENT_DOCKER_ERRORS.some(pattern => pattern.test(text));
+}
+
+/**
+ * Whether a failed pull should be retried against a registry mirror
+ * Only network-class failures qualify; authentication errors and missing
+ * manifests do not.
+ */
+export function isNetworkPullError(error: any): boolean {
+  if (!error) {
+    return false;
+  }
+
+  const text = `${error.stderr || ''}\n${error.message || ''}`;
+  if (DEFINITIVE_PULL_ERRORS.some(pattern => pattern.test(text))) {
+    return false;
+  }
+  return error.code === 'ETIMEDOUT' || NETWORK_PULL_ERRORS.some(pattern => pattern.test(text));
+}
+
+/**
+ * Parse a registry mirror rewrite rule ("golang:=registry.internal/library/golang:")
+ * @throws Error if the rule has no "=" or an empty side
+ */
+export function parseMirrorRule(rule: string): MirrorRule {
+  const separator = rule.indexOf('=');
+  const from = rule.slice(0, separator).trim();
+  const to = rule.slice(separator + 1).trim();
+  if (separator < 0 || !from || !to) {
+    throw new Error(`Invalid registry mirror rule "${rule}", expected from=to`);
+  }
+  return { from, to };
+}
+
+/**
+ * Name of an image on the mirror
+ * @returns The rewritten name, or undefined when the mirror has no rule for it
+ */
+export function mirrorImage(image: string, mirror: RegistryMirror): string | undefined {
+  const rule = (mirror.rules || []).find(r => image.startsWith(r.from));
+  if (rule) {
+    return rule.to + image.slice(rule.from.length);
+  }
+  if (mirror.prefix) {
+    return mirror.prefix.endsWith('/') ? mirror.prefix + image : `${mirror.prefix}/${image}`;
+  }
+  return undefined;
+}
+
+/**
+ * Parse a Docker-style memory limit ("512m", "2g", "1.5G") into megabytes
+ * Plain numbers are taken as megabytes.
+ * @throws Error if the value is not a valid size
+ */
+export function parseMemoryLimit(value: string): number {
+  const match = value.trim().match(/^(\d+(?:\.\d+)?)\s*([kmg]?)b?$/i);
+  if (!match) {
+    throw new Error(`Invalid memory limit "${value}", expected e.g. 512m or 2g`);
+  }
+
+  const amount = parseFloat(match[1]);
+  const unit = match[2].toLowerCase();
+  const mb = unit === 'g' ? amount * 1024 : unit === 'k' ? amount / 1024 : amount;
+
+  return Math.max(1, Math.round(mb));
 }
 
 export class SandboxService {
   private containerPrefix = 'alcs-test-';
+  private docker: DockerClient;
+  private retryPolicy: RetryPolicy;
+
+  constructor(docker: DockerClient = cliDockerClient, retryPolicy: RetryPolicy = DEFAULT_RETRY_POLICY) {
+    this.docker = docker;
+    this.retryPolicy = retryPolicy;
+  }
 
   /**
    * Check if Docker is available
    */
   async isDockerAvailable(): Promise<boolean> {
     try {
-      await execFileAsync('docker', ['--version']);
+      await this.docker.run(['--version']);
       return true;
     } catch {
       return false;
@@ -52,31 +258,37 @@ export class SandboxService {
    * @param command Command to execute
    * @param workspacePath Path to workspace (will be mounted)
    * @param workDir Working directory inside container
+   * @param signal Aborts the run; the container is still removed
    * @returns Execution result
+   * @throws CancelledError if the signal fired
    */
   async executeInSandbox(
     config: SandboxConfig,
     command: string[],
     workspacePath: string,
-    workDir: string = '/workspace'
+    workDir: string = '/workspace',
+    signal?: AbortSignal
   ): Promise<SandboxExecutionResult> {
-    const containerId = `${this.containerPrefix}${Date.now()}`;
+    let containerId: string | undefined;
 
     logger.info(`Executing in sandbox: ${command.join(' ')}`);
 
     try {
-      // Build docker run command
-      const dockerArgs = this.buildDockerArgs(config, containerId, workspacePath, workDir);
-      dockerArgs.push(...command);
+      containerId = await this.createContainer(config, command, workspacePath, workDir, signal);
 
-      // Execute docker command
-      const { stdout, stderr } = await execFileAsync('docker', dockerArgs, {
+      // Run attached; docker start exits with the container's exit code
+      metricsService.recordContainerStarted();
+      currentRunLog().debug('Container started', { container_id: containerId });
+      const { stdout, stderr } = await this.docker.run(['start', '--attach', containerId], {
         timeout: config.timeout_seconds * 1000,
         maxBuffer: 10 * 1024 * 1024, // 10MB buffer
+        signal,
       });
 
       // Clean up container
+      const artifacts = await this.copyArtifacts(containerId, config);
       await this.removeContainer(containerId);
+      metricsService.recordContainerRemoved();
 
       return {
         exitCode: 0,
@@ -84,14 +296,37 @@ export class SandboxService {
         stderr,
         timedOut: false,
         killedBySignal: false,
+        oomKilled: false,
+        artifacts,
       };
 
     } catch (error: any) {
-      // Clean up container even on error
-      await this.removeContainer(containerId);
+      // Check for an OOM kill before the container (and its state) is removed
+      const oomKilled = containerId ? await this.isOomKilled(containerId) : false;
+
+      // Copy artifacts and clean up container even on error; failed runs need them most
+      const artifacts = containerId ? await this.copyArtifacts(containerId, config) : undefined;
+      if (containerId) {
+        await this.removeContainer(containerId);
+        metricsService.recordContainerRemoved();
+      }
+
+      if (signal?.aborted) {
+        throw error instanceof CancelledError ? error : new CancelledError(`Container ${containerId}`);
+      }
 
       // Check if timeout
-      const timedOut = error.code === 'ETIMEDOUT' || error.killed;
+      const timedOut = Boolean(containerId) && (error.code === 'ETIMEDOUT' || error.killed);
+
+      // A failed create (or exit codes 125-127) means the container never started
+      if (!containerId || this.isSpawnFailure(error)) {
+        metricsService.recordDockerFailure('spawn_failed');
+      } else if (oomKilled) {
+        metricsService.recordDockerFailure('oom');
+        logger.warn(`Container ${containerId} exceeded its ${config.memory_limit_mb}MB memory limit (exit ${error.code})`);
+      } else if (timedOut) {
+        metricsService.recordDockerFailure('timeout');
+      }
 
       return {
         exitCode: error.code || 1,
@@ -99,31 +334,285 @@ export class SandboxService {
         stderr: error.stderr || error.message,
         timedOut,
         killedBySignal: error.killed || false,
+        oomKilled,
+        artifacts,
       };
     }
   }
 
   /**
-   * Build Docker run arguments
+   * Start a command in a sandbox container without waiting for it
+   * Output is streamed from the returned process; the caller must call
+   * finishContainer once it exits.
+   * @param config Sandbox configuration
+   * @param command Command to execute
+   * @param workspacePath Path to workspace (will be mounted)
+   * @param workDir Working directory inside container
+   * @param signal Checked once the container exists; a cancelled create is cleaned up
+   * @returns The docker client process and the container name
+   * @throws If the container cannot be created, or CancelledError
+   */
+  async spawnInSandbox(
+    config: SandboxConfig,
+    command: string[],
+    workspacePath: string,
+    workDir: string = '/workspace',
+    signal?: AbortSignal
+  ): Promise<{ child: ChildProcess; containerId: string }> {
+    const containerId = await this.createContainer(config, command, workspacePath, workDir, signal);
+
+    logger.info(`Starting sandbox ${containerId}: ${command.join(' ')}`);
+    metricsService.recordContainerStarted();
+    currentRunLog().debug('Container started', { container_id: containerId });
+
+    return { child: this.docker.spawn(['start', '--attach', containerId]), containerId };
+  }
+
+  /**
+   * Create a container, retrying transient daemon errors with exponential backoff
+   * Each attempt uses a fresh name so a create that timed out but still
+   * registered its name cannot conflict with the retry.
+   * The create itself is never interrupted: cancellation is checked once it
+   * settles, so a container the daemon finished creating is always removed
+   * rather than orphaned.
+   * @returns Name of the created container
+   * @throws CancelledError if the signal fired, after removing the container
+   */
+  private async createContainer(
+    config: SandboxConfig,
+    command: string[],
+    workspacePath: string,
+    workDir: string,
+    signal?: AbortSignal
+  ): Promise<string> {
+    const maxRetries = config.create_retries ?? this.retryPolicy.maxRetries;
+    const envFile = await this.writeSecretEnvFile(config.secret_env);
+
+    try {
+      return await this.createWithRetries(config, command, workspacePath, workDir, maxRetries, envFile, signal);
+    } finally {
+      if (envFile) {
+        // docker create has copied the values into the container config
+        await fs.rm(path.dirname(envFile), { recursive: true, force: true });
+      }
+    }
+  }
+
+  /**
+   * The create attempts of createContainer, sharing one secret env file
    */
-  private buildDockerArgs(
+  private async createWithRetries(
+    config: SandboxConfig,
+    command: string[],
+    workspacePath: string,
+    workDir: string,
+    maxRetries: number,
+    envFile: string | undefined,
+    signal?: AbortSignal
+  ): Promise<string> {
+    for (let attempt = 0; ; attempt++) {
+      throwIfCancelled(signal, 'Container creation');
+
+      const containerId = this.newContainerId();
+      const dockerArgs = this.buildDockerArgs(config, containerId, workspacePath, workDir, envFile);
+      dockerArgs.push(...command);
+      logger.debug(`Creating container: docker ${redactSecrets(dockerArgs.join(' '), config.secret_env)}`);
+
+      let createError: any;
+      try {
+        await this.docker.run(dockerArgs, { timeout: CREATE_TIMEOUT_MS });
+      } catch (error: any) {
+        createError = error;
+        await this.removeContainer(containerId); // May exist if the daemon finished after we gave up
+      }
+
+      if (signal?.aborted) {
+        if (!createError) {
+          await this.removeContainer(containerId);
+        }
+        throw new CancelledError(`Container ${containerId}`);
+      }
+
+      if (!createError) {
+        currentRunLog().debug('Container created', { container_id: containerId, image: config.image, attempt: attempt + 1 });
+        return containerId;
+      }
+
+      if (attempt >= maxRetries || !isTransient(createError)) {
+        metricsService.recordDockerFailure('create_failed');
+        throw createError;
+      }
+
+      const delay = Math.min(this.retryPolicy.baseDelayMs * Math.pow(2, attempt), this.retryPolicy.maxDelayMs);
+      // The error message repeats the full docker command line
+      const detail = redactSecrets((createError.stderr || createError.message || '').trim(), config.secret_env);
+      currentRunLog().debug('Container create failed, retrying', {
+        container_id: containerId, attempt: attempt + 1, delay_ms: delay, error: detail,
+      });
+      logger.warn(
+        `Transient error creating container (attempt ${attempt + 1}/${maxRetries + 1}), ` +
+        `retrying in ${delay}ms: ${detail}`
+      );
+      await this.retryPolicy.sleep(delay);
+    }
+  }
+
+  /**
+   * Write secret variables to an env file only the current user can read
+   * @returns Path of the file, or undefined when there are no secrets
+   */
+  private async writeSecretEnvFile(secrets: Record<string, string> = {}): Promise<string | undefined> {
+    const names = Object.keys(secrets);
+    if (names.length === 0) {
+      return undefined;
+    }
+
+    const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-env-'));
+    const file = path.join(dir, 'secrets.env');
+    await fs.writeFile(file, names.map(name => `${name}=${secrets[name]}\n`).join(''), { encoding: 'utf-8', mode: 0o600 });
+    return file;
+  }
+
+  /**
+   * Inspect, copy artifacts out of, and remove a container started with spawnInSandbox
+   * @param containerId Container name
+   * @param exitCode Exit code of the docker client
+   * @param config Config the container was started with (for artifact paths)
+   * @returns Whether the container was OOM-killed, and where its artifacts went
+   */
+  async finishContainer(
+    containerId: string,
+    exitCode: number,
+    config?: SandboxConfig
+  ): Promise<{ oomKilled: boolean; artifacts?: ArtifactCopyResult }> {
+    const oomKilled = exitCode !== 0 && await this.isOomKilled(containerId);
+    const artifacts = config ? await this.copyArtifacts(containerId, config) : undefined;
+
+    await this.removeContainer(containerId);
+    metricsService.recordContainerRemoved();
+
+    if (this.isSpawnFailure({ code: exitCode })) {
+      metricsService.recordDockerFailure('spawn_failed');
+    } else if (oomKilled) {
+      metricsService.recordDockerFailure('oom');
+      logger.warn(`Container ${containerId} was OOM-killed (exit ${exitCode})`);
+    }
+
+    return { oomKilled, artifacts };
+  }
+
+  /**
+   * Copy declared artifact paths out of a stopped container
+   * Paths the tests never created are skipped silently; a copy that fails for
+   * an existing path is a warning, never a failed run. Note that docker cp
+   * cannot read tmpfs mounts, so artifacts under the /tmp tmpfs are not
+   * retrievable once the container exits.
+   * @param containerId Container name, also used as the host subdirectory
+   * @param config Sandbox configuration with artifact_paths and artifacts_dir
+   */
+  async copyArtifacts(containerId: string, config: SandboxConfig): Promise<ArtifactCopyResult | undefined> {
+    if (!config.artifacts_dir || !config.artifact_paths || config.artifact_paths.length === 0) {
+      return undefined;
+    }
+
+    const runDir = path.join(config.artifacts_dir, containerId);
+    const warnings: string[] = [];
+    let copied = 0;
+
+    for (const artifact of config.artifact_paths) {
+      const destination = path.join(runDir, artifact.replace(/^\/+/, ''));
+      try {
+        await fs.mkdir(path.dirname(destination), { recursive: true });
+        await this.docker.run(['cp', `${containerId}:${artifact}`, destination]);
+        copied++;
+      } catch (error: any) {
+        const detail = (error.stderr || error.message || '').trim();
+        if (MISSING_ARTIFACT.test(detail)) {
+          logger.debug(`Artifact ${artifact} not present in ${containerId}, skipping`);
+          continue;
+        }
+        const warning = `Failed to copy artifact ${artifact} from ${containerId}: ${detail}`;
+        logger.warn(warning);
+        warnings.push(warning);
+      }
+    }
+
+    if (copied === 0) {
+      await fs.rm(runDir, { recursive: true, force: true });
+      return { warnings };
+    }
+
+    logger.info(`Copied ${copied} artifact path(s) from ${containerId} to ${runDir}`);
+    return { dir: runDir, warnings };
+  }
+
+  /**
+   * Send a signal to the processes in a container
+   * SIGKILL stops the container; other signals go to every process except
+   * PID 1 so they reach the test binary rather than only the go command.
+   */
+  async signalContainer(containerId: string, signal: NodeJS.Signals): Promise<void> {
+    try {
+      if (signal === 'SIGKILL') {
+        await this.docker.run(['kill', containerId]);
+      } else {
+        await this.docker.run(['exec', containerId, 'kill', '-s', signal.replace(/^SIG/, ''), '-1']);
+      }
+    } catch (error: any) {
+      logger.debug(`Failed to signal container ${containerId}: ${error.message}`);
+    }
+  }
+
+  /**
+   * Whether the kernel OOM killer fired inside the container
+   * Exit code 137 alone is ambiguous (any SIGKILL), so Docker's OOMKilled
+   * state is the source of truth.
+   */
+  private async isOomKilled(containerId: string): Promise<boolean> {
+    try {
+      const { stdout } = await this.docker.run(['inspect', '--format', '{{.State.OOMKilled}}', containerId]);
+      return stdout.trim() === 'true';
+    } catch {
+      return false;
+    }
+  }
+
+  /**
+   * Unique container name; runs may start several containers per millisecond
+   */
+  private newContainerId(): string {
+    return `${this.containerPrefix}${Date.now()}-${crypto.randomBytes(3).toString('hex')}`;
+  }
+
+  /**
+   * Check whether a docker run error means the container failed to start
+   */
+  private isSpawnFailure(error: any): boolean {
+    return error.code === 'ENOENT' || error.code === 125 || error.code === 126 || error.code === 127;
+  }
+
+  /**
+   * Build Docker create arguments
+   */
+  buildDockerArgs(
     config: SandboxConfig,
     containerId: string,
     workspacePath: string,
-    workDir: string
+    workDir: string,
+    envFile?: string
   ): string[] {
     const args = [
-      'run',
-      '--rm', // Auto-remove container after execution
-      '--name', containerId,
+      'create',
+      '--name', containerId, // Removed explicitly after its OOM state is inspected
 
       // Resource limits
       `--memory=${config.memory_limit_mb}m`,
+      `--memory-swap=${config.memory_limit_mb}m`, // No swap, so the limit is enforced by OOM kill
       `--cpus=${config.cpu_limit}`,
-      '--pids-limit=100', // Prevent fork bombs
+      `--pids-limit=${config.pids_limit}`, // Prevent fork bombs
 
       // Network isolation
-      `--network=${config.network_mode}`,
+      `--network=${config.network || config.network_mode}`,
 
       // Security options
       '--security-opt=no-new-privileges', // Prevent privilege escalation
@@ -136,7 +625,7 @@ export class SandboxService {
       '--user=1000:1000',
 
       // Mount workspace
-      `--volume=${workspacePath}:${workDir}:rw`,
+      ...this.buildMountArgs(config, workspacePath, workDir),
       '--workdir', workDir,
 
       // Tmpfs for temporary files (ephemeral, memory-backed)
@@ -148,19 +637,171 @@ export class SandboxService {
       args.push('--read-only');
     }
 
+    for (const [name, value] of Object.entries(config.env || {})) {
+      args.push('--env', `${name}=${value}`);
+    }
+    if (envFile) {
+      args.push('--env-file', envFile);
+    }
+
     // Add image
     args.push(config.image);
 
     return args;
   }
 
+  /**
+   * Volume arguments for the workspace
+   * A copy mount puts the snapshot volume at workDir, with output paths
+   * bind-mounted over it at their place in the workspace.
+   * @throws If a copy mount has no snapshot volume
+   */
+  private buildMountArgs(config: SandboxConfig, workspacePath: string, workDir: string): string[] {
+    if (config.mount !== 'copy') {
+      return [`--volume=${workspacePath}:${workDir}:rw`];
+    }
+    if (!config.source_volume) {
+      throw new Error('Copy mount requested without a workspace snapshot; call snapshotWorkspace first');
+    }
+
+    return [
+      `--volume=${config.source_volume}:${workDir}:rw`,
+      ...(config.output_paths || []).map(hostPath =>
+        `--volume=${hostPath}:${path.posix.join(workDir, path.relative(workspacePath, hostPath).split(path.sep).join('/'))}:rw`
+      ),
+    ];
+  }
+
+  /**
+   * Snapshot the workspace into a volume for copy-mounted containers
+   * Containers then read the source from local disk instead of a (possibly
+   * network) bind mount, and every container of a run sees the same frozen
+   * source. Paths matching .dockerignore, .gitignore, the extra excludes, or
+   * the config's output paths (which stay bind-mounted) are left out. The
+   * copy is staged locally, then copied in through a helper container that
+   * hands the files to the container user.
+   * @param config Sandbox configuration; its image runs the helper
+   * @param workspacePath Workspace to snapshot
+   * @param workDir Where containers mount the workspace
+   * @param exclude Extra glob patterns, relative to the workspace
+   * @returns Name of the volume, to set as source_volume
+   * @throws If the snapshot cannot be created; nothing is left behind
+   */
+  async snapshotWorkspace(
+    config: SandboxConfig,
+    workspacePath: string,
+    workDir: string,
+    exclude: string[] = []
+  ): Promise<string> {
+    const outputs = (config.output_paths || []).map(p => `/${path.relative(workspacePath, p).split(path.sep).join('/')}/`);
+    const patterns = [...SNAPSHOT_EXCLUDES, ...await this.readIgnorePatterns(workspacePath), ...exclude, ...outputs]
+      .map(pattern => globToRegExp(pattern));
+
+    const volume = `alcs-src-${Date.now()}-${crypto.randomBytes(3).toString('hex')}`;
+    const helper = this.newContainerId();
+    const stage = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-src-'));
+    let files = 0;
+    let failure: any;
+
+    try {
+      // Output paths must exist before docker bind-mounts them, or it creates them as root
+      for (const outputPath of config.output_paths || []) {
+        await fs.mkdir(outputPath, { recursive: true });
+      }
+
+      await fs.cp(workspacePath, stage, {
+        recursive: true,
+        filter: async source => {
+          const rel = path.relative(workspacePath, source).split(path.sep).join('/');
+          if (rel !== '' && patterns.some(pattern => pattern.test(rel))) {
+            return false;
+          }
+          if (!(await fs.lstat(source)).isDirectory()) {
+            files++;
+          }
+          return true;
+        },
+      });
+
+      await this.docker.run(['volume', 'create', '--label', 'alcs.snapshot=true', volume]);
+      await this.docker.run([
+        'create', '--name', helper, '--network=none', `--volume=${volume}:${workDir}`,
+        config.image, 'chown', '-R', '1000:1000', workDir,
+      ], { timeout: CREATE_TIMEOUT_MS });
+      await this.docker.run(['cp', `${stage}/.`, `${helper}:${workDir}`], { maxBuffer: 10 * 1024 * 1024 });
+      await this.docker.run(['start', '--attach', helper]);
+    } catch (error: any) {
+      failure = error;
+    } finally {
+      await this.removeContainer(helper);
+      await fs.rm(stage, { recursive: true, force: true });
+    }
+
+    if (failure) {
+      await this.removeWorkspaceSnapshot(volume);
+      throw new Error(`Failed to snapshot ${workspacePath} for a copy mount: ${(failure.stderr || failure.message || '').trim()}`);
+    }
+
+    logger.info(`Snapshotted ${files} files from ${workspacePath} into volume ${volume}`);
+    currentRunLog().debug('Workspace snapshotted', { volume, files });
+    return volume;
+  }
+
+  /**
+   * Remove a volume created by snapshotWorkspace
+   */
+  async removeWorkspaceSnapshot(volume: string): Promise<void> {
+    try {
+      await this.docker.run(['volume', 'rm', '-f', volume]);
+      currentRunLog().debug('Workspace snapshot removed', { volume });
+    } catch (error: any) {
+      logger.debug(`Failed to remove volume ${volume}: ${error.message}`);
+    }
+  }
+
+  /**
+   * Exclude patterns from the workspace's ignore files
+   * .dockerignore entries are relative to the root, so they are anchored;
+   * .gitignore entries without a slash match at any depth, as in git.
+   * Negations (!pattern) are not supported and are skipped.
+   */
+  private async readIgnorePatterns(workspacePath: string): Promise<string[]> {
+    const patterns: string[] = [];
+
+    for (const file of IGNORE_FILES) {
+      let content: string;
+      try {
+        content = await fs.readFile(path.join(workspacePath, file), 'utf-8');
+      } catch {
+        continue;
+      }
+
+      for (const raw of content.split('\n')) {
+        const line = raw.trim();
+        if (line === '' || line.startsWith('#')) {
+          continue;
+        }
+        if (line.startsWith('!')) {
+          logger.debug(`Ignoring negated pattern ${line} in ${file}; copy mounts do not support negation`);
+          continue;
+        }
+
+        const anchored = file === '.dockerignore' || line.replace(/\/$/, '').includes('/');
+        patterns.push(anchored && !line.startsWith('/') && !line.startsWith('**') ? `/${line}` : line);
+      }
+    }
+
+    return patterns;
+  }
+
   /**
    * Remove container (cleanup)
    */
   private async removeContainer(containerId: string): Promise<void> {
     try {
-      await execFileAsync('docker', ['rm', '-f', containerId]);
+      await this.docker.run(['rm', '-f', containerId]);
       logger.debug(`Removed container: ${containerId}`);
+      currentRunLog().debug('Container removed', { container_id: containerId });
     } catch (error: any) {
       // Ignore errors if container doesn't exist
       logger.debug(`Failed to remove container ${containerId}: ${error.message}`);
@@ -174,8 +815,15 @@ export class SandboxService {
     return {
       image: 'python:3.11-slim', // Default image
       timeout_seconds: options.timeout_seconds || 300,
-      memory_limit_mb: options.memory_limit_mb || 512,
+      memory_limit_mb: options.memory ? parseMemoryLimit(options.memory) : options.memory_limit_mb || 512,
       cpu_limit: options.cpu_limit || 1.0,
+      pids_limit: options.pids_limit || 100,
+      create_retries: options.container_create_retries,
+      artifact_paths: options.artifacts,
+      artifacts_dir: options.artifacts_dir,
+      registry_mirror: options.registry_mirror || options.registry_mirror_rules
+        ? { prefix: options.registry_mirror, rules: (options.registry_mirror_rules || []).map(parseMirrorRule) }
+        : undefined,
       network_mode: options.enable_network ? 'bridge' : 'none',
       readonly_rootfs: false, // Allow writes to workspace
       tmpfs_size_mb: 100,
@@ -190,6 +838,7 @@ export class SandboxService {
       'pytest': 'python:3.11-slim',
       'jest': 'node:20-alpine',
       'go_testing': 'golang:1.21-alpine',
+      'ginkgo': 'golang:1.21-alpine',
       'junit5': 'eclipse-temurin:17-jdk-alpine',
       'rust_test': 'rust:1.75-alpine',
       'gtest': 'gcc:13-alpine',
@@ -203,7 +852,7 @@ export class SandboxService {
    */
   async imageExists(image: string): Promise<boolean> {
     try {
-      await execFileAsync('docker', ['image', 'inspect', image]);
+      await this.docker.run(['image', 'inspect', image]);
       return true;
     } catch {
       return false;
@@ -212,13 +861,50 @@ export class SandboxService {
 
   /**
    * Pull Docker image if not available
+   * docker pull's per-layer progress is forwarded line by line so a slow
+   * pull is visibly making progress.
+   * @param onProgress Receives each progress line (default: logged)
    */
-  async pullImage(image: string): Promise<void> {
+  async pullImage(
+    image: string,
+    onProgress: (line: string) => void = line => logger.info(`Pulling ${image}: ${line}`)
+  ): Promise<void> {
     logger.info(`Pulling Docker image: ${image}`);
 
     try {
-      await execFileAsync('docker', ['pull', image], {
-        timeout: 300000, // 5 minutes for image pull
+      await new Promise<void>((resolve, reject) => {
+        const child = this.docker.spawn(['pull', image]);
+        let pending = '';
+        let stderr = '';
+
+        const timer = setTimeout(() => {
+          child.kill('SIGKILL');
+          reject(new Error(`docker pull timed out after ${PULL_TIMEOUT_MS / 1000}s`));
+        }, PULL_TIMEOUT_MS);
+
+        child.stdout?.on('data', (chunk: Buffer) => {
+          const lines = (pending + chunk.toString()).split('\n');
+          pending = lines.pop() || '';
+          lines.map(l => l.trim()).filter(l => l).forEach(onProgress);
+        });
+        child.stderr?.on('data', (chunk: Buffer) => {
+          stderr += chunk.toString();
+        });
+        child.on('error', error => {
+          clearTimeout(timer);
+          reject(error);
+        });
+        child.on('close', code => {
+          clearTimeout(timer);
+          if (pending.trim()) {
+            onProgress(pending.trim());
+          }
+          if (code === 0) {
+            resolve();
+          } else {
+            reject(new Error(stderr.trim() || `docker pull exited with code ${code}`));
+          }
+        });
       });
       logger.info(`Successfully pulled image: ${image}`);
     } catch (error: any) {
@@ -229,11 +915,31 @@ export class SandboxService {
 
   /**
    * Ensure Docker image is available (pull if needed)
+   * When the pull fails with a network error and a mirror is configured,
+   * the image is pulled from the mirror and tagged with its original name,
+   * so containers are created from the same name either way.
+   * @param onProgress Receives docker pull progress lines
+   * @param mirror Registry mirror to fall back to
+   * @throws If the image is missing and cannot be pulled
    */
-  async ensureImage(image: string): Promise<void> {
+  async ensureImage(image: string, onProgress?: (line: string) => void, mirror?: RegistryMirror): Promise<void> {
     const exists = await this.imageExists(image);
-    if (!exists) {
-      await this.pullImage(image);
+    if (exists) {
+      return;
+    }
+
+    try {
+      await this.pullImage(image, onProgress);
+    } catch (error: any) {
+      const mirrored = mirror && mirrorImage(image, mirror);
+      if (!mirrored || mirrored === image || !isNetworkPullError(error)) {
+        throw error;
+      }
+
+      logger.warn(`Pulling ${image} failed with a network error; falling back to mirror ${mirrored}`);
+      currentRunLog().debug('Image pull falling back to mirror', { image, mirror_image: mirrored });
+      await this.pullImage(mirrored, onProgress);
+      await this.docker.run(['tag', mirrored, image]);
     }
   }
 
@@ -242,7 +948,7 @@ export class SandboxService {
    */
   async listRunningContainers(): Promise<string[]> {
     try {
-      const { stdout } = await execFileAsync('docker', [
+      const { stdout } = await this.docker.run([
         'ps',
         '--filter', `name=${this.containerPrefix}`,
         '--format', '{{.Names}}',
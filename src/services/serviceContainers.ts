@@ -0,0 +1,249 @@
+/**
+ * Service Containers
+ *
+ * Dependency services (databases, queues, caches) a run's tests talk to.
+ * Each one runs in its own container, started before the tests and removed
+ * after them whether they pass or not. A service with a health_cmd is only
+ * considered started once that command exits 0 inside its container; until
+ * then the tests would fail with connection errors that say nothing about
+ * the real problem, so a service still unhealthy at its deadline aborts the
+ * run instead.
+ *
+ * Sandboxed tests reach the services over a per-run Docker network, by
+ * service name. Host tests reach them through ports published on 127.0.0.1.
+ * Either way the address is passed to the tests as <NAME>_HOST, <NAME>_PORT
+ * and <NAME>_ADDR, plus <NAME>_PORT_<port> for every listed port.
+ */
+
+import crypto from 'crypto';
+import { ServiceDependency } from '../types/mcp';
+import { logger } from './loggerService';
+import { currentRunLog } from './runLog';
+import { cliDockerClient, DockerClient } from './sandboxService';
+
+export interface RunningServices {
+  network?: string;           // Network the test containers join; unset when the tests run on the host
+  containers: string[];
+  env: Record<string, string>; // Service addresses for the test processes
+}
+
+export interface ServiceStartOptions {
+  sandbox: boolean;           // Tests run in containers, so services are reached over a shared network
+  enableNetwork?: boolean;    // Sandboxed: let the network reach beyond its members
+}
+
+/**
+ * A service's health command kept failing until its deadline, or its container exited
+ */
+export class DependencyUnhealthyError extends Error {
+  constructor(service: string, reason: string) {
+    super(`Dependency ${service} unhealthy: ${reason}`);
+    this.name = 'DependencyUnhealthyError';
+  }
+}
+
+const SERVICE_NAME = /^[A-Za-z0-9][A-Za-z0-9_.-]*$/;
+
+// docker run pulls a missing image first
+const START_TIMEOUT_MS = 300000;
+
+const NOT_RUNNING = /is not running|no such container/i;
+
+/**
+ * Prefix of a service's environment variables: redis-cache -> REDIS_CACHE
+ */
+export function serviceEnvPrefix(name: string): string {
+  return name.toUpperCase().replace(/[^A-Z0-9]/g, '_');
+}
+
+export class ServiceContainers {
+  constructor(
+    private docker: DockerClient = cliDockerClient,
+    private sleep: (ms: number) => Promise<void> = ms => new Promise(resolve => setTimeout(resolve, ms)),
+    private now: () => number = () => Date.now()
+  ) {}
+
+  /**
+   * Start the services and wait for each to become healthy
+   * @throws DependencyUnhealthyError if a service does not become healthy;
+   *         whatever was started is removed before any error is thrown
+   */
+  async start(services: ServiceDependency[], options: ServiceStartOptions): Promise<RunningServices> {
+    this.validate(services);
+
+    const group = `${Date.now()}-${crypto.randomBytes(3).toString('hex')}`;
+    const running: RunningServices = { containers: [], env: {} };
+
+    try {
+      if (options.sandbox) {
+        running.network = `alcs-svc-${group}`;
+        // Internal unless the run allows network access, so tests stay as isolated as with --network=none
+        await this.docker.run([
+          'network', 'create', '--label', 'alcs.services=true',
+          ...(options.enableNetwork ? [] : ['--internal']),
+          running.network,
+        ]);
+      }
+
+      for (const service of services) {
+        const container = `alcs-svc-${group}-${service.name}`;
+        await this.docker.run(this.runArgs(service, container, running.network), { timeout: START_TIMEOUT_MS });
+        running.containers.push(container);
+        Object.assign(running.env, await this.addressEnv(service, container, running.network));
+      }
+
+      // Started together, so a slow service does not hold back the others' startup
+      await Promise.all(services.map((service, i) => this.waitHealthy(service, running.containers[i])));
+    } catch (error: any) {
+      await this.stop(running);
+      if (error instanceof DependencyUnhealthyError) {
+        throw error;
+      }
+      throw new Error(`Failed to start dependency services: ${(error.stderr || error.message || '').trim()}`);
+    }
+
+    logger.info(`Started ${services.length} dependency services: ${services.map(s => s.name).join(', ')}`);
+    currentRunLog().debug('Services started', { network: running.network, containers: running.containers });
+    return running;
+  }
+
+  /**
+   * Remove the service containers and their network
+   * Failures are logged; a leftover container must not fail the run.
+   */
+  async stop(running: RunningServices): Promise<void> {
+    for (const container of running.containers) {
+      try {
+        await this.docker.run(['rm', '-f', '-v', container]);
+      } catch (error: any) {
+        logger.warn(`Failed to remove service container ${container}: ${error.message}`);
+      }
+    }
+    if (running.network) {
+      try {
+        await this.docker.run(['network', 'rm', running.network]);
+      } catch (error: any) {
+        logger.warn(`Failed to remove service network ${running.network}: ${error.message}`);
+      }
+    }
+    currentRunLog().debug('Services stopped', { network: running.network, containers: running.containers });
+  }
+
+  private validate(services: ServiceDependency[]): void {
+    const prefixes = new Set<string>();
+    for (const service of services) {
+      if (!SERVICE_NAME.test(service.name)) {
+        throw new Error(`Invalid service name "${service.name}": use letters, digits, '.', '_' and '-'`);
+      }
+      if (!service.image) {
+        throw new Error(`Service ${service.name} has no image`);
+      }
+      const prefix = serviceEnvPrefix(service.name);
+      if (prefixes.has(prefix)) {
+        throw new Error(`Services named like ${service.name} would share the ${prefix}_* variables`);
+      }
+      prefixes.add(prefix);
+    }
+  }
+
+  private runArgs(service: ServiceDependency, container: string, network?: string): string[] {
+    const args = ['run', '--detach', '--name', container, '--label', 'alcs.services=true'];
+    if (network) {
+      args.push(`--network=${network}`, `--network-alias=${service.name}`);
+    } else {
+      // Random host ports, so concurrent runs do not collide
+      for (const port of service.ports || []) {
+        args.push('--publish', `127.0.0.1::${port}`);
+      }
+    }
+    for (const [key, value] of Object.entries(service.env || {})) {
+      args.push('--env', `${key}=${value}`);
+    }
+    args.push(service.image);
+    return args;
+  }
+
+  /**
+   * Variables telling the tests where a service listens
+   */
+  private async addressEnv(service: ServiceDependency, container: string, network?: string): Promise<Record<string, string>> {
+    const prefix = serviceEnvPrefix(service.name);
+    const host = network ? service.name : '127.0.0.1';
+    const env: Record<string, string> = { [`${prefix}_HOST`]: host };
+
+    for (const [i, port] of (service.ports || []).entries()) {
+      const reachable = network ? String(port) : await this.publishedPort(container, port);
+      env[`${prefix}_PORT_${port}`] = reachable;
+      if (i === 0) {
+        env[`${prefix}_PORT`] = reachable;
+        env[`${prefix}_ADDR`] = `${host}:${reachable}`;
+      }
+    }
+    return env;
+  }
+
+  /**
+   * Host port docker picked for a container port, from `docker port`: 127.0.0.1:49153
+   */
+  private async publishedPort(container: string, port: number): Promise<string> {
+    const { stdout } = await this.docker.run(['port', container, `${port}/tcp`]);
+    const mapped = stdout.trim().split('\n')[0].match(/:(\d+)$/);
+    if (!mapped) {
+      throw new Error(`No host port published for ${container} port ${port}`);
+    }
+    return mapped[1];
+  }
+
+  /**
+   * Poll the service's health command until it exits 0
+   * @throws DependencyUnhealthyError at the deadline, or as soon as the container exits
+   */
+  private async waitHealthy(service: ServiceDependency, container: string): Promise<void> {
+    if (!service.health_cmd || service.health_cmd.length === 0) {
+      logger.warn(`Service ${service.name} has no health_cmd; tests may start before it is ready`);
+      return;
+    }
+
+    const timeoutSeconds = service.health_timeout_seconds ?? 60;
+    const interval = service.health_interval_ms ?? 1000;
+    const started = this.now();
+    const deadline = started + timeoutSeconds * 1000;
+    const command = service.health_cmd.join(' ');
+
+    for (let attempt = 1; ; attempt++) {
+      let output: string;
+      try {
+        await this.docker.run(['exec', container, ...service.health_cmd], { timeout: Math.max(1000, deadline - this.now()) });
+        logger.info(`Service ${service.name} healthy after ${attempt} checks (${this.now() - started}ms)`);
+        return;
+      } catch (error: any) {
+        output = (error.stderr || error.stdout || error.message || '').trim();
+      }
+
+      if (NOT_RUNNING.test(output)) {
+        throw new DependencyUnhealthyError(service.name, `container exited before ${command} passed${await this.logTail(container)}`);
+      }
+      if (this.now() + interval >= deadline) {
+        throw new DependencyUnhealthyError(
+          service.name,
+          `${command} still failing after ${timeoutSeconds}s (${attempt} checks)${output ? `: ${output}` : ''}`
+        );
+      }
+      currentRunLog().debug('Service not healthy yet', { service: service.name, attempt });
+      await this.sleep(interval);
+    }
+  }
+
+  private async logTail(container: string): Promise<string> {
+    try {
+      const { stdout, stderr } = await this.docker.run(['logs', '--tail', '20', container]);
+      const logs = `${stdout}${stderr}`.trim();
+      return logs ? `; last output:\n${logs}` : '';
+    } catch {
+      return '';
+    }
+  }
+}
+
+// Export singleton instance
+export const serviceContainers = new ServiceContainers();
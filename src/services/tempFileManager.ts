@@ -175,6 +175,7 @@ export class TempFileManager {
       'pytest': '.py',
       'jest': '.test.js',
       'go_testing': '_test.go',
+      'ginkgo': '_test.go',
       'rust_test': '.rs',
       'junit5': '.java',
       'gtest': '.cpp',
@@ -0,0 +1,119 @@
+/**
+ * Test Environment
+ *
+ * Resolves the variables a run injects into its test processes: KEY=VALUE
+ * pairs from the options, an env file, and secrets read from files. The
+ * runner applies them to go test only (its container, or the host process),
+ * never to go list, go env, or other helper invocations.
+ *
+ * Secret values are kept apart from plain variables so the sandbox can pass
+ * them without putting them on docker's command line, and so anything that
+ * logs a container spec can redact them.
+ */
+
+import * as fs from 'fs/promises';
+import { TestExecutionOptions } from '../types/mcp';
+
+export interface TestEnvironment {
+  env: Record<string, string>;      // Plain variables; safe to log
+  secrets: Record<string, string>;  // Values read from secret files; never logged
+}
+
+const NAME = /^[A-Za-z_][A-Za-z0-9_]*$/;
+
+const REDACTED = '***';
+
+/**
+ * Replace every secret value in text
+ * @param text Text that may contain secret values, e.g. a docker command line
+ * @param secrets Secret variables whose values to hide
+ */
+export function redactSecrets(text: string, secrets: Record<string, string> = {}): string {
+  // Longest first so a secret containing another is hidden whole
+  const values = Object.values(secrets).filter(v => v.length > 0).sort((a, b) => b.length - a.length);
+  return values.reduce((redacted, value) => redacted.split(value).join(REDACTED), text);
+}
+
+export class TestEnvironmentLoader {
+  /**
+   * Resolve the variables to inject for a run
+   * env_file is applied first and env entries override it; a secret wins
+   * over a plain variable of the same name.
+   * @param options env, env_file, and secret_files
+   * @throws If an entry is malformed or a file cannot be read
+   */
+  async load(options: TestExecutionOptions): Promise<TestEnvironment> {
+    const env: Record<string, string> = {};
+    const secrets: Record<string, string> = {};
+
+    if (options.env_file) {
+      let content: string;
+      try {
+        content = await fs.readFile(options.env_file, 'utf-8');
+      } catch (error: any) {
+        throw new Error(`Cannot read env file ${options.env_file}: ${error.message}`);
+      }
+      Object.assign(env, this.parseEnvFile(content, options.env_file));
+    }
+
+    for (const entry of options.env || []) {
+      const [name, value] = this.splitEntry(entry, 'env');
+      env[name] = value;
+    }
+
+    for (const entry of options.secret_files || []) {
+      const [name, file] = this.splitEntry(entry, 'secret_files');
+      let value: string;
+      try {
+        value = (await fs.readFile(file, 'utf-8')).replace(/\r?\n$/, '');
+      } catch (error: any) {
+        throw new Error(`Cannot read secret file for ${name}: ${error.message}`);
+      }
+      // docker --env-file holds one variable per line
+      if (/[\r\n]/.test(value)) {
+        throw new Error(`Secret ${name} spans several lines; only single-line values are supported`);
+      }
+      secrets[name] = value;
+      delete env[name];
+    }
+
+    return { env, secrets };
+  }
+
+  /**
+   * Parse KEY=VALUE lines
+   * Blank lines, # comments, and a leading "export " are ignored, and one
+   * pair of matching quotes around a value is removed.
+   */
+  parseEnvFile(content: string, source: string = 'env file'): Record<string, string> {
+    const env: Record<string, string> = {};
+
+    content.split('\n').forEach((raw, index) => {
+      const line = raw.trim();
+      if (line === '' || line.startsWith('#')) {
+        return;
+      }
+
+      const [name, value] = this.splitEntry(line.replace(/^export\s+/, ''), `${source}:${index + 1}`);
+      const quoted = value.match(/^(['"])(.*)\1$/);
+      env[name] = quoted ? quoted[2] : value;
+    });
+
+    return env;
+  }
+
+  /**
+   * Split NAME=VALUE at the first '='
+   */
+  private splitEntry(entry: string, source: string): [string, string] {
+    const eq = entry.indexOf('=');
+    const name = eq > 0 ? entry.slice(0, eq).trim() : '';
+    if (!NAME.test(name)) {
+      throw new Error(`Invalid ${source} entry "${entry.slice(0, 40)}", expected NAME=VALUE`);
+    }
+    return [name, entry.slice(eq + 1)];
+  }
+}
+
+// Export singleton instance
+export const testEnvironmentLoader = new TestEnvironmentLoader();
@@ -0,0 +1,95 @@
+/**
+ * Test Label Scanner
+ *
+ * Extracts `// +alcs:<label>` annotations from the doc comments of Go tests
+ * so runs can select slices of a suite (integration, slow, ...) without
+ * build tags. Labels are not part of test names, so the runner resolves a
+ * label selection to concrete top-level test names and passes those to -run.
+ *
+ *   // TestCheckout talks to a real database.
+ *   // +alcs:integration +alcs:slow
+ *   func TestCheckout(t *testing.T) {
+ *
+ * Several labels may share a line or be comma-separated (+alcs:slow,flaky).
+ * The annotation must be in the comment block directly above the func.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+
+const TEST_FUNC = /^func\s+((?:Test|Example|Fuzz)\w*)\s*\(/;
+
+const LABEL = /\+alcs:([\w,-]+)/g;
+
+export interface LabelSelection {
+  labels?: string[];          // Keep tests with at least one of these
+  exclude_labels?: string[];  // Drop tests with any of these
+}
+
+export class TestLabelScanner {
+  /**
+   * Labels of every top-level test in a package directory
+   * @param pkgDir Directory of the package; only its _test.go files are read
+   * @param files Test files to read instead of every _test.go, e.g. the ones
+   *   go list kept for the run's build tags
+   * @returns Test name to labels; unlabelled tests map to an empty list
+   */
+  async scan(pkgDir: string, files?: string[]): Promise<Record<string, string[]>> {
+    const entries = files || await fs.readdir(pkgDir);
+    const tests: Record<string, string[]> = {};
+
+    for (const entry of entries.filter(e => e.endsWith('_test.go')).sort()) {
+      const source = await fs.readFile(path.join(pkgDir, entry), 'utf-8');
+      Object.assign(tests, this.parse(source));
+    }
+
+    return tests;
+  }
+
+  /**
+   * Labels of the tests declared in one source file
+   * @param source Contents of a _test.go file
+   */
+  parse(source: string): Record<string, string[]> {
+    const tests: Record<string, string[]> = {};
+    let pending: string[] = [];
+
+    for (const raw of source.split('\n')) {
+      const line = raw.trim();
+
+      if (line.startsWith('//')) {
+        for (const match of line.matchAll(LABEL)) {
+          pending.push(...match[1].split(',').filter(Boolean));
+        }
+        continue;
+      }
+
+      const func = line.match(TEST_FUNC);
+      if (func) {
+        tests[func[1]] = Array.from(new Set(pending)).sort();
+      }
+      pending = []; // Only the comment block directly above a func applies
+    }
+
+    return tests;
+  }
+
+  /**
+   * Tests a label selection keeps
+   * @param tests Output of scan
+   * @param selection Labels to include and exclude
+   * @returns Selected test names, sorted
+   */
+  select(tests: Record<string, string[]>, selection: LabelSelection): string[] {
+    const include = selection.labels || [];
+    const exclude = selection.exclude_labels || [];
+
+    return Object.keys(tests)
+      .filter(name => include.length === 0 || tests[name].some(l => include.includes(l)))
+      .filter(name => !tests[name].some(l => exclude.includes(l)))
+      .sort();
+  }
+}
+
+// Export singleton instance
+export const testLabelScanner = new TestLabelScanner();
@@ -0,0 +1,222 @@
+/**
+ * Test Result Cache
+ *
+ * Content-addressed cache of per-package Go test results.
+ * A package's key hashes its source files and testdata tree, the keys of the
+ * in-module packages it imports (transitively), go.mod/go.sum, the toolchain
+ * version, and the test flags, so any change that could alter the outcome
+ * produces a new key.
+ * Only passing results are stored; failures always re-run.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import crypto from 'crypto';
+import { GoCoverageProfile, TestCaseResult } from '../types/mcp';
+import { GoPackageInfo } from './goPackageSelector';
+import { logger } from './loggerService';
+
+// Bump when the entry format or key derivation changes
+const CACHE_FORMAT_VERSION = 'alcs-test-cache-v1';
+
+export interface CachedPackageResult {
+  package: string;
+  test_cases: TestCaseResult[];
+  coverage?: GoCoverageProfile; // Blocks for this package's files only
+  stored_at: string;
+}
+
+export interface CacheKeyInputs {
+  goVersion: string;
+  flags: string[];                 // Test flags that affect the outcome, e.g. -race, -tags=integration
+  env?: Record<string, string>;    // Build-relevant environment, e.g. GOFLAGS, CGO_ENABLED
+}
+
+export class TestResultCache {
+  private cacheDir: string;
+
+  constructor(cacheDir: string) {
+    this.cacheDir = cacheDir;
+  }
+
+  /**
+   * Look up a stored result
+   * @param key Package cache key
+   * @returns The stored result, or undefined on a miss or unreadable entry
+   */
+  async get(key: string): Promise<CachedPackageResult | undefined> {
+    try {
+      const content = await fs.readFile(this.entryPath(key), 'utf-8');
+      return JSON.parse(content) as CachedPackageResult;
+    } catch (error: any) {
+      if (error.code !== 'ENOENT') {
+        logger.warn(`Ignoring unreadable test cache entry ${key}: ${error.message}`);
+      }
+      return undefined;
+    }
+  }
+
+  /**
+   * Store a package result
+   * Results with any failing, timed out, or flaky test are not cached.
+   * @param key Package cache key
+   * @param result Result to store
+   * @returns True if the result was stored
+   */
+  async put(key: string, result: CachedPackageResult): Promise<boolean> {
+    if (!this.isCacheable(result)) {
+      return false;
+    }
+
+    const entryPath = this.entryPath(key);
+    const tmpPath = `${entryPath}.${process.pid}.tmp`;
+
+    await fs.mkdir(path.dirname(entryPath), { recursive: true });
+    await fs.writeFile(tmpPath, JSON.stringify(result), 'utf-8');
+    await fs.rename(tmpPath, entryPath); // Atomic so concurrent runs never read partial entries
+
+    return true;
+  }
+
+  /**
+   * Compute cache keys for every package in the module
+   * @param moduleRoot Module root (containing go.mod)
+   * @param packages Packages from go list -json
+   * @param inputs Toolchain and flag inputs shared by all keys
+   * @returns Map of import path to cache key
+   */
+  async computeKeys(
+    moduleRoot: string,
+    packages: GoPackageInfo[],
+    inputs: CacheKeyInputs
+  ): Promise<Map<string, string>> {
+    const byImportPath = new Map(packages.map(p => [p.ImportPath, p]));
+    const buildHashes = new Map<string, string>();
+
+    const salt = crypto.createHash('sha256');
+    salt.update(`${CACHE_FORMAT_VERSION}\n${inputs.goVersion}\n`);
+    salt.update(inputs.flags.join('\n') + '\n');
+    for (const name of Object.keys(inputs.env || {}).sort()) {
+      salt.update(`${name}=${inputs.env![name]}\n`);
+    }
+    // External dependency versions are pinned by go.mod/go.sum
+    salt.update(await this.hashFiles(moduleRoot, ['go.mod', 'go.sum']));
+    const saltDigest = salt.digest('hex');
+
+    // Hash of a package as seen by its importers: non-test sources plus its imports
+    // (Go forbids import cycles, so the recursion terminates)
+    const buildHash = async (importPath: string): Promise<string> => {
+      const cached = buildHashes.get(importPath);
+      if (cached) {
+        return cached;
+      }
+
+      const pkg = byImportPath.get(importPath)!;
+      const hash = crypto.createHash('sha256');
+      hash.update(`${importPath}\n`);
+      hash.update(await this.hashFiles(pkg.Dir, [
+        ...(pkg.GoFiles || []),
+        ...(pkg.CgoFiles || []),
+        ...(pkg.EmbedFiles || []),
+      ]));
+      for (const dep of this.moduleImports(pkg.Imports, byImportPath, importPath)) {
+        hash.update(`${dep} ${await buildHash(dep)}\n`);
+      }
+
+      const digest = hash.digest('hex');
+      buildHashes.set(importPath, digest);
+      return digest;
+    };
+
+    const keys = new Map<string, string>();
+    for (const pkg of packages) {
+      const hash = crypto.createHash('sha256');
+      hash.update(`${saltDigest}\n${await buildHash(pkg.ImportPath)}\n`);
+      hash.update(await this.hashFiles(pkg.Dir, [
+        ...(pkg.TestGoFiles || []),
+        ...(pkg.XTestGoFiles || []),
+        ...(pkg.TestEmbedFiles || []),
+        ...(pkg.XTestEmbedFiles || []),
+        // Golden files and fixtures the tests read at run time
+        ...await this.listFiles(path.join(pkg.Dir, 'testdata'), 'testdata'),
+      ]));
+      const testImports = [...(pkg.TestImports || []), ...(pkg.XTestImports || [])];
+      for (const dep of this.moduleImports(testImports, byImportPath, pkg.ImportPath)) {
+        hash.update(`${dep} ${await buildHash(dep)}\n`);
+      }
+      keys.set(pkg.ImportPath, hash.digest('hex'));
+    }
+
+    return keys;
+  }
+
+  /**
+   * Only fully passing results may be reused
+   */
+  private isCacheable(result: CachedPackageResult): boolean {
+    return result.test_cases.every(c => (c.status === 'passed' || c.status === 'skipped') && !c.flaky);
+  }
+
+  /**
+   * In-module imports, sorted and deduplicated
+   */
+  private moduleImports(
+    imports: string[] | undefined,
+    byImportPath: Map<string, GoPackageInfo>,
+    self: string
+  ): string[] {
+    return Array.from(new Set(imports || []))
+      .filter(imp => imp !== self && byImportPath.has(imp))
+      .sort();
+  }
+
+  /**
+   * Files under a directory, recursively, as paths prefixed with prefix
+   * A missing directory has no files.
+   */
+  private async listFiles(dir: string, prefix: string): Promise<string[]> {
+    let entries;
+    try {
+      entries = await fs.readdir(dir, { withFileTypes: true });
+    } catch {
+      return [];
+    }
+
+    const files: string[] = [];
+    for (const entry of entries) {
+      const rel = `${prefix}/${entry.name}`;
+      if (entry.isDirectory()) {
+        files.push(...await this.listFiles(path.join(dir, entry.name), rel));
+      } else {
+        files.push(rel);
+      }
+    }
+    return files;
+  }
+
+  /**
+   * Hash file names and contents; missing files hash as absent
+   */
+  private async hashFiles(dir: string, files: string[]): Promise<string> {
+    const hash = crypto.createHash('sha256');
+
+    for (const file of Array.from(new Set(files)).sort()) {
+      hash.update(`${file}\0`);
+      try {
+        hash.update(await fs.readFile(path.join(dir, file)));
+      } catch {
+        hash.update('<missing>');
+      }
+      hash.update('\0');
+    }
+
+    return hash.digest('hex');
+  }
+
+  /**
+   * Entries are sharded by key prefix to keep directories small
+   */
+  private entryPath(key: string): string {
+    return path.join(this.cacheDir, key.slice(0, 2), `${key}.json`);
+  }
+}
@@ -9,6 +9,7 @@ import { testRunnerService } from './testRunnerService';
 import { PytestRunner } from './testRunners/pytestRunner';
 import { JestRunner } from './testRunners/jestRunner';
 import { GoTestRunner } from './testRunners/goTestRunner';
+import { GinkgoRunner } from './testRunners/ginkgoRunner';
 import { JUnitRunner } from './testRunners/junitRunner';
 import { MockTestRunner } from './testRunners/mockTestRunner';
 import { logger } from './loggerService';
@@ -35,6 +36,11 @@ export async function registerAllTestRunners(): Promise<void> {
   testRunnerService.registerRunner(goTestRunner);
   logger.info('✓ Registered Go test runner');
 
+  // Register Ginkgo runner
+  const ginkgoRunner = new GinkgoRunner();
+  testRunnerService.registerRunner(ginkgoRunner);
+  logger.info('✓ Registered Ginkgo runner');
+
   // Register JUnit runner
   const junitRunner = new JUnitRunner();
   testRunnerService.registerRunner(junitRunner);
@@ -60,21 +66,24 @@ export async function checkTestToolAvailability(): Promise<{
   pytest: boolean;
   jest: boolean;
   go: boolean;
+  ginkgo: boolean;
   maven: boolean;
   docker: boolean;
 }> {
   const pytestRunner = new PytestRunner();
   const jestRunner = new JestRunner();
   const goTestRunner = new GoTestRunner();
+  const ginkgoRunner = new GinkgoRunner();
   const junitRunner = new JUnitRunner();
 
   // Import sandboxService dynamically to avoid circular dependency
   const { sandboxService } = await import('./sandboxService.js');
 
-  const [pytest, jest, go, maven, docker] = await Promise.all([
+  const [pytest, jest, go, ginkgo, maven, docker] = await Promise.all([
     pytestRunner.isAvailable(),
     jestRunner.isAvailable(),
     goTestRunner.isAvailable(),
+    ginkgoRunner.isAvailable(),
     junitRunner.isAvailable(),
     sandboxService.isDockerAvailable(),
   ]);
@@ -83,6 +92,7 @@ export async function checkTestToolAvailability(): Promise<{
     pytest,
     jest,
     go,
+    ginkgo,
     maven,
     docker,
   };
@@ -98,6 +108,7 @@ export async function logTestToolAvailability(): Promise<void> {
   logger.info(`  pytest: ${availability.pytest ? '✓ Available' : '✗ Not available'}`);
   logger.info(`  jest: ${availability.jest ? '✓ Available' : '✗ Not available'}`);
   logger.info(`  go: ${availability.go ? '✓ Available' : '✗ Not available'}`);
+  logger.info(`  ginkgo: ${availability.ginkgo ? '✓ Available' : '✗ Not available'}`);
   logger.info(`  maven: ${availability.maven ? '✓ Available' : '✗ Not available'}`);
   logger.info(`  docker: ${availability.docker ? '✓ Available' : '✗ Not available'}`);
 
@@ -28,18 +28,21 @@ export interface TestRunner {
    * @param codeFilePath Path to code file
    * @param testFilePath Path to test file
    * @param options Execution options
+   * @param signal Cancels the run; runners that support it return partial results
    * @returns Test execution result
    */
   execute(
     workspacePath: string,
     codeFilePath: string,
     testFilePath: string,
-    options: TestExecutionOptions
+    options: TestExecutionOptions,
+    signal?: AbortSignal
   ): Promise<TestExecutionResult>;
 }
 
 export class TestRunnerService {
   private runners: Map<TestFramework, TestRunner> = new Map();
+  private root = new AbortController(); // Cancels every run; see cancelAll
 
   constructor() {
     // Runners will be registered as they are implemented
@@ -55,6 +58,17 @@ export class TestRunnerService {
     logger.info(`Registered test runner for framework: ${runner.framework}`);
   }
 
+  /**
+   * Cancel all in-flight test runs, e.g. on SIGINT/SIGTERM
+   * Runners stop their containers and return partial results. Runs started
+   * afterwards are not affected.
+   */
+  cancelAll(): void {
+    logger.warn('Cancelling all in-flight test runs');
+    this.root.abort();
+    this.root = new AbortController();
+  }
+
   /**
    * Execute tests for code and test artifacts
    * @param codeArtifact Code artifact to test
@@ -76,8 +90,9 @@ export class TestRunnerService {
       `Executing tests for artifact ${codeArtifact.id} using framework ${framework}`
     );
 
-    // Default options
+    // Default options; everything else passes through to the runner
     const execOptions: TestExecutionOptions = {
+      ...options,
       timeout_seconds: options?.timeout_seconds || 300, // 5 minutes default
       memory_limit_mb: options?.memory_limit_mb || 512,
       cpu_limit: options?.cpu_limit || 1.0,
@@ -85,6 +100,8 @@ export class TestRunnerService {
     };
 
     let workspace: string | null = null;
+    const signal = this.root.signal;
+    metricsService.recordTestExecutionStart();
 
     try {
       // 1. Create temporary workspace
@@ -105,7 +122,8 @@ export class TestRunnerService {
         workspace,
         codeFilePath,
         testFilePath,
-        execOptions
+        execOptions,
+        signal
       );
 
       // 5. Add execution metadata
@@ -163,6 +181,8 @@ export class TestRunnerService {
       };
 
     } finally {
+      metricsService.recordTestExecutionEnd();
+
       // 6. Cleanup workspace
       if (workspace) {
         await tempFileManager.cleanup(workspace);
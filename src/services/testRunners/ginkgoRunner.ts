@@ -0,0 +1,268 @@
+/**
+ * Ginkgo Test Runner
+ *
+ * Executes Ginkgo suites with --json-report and parses the structured report.
+ * Preserves the Describe/Context/It hierarchy on each per-test result.
+ */
+
+import { execFile } from 'child_process';
+import { promisify } from 'util';
+import * as path from 'path';
+import * as fs from 'fs/promises';
+import { TestRunner } from '../testRunnerService';
+import {
+  TestFramework,
+  TestExecutionResult,
+  TestExecutionOptions,
+  TestFailure,
+  TestCaseResult,
+  TestCaseStatus,
+} from '../../types/mcp';
+import { logger } from '../loggerService';
+import { coverageParser } from '../coverageParser';
+
+const execFileAsync = promisify(execFile);
+
+// Ginkgo spec states and how they map onto per-test statuses
+const GINKGO_STATE_MAP: Record<string, TestCaseStatus> = {
+  'passed': 'passed',
+  'skipped': 'skipped',
+  'pending': 'skipped',
+  'failed': 'failed',
+  'panicked': 'error',
+  'interrupted': 'error',
+  'aborted': 'error',
+  'timedout': 'timed_out',
+};
+
+export class GinkgoRunner implements TestRunner {
+  framework: TestFramework = 'ginkgo';
+
+  async execute(
+    workspacePath: string,
+    codeFilePath: string,
+    testFilePath: string,
+    options: TestExecutionOptions
+  ): Promise<TestExecutionResult> {
+    const startTime = Date.now();
+
+    logger.info(`Executing Ginkgo suites from ${testFilePath}`);
+
+    try {
+      const { testCases, failures, coverageProfilePath, ...result } = await this.runSuites(
+        workspacePath, undefined, path.join(workspacePath, 'reports'), options
+      );
+
+      let coverageReport;
+      try {
+        await fs.access(coverageProfilePath);
+        coverageReport = await coverageParser.parseGoCoverageProfile(coverageProfilePath);
+      } catch {
+        logger.warn('Ginkgo coverage profile not found, parsing from stdout');
+        coverageReport = await coverageParser.parseGoCoverage(result.stdout);
+      }
+
+      const passed = testCases.filter(c => c.status === 'passed').length;
+
+      return {
+        success: result.exitCode === 0,
+        passed_tests: passed,
+        failed_tests: failures.length,
+        total_tests: passed + failures.length,
+        coverage_percentage: coverageReport.line_coverage,
+        duration_ms: Date.now() - startTime,
+        failures,
+        stdout: result.stdout,
+        stderr: result.stderr,
+        test_cases: testCases,
+      };
+
+    } catch (error: any) {
+      logger.error(`Ginkgo execution failed: ${error.message}`);
+
+      return {
+        success: false,
+        passed_tests: 0,
+        failed_tests: 0,
+        total_tests: 0,
+        coverage_percentage: 0,
+        duration_ms: Date.now() - startTime,
+        failures: [{
+          test_name: 'ginkgo_execution',
+          error_message: error.message,
+          stack_trace: error.stack || '',
+          location: 'unknown',
+        }],
+        stdout: error.stdout || '',
+        stderr: error.stderr || error.message,
+      };
+    }
+  }
+
+  /**
+   * Run Ginkgo suites and parse their report
+   * Used directly by the Go test runner for packages detected as Ginkgo.
+   * @param workspacePath Module root
+   * @param packageDirs Suite directories, or undefined to run every suite (-r)
+   * @param reportsDir Where the JSON report and coverage profile are written
+   * @param options Execution options
+   */
+  async runSuites(
+    workspacePath: string,
+    packageDirs: string[] | undefined,
+    reportsDir: string,
+    options: TestExecutionOptions
+  ): Promise<{
+    exitCode: number;
+    stdout: string;
+    stderr: string;
+    testCases: TestCaseResult[];
+    failures: TestFailure[];
+    coverageProfilePath: string;
+  }> {
+    const reportPath = path.join(reportsDir, 'ginkgo-report.json');
+    const coverageProfilePath = path.join(reportsDir, 'coverage.out');
+
+    const args = [
+      '--json-report=' + reportPath,
+      '--cover',
+      '--coverprofile=' + coverageProfilePath,
+      '--keep-going', // Run every suite even if one fails
+      ...(options.tags && options.tags.length > 0 ? [`--tags=${options.tags.join(',')}`] : []),
+      ...(packageDirs || ['-r']), // Explicit suites, or all suites under the workspace
+    ];
+
+    const result = await this.executeGinkgo(workspacePath, args, options);
+
+    // Parse the structured report
+    const content = await fs.readFile(reportPath, 'utf-8');
+    const testCases = this.parseReport(content);
+    const failures = testCases
+      .filter(c => c.status === 'failed' || c.status === 'error' || c.status === 'timed_out')
+      .map(c => this.toFailure(c));
+
+    return { ...result, testCases, failures, coverageProfilePath };
+  }
+
+  /**
+   * Parse a Ginkgo JSON report (--json-report)
+   * The report is an array of suite reports, each with SpecReports.
+   * @param content Report JSON
+   * @returns Per-spec results
+   * @throws Error if the report is not valid JSON
+   */
+  parseReport(content: string): TestCaseResult[] {
+    const suites = JSON.parse(content);
+    const testCases: TestCaseResult[] = [];
+
+    for (const suite of Array.isArray(suites) ? suites : [suites]) {
+      const suiteName = suite.SuitePath || suite.SuiteDescription || '';
+
+      for (const spec of suite.SpecReports || []) {
+        const status = GINKGO_STATE_MAP[spec.State] || 'error';
+
+        // Setup/teardown nodes (BeforeSuite, AfterSuite, ...) only matter when they fail
+        if (spec.LeafNodeType !== 'It' && (status === 'passed' || status === 'skipped')) {
+          continue;
+        }
+
+        const hierarchy: string[] = spec.ContainerHierarchyTexts || [];
+        const leaf = spec.LeafNodeText || spec.LeafNodeType || 'spec';
+        const attempts = spec.NumAttempts || 1;
+
+        testCases.push({
+          package: suiteName,
+          name: [...hierarchy, leaf].join(' '),
+          status,
+          duration_ms: Math.round((spec.RunTime || 0) / 1e6), // RunTime is in nanoseconds
+          output: (spec.CapturedGinkgoWriterOutput || '') + (spec.CapturedStdOutErr || ''),
+          failure_message: this.extractFailureMessage(spec),
+          attempts,
+          flaky: status === 'passed' && attempts > 1 ? true : undefined,
+          hierarchy,
+        });
+      }
+    }
+
+    return testCases;
+  }
+
+  /**
+   * Extract the failure or skip message for a spec
+   */
+  private extractFailureMessage(spec: any): string | undefined {
+    if (spec.State === 'pending') {
+      return 'pending';
+    }
+
+    const failure = spec.Failure;
+    if (!failure) {
+      return undefined;
+    }
+
+    return failure.ForwardedPanic
+      ? `panic: ${failure.ForwardedPanic}`
+      : failure.Message;
+  }
+
+  /**
+   * Convert a failed spec to a test failure for the review system
+   */
+  private toFailure(testCase: TestCaseResult): TestFailure {
+    return {
+      test_name: testCase.name,
+      error_message: testCase.failure_message || 'Spec failed',
+      stack_trace: testCase.output,
+      location: 'unknown',
+    };
+  }
+
+  /**
+   * Execute ginkgo command with timeout
+   */
+  private async executeGinkgo(
+    workspacePath: string,
+    args: string[],
+    options: TestExecutionOptions
+  ): Promise<{ exitCode: number; stdout: string; stderr: string }> {
+    const timeoutMs = (options.timeout_seconds || 300) * 1000;
+
+    try {
+      const { stdout, stderr } = await execFileAsync('ginkgo', args, {
+        cwd: workspacePath,
+        timeout: timeoutMs,
+        maxBuffer: 10 * 1024 * 1024, // 10MB buffer
+        env: {
+          ...process.env,
+          GOPATH: process.env.GOPATH || path.join(process.env.HOME || '~', 'go'),
+        },
+      });
+
+      return { exitCode: 0, stdout, stderr };
+
+    } catch (error: any) {
+      // Ginkgo exits non-zero when specs fail; the JSON report is still written
+      if (typeof error.code === 'number' && !error.killed) {
+        return {
+          exitCode: error.code,
+          stdout: error.stdout || '',
+          stderr: error.stderr || '',
+        };
+      }
+
+      throw error;
+    }
+  }
+
+  /**
+   * Check if ginkgo is available
+   */
+  async isAvailable(): Promise<boolean> {
+    try {
+      await execFileAsync('ginkgo', ['version']);
+      return true;
+    } catch {
+      return false;
+    }
+  }
+}
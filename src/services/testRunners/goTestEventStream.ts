@@ -0,0 +1,159 @@
+/**
+ * Go Test Event Stream
+ *
+ * Turns raw `go test -json` stdout/stderr chunks into normalized test events
+ * and dispatches them to subscribers as they arrive.
+ * Compiler errors are written to stderr (not as JSON), so stderr lines are
+ * forwarded as output events attributed to the package named in the
+ * preceding `# package` header.
+ */
+
+import { logger } from '../loggerService';
+
+export type GoTestEventAction =
+  | 'start'
+  | 'run'
+  | 'pause'
+  | 'cont'
+  | 'pass'
+  | 'fail'
+  | 'skip'
+  | 'bench'
+  | 'output';
+
+export interface GoTestEvent {
+  package: string;
+  test?: string;
+  action: GoTestEventAction;
+  elapsed_ms?: number;
+  output?: string;
+  stream: 'stdout' | 'stderr';
+  build?: boolean; // Compiler output rather than test output
+}
+
+/**
+ * Receives test events as go test produces them
+ */
+export interface EventHandler {
+  handleEvent(event: GoTestEvent): void;
+}
+
+export class GoTestEventStream {
+  private handlers: EventHandler[];
+  private pendingStdout = '';
+  private pendingStderr = '';
+  private buildPackage = '';
+
+  constructor(handlers: EventHandler[] = []) {
+    this.handlers = handlers;
+  }
+
+  /**
+   * Feed a chunk of go test stdout
+   */
+  writeStdout(chunk: string): void {
+    this.pendingStdout = this.consumeLines(this.pendingStdout + chunk, line => this.handleStdoutLine(line));
+  }
+
+  /**
+   * Feed a chunk of go test stderr
+   */
+  writeStderr(chunk: string): void {
+    this.pendingStderr = this.consumeLines(this.pendingStderr + chunk, line => this.handleStderrLine(line));
+  }
+
+  /**
+   * Flush any trailing partial lines once the process has exited
+   */
+  end(): void {
+    if (this.pendingStdout.trim()) {
+      this.handleStdoutLine(this.pendingStdout);
+    }
+    if (this.pendingStderr.trim()) {
+      this.handleStderrLine(this.pendingStderr);
+    }
+    this.pendingStdout = '';
+    this.pendingStderr = '';
+  }
+
+  /**
+   * Split complete lines off a buffer and return the remainder
+   */
+  private consumeLines(buffer: string, onLine: (line: string) => void): string {
+    const lines = buffer.split('\n');
+    const remainder = lines.pop() || '';
+    lines.filter(l => l.trim()).forEach(onLine);
+    return remainder;
+  }
+
+  private handleStdoutLine(line: string): void {
+    let raw: any;
+    try {
+      raw = JSON.parse(line);
+    } catch {
+      // Non-JSON lines (e.g. "go: downloading ..." or a runtime crash) are still output
+      this.emit({ package: '', action: 'output', output: line + '\n', stream: 'stdout' });
+      return;
+    }
+
+    if (!raw || !raw.Action) {
+      return;
+    }
+
+    // Go 1.24+ reports compiler output as build-output events keyed by ImportPath;
+    // build-fail is redundant with the package-level fail that follows
+    if (raw.Action === 'build-fail') {
+      return;
+    }
+    if (raw.Action === 'build-output') {
+      this.emit({
+        package: this.packageFromImportPath(raw.ImportPath || ''),
+        action: 'output',
+        output: raw.Output,
+        stream: 'stdout',
+        build: true,
+      });
+      return;
+    }
+
+    this.emit({
+      package: raw.Package || '',
+      test: raw.Test || undefined,
+      action: raw.Action,
+      elapsed_ms: typeof raw.Elapsed === 'number' ? Math.round(raw.Elapsed * 1000) : undefined,
+      output: raw.Output,
+      stream: 'stdout',
+    });
+  }
+
+  private handleStderrLine(line: string): void {
+    // "# example.com/pkg" or "# example.com/pkg_test [example.com/pkg.test]" starts a build error block
+    if (/^# \S/.test(line)) {
+      this.buildPackage = this.packageFromImportPath(line.slice(2));
+    }
+
+    this.emit({ package: this.buildPackage, action: 'output', output: line + '\n', stream: 'stderr', build: true });
+  }
+
+  /**
+   * Map a build target to the package its test events are reported under
+   * "example.com/pkg_test [example.com/pkg.test]" -> "example.com/pkg"
+   */
+  private packageFromImportPath(importPath: string): string {
+    const variant = importPath.match(/\[(\S+)\.test\]/);
+    return variant ? variant[1] : importPath.trim().split(/\s+/)[0];
+  }
+
+  /**
+   * Dispatch an event; a failing subscriber must not abort the run
+   */
+  private emit(event: GoTestEvent): void {
+    for (const handler of this.handlers) {
+      try {
+        handler.handleEvent(event);
+      } catch (error: any) {
+        logger.warn(`Test event handler failed: ${error.message}`);
+      }
+    }
+  }
+}
@@ -0,0 +1,150 @@
+/**
+ * Go Test Executors
+ *
+ * Decide how one go test invocation is launched. GoToolchainExecutor compiles
+ * and runs packages with `go test -json`; PrebuiltBinaryExecutor runs `.test`
+ * binaries built elsewhere (with `go test -c -cover`) and converts their
+ * output to the same -json events, so result parsing is shared and prebuilt
+ * runs need neither the sources nor a Go toolchain.
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { goPackageSelector } from '../goPackageSelector';
+import { OutputDecoder, Test2JsonConverter } from './test2json';
+
+// go test flags that only affect compilation; a prebuilt binary already has them baked in
+const BUILD_FLAGS = new Set(['cover', 'covermode', 'coverpkg', 'race', 'json', 'tags', 'v']);
+
+/**
+ * One go test invocation, in go test terms
+ */
+export interface GoTestRun {
+  packages: string[];         // Package patterns or import paths
+  flags: string[];            // go test flags, e.g. -v -json -cover
+  run?: string;               // -run pattern
+  coverProfile?: string;      // -coverprofile destination
+}
+
+/**
+ * Process to spawn for a GoTestRun
+ */
+export interface GoTestCommand {
+  argv: string[];             // Program followed by its arguments
+  cwd?: string;               // Defaults to the workspace
+  decoder?: OutputDecoder;    // Converts stdout to -json events; omitted when it already is
+}
+
+export interface GoTestExecutor {
+  /**
+   * Expand package patterns such as ./... or ./api into the packages this executor can run
+   * @param tags Build tags the packages are tested with
+   * @returns Import paths, or package labels for prebuilt binaries
+   */
+  resolvePackages(workspacePath: string, patterns: string[], tags?: string[]): Promise<string[]>;
+
+  /**
+   * Build the command for one invocation
+   */
+  command(run: GoTestRun): GoTestCommand;
+}
+
+export class GoToolchainExecutor implements GoTestExecutor {
+  async resolvePackages(workspacePath: string, patterns: string[], tags: string[] = []): Promise<string[]> {
+    if (patterns.length === 0) {
+      return [];
+    }
+
+    const listed = await goPackageSelector.listPackages(workspacePath, tags, patterns);
+    return listed.map(p => p.ImportPath);
+  }
+
+  command(run: GoTestRun): GoTestCommand {
+    return {
+      argv: [
+        'go',
+        'test',
+        ...run.flags,
+        ...(run.coverProfile ? ['-coverprofile=' + run.coverProfile] : []),
+        ...(run.run ? ['-run', run.run] : []),
+        ...run.packages,
+      ],
+    };
+  }
+}
+
+/**
+ * Runs the `<package>.test` binaries found under a directory
+ * A binary's path relative to the directory, minus `.test`, is its package
+ * label, so a builder that writes example.com/mod/api.test keeps import paths.
+ */
+export class PrebuiltBinaryExecutor implements GoTestExecutor {
+  constructor(private binariesDir: string) {}
+
+  async resolvePackages(_workspacePath: string, patterns: string[]): Promise<string[]> {
+    const labels = await this.listBinaries(this.binariesDir);
+    return labels.filter(label => patterns.some(pattern => this.matches(label, pattern))).sort();
+  }
+
+  /**
+   * @throws If the run covers more than one package; each binary is one package
+   */
+  command(run: GoTestRun): GoTestCommand {
+    if (run.packages.length !== 1) {
+      throw new Error(`A prebuilt test binary runs exactly one package, got ${run.packages.length}`);
+    }
+
+    const pkg = run.packages[0];
+    const binary = path.join(this.binariesDir, `${pkg}.test`);
+    const argv = [binary, '-test.v=true'];
+
+    for (const flag of run.flags) {
+      const name = flag.replace(/^-+/, '').split('=')[0];
+      if (!BUILD_FLAGS.has(name)) {
+        argv.push(`-test.${flag.replace(/^-+/, '')}`);
+      }
+    }
+    if (run.coverProfile) {
+      argv.push(`-test.coverprofile=${run.coverProfile}`);
+    }
+    if (run.run) {
+      argv.push(`-test.run=${run.run}`);
+    }
+
+    // Run from the binary's directory, where go test would put the package's testdata
+    return { argv, cwd: path.dirname(binary), decoder: new Test2JsonConverter(pkg) };
+  }
+
+  private async listBinaries(dir: string, prefix: string = ''): Promise<string[]> {
+    const labels: string[] = [];
+
+    for (const entry of await fs.readdir(dir, { withFileTypes: true })) {
+      const rel = prefix ? `${prefix}/${entry.name}` : entry.name;
+      if (entry.isDirectory()) {
+        labels.push(...await this.listBinaries(path.join(dir, entry.name), rel));
+      } else if (entry.name.endsWith('.test')) {
+        labels.push(rel.slice(0, -'.test'.length));
+      }
+    }
+
+    return labels;
+  }
+
+  /**
+   * Patterns work like go test's: ./... is everything, x/... is x and below
+   */
+  private matches(label: string, pattern: string): boolean {
+    const normalized = pattern.replace(/^\.\//, '');
+    if (normalized === '...') {
+      return true;
+    }
+    if (normalized.endsWith('/...')) {
+      const base = normalized.slice(0, -'/...'.length);
+      return label === base || label.startsWith(`${base}/`);
+    }
+    return label === normalized;
+  }
+}
+
+// Export singleton instance
+export const goToolchainExecutor = new GoToolchainExecutor();
@@ -5,137 +5,2804 @@
  * Supports Go's built-in testing package.
  */
 
-import { execFile } from 'child_process';
+import { ChildProcess, execFile, spawn } from 'child_process';
 import { promisify } from 'util';
+import crypto from 'crypto';
 import * as path from 'path';
+import * as os from 'os';
 import * as fs from 'fs/promises';
 import { TestRunner } from '../testRunnerService';
-import { TestFramework, TestExecutionResult, TestExecutionOptions, TestFailure } from '../../types/mcp';
+import {
+  TestFramework,
+  TestExecutionResult,
+  TestExecutionOptions,
+  TestFailure,
+  TestCaseResult,
+  TestCaseStatus,
+  CoverageViolation,
+  GoCoverageProfile,
+  DataRace,
+  GoroutineLeak,
+  LintFinding,
+  RunSummary,
+  BenchResult,
+  BenchmarkComparison,
+  GoFramework,
+  DiffSummary,
+  BuildFailure,
+  ShuffleInfo,
+  ChangedCoverageReport,
+  TestPanic,
+  RunPlan,
+  PlannedProcess,
+  ListedTest,
+  TestInventory,
+} from '../../types/mcp';
 import { logger } from '../loggerService';
+import { RunningServices, serviceContainers } from '../serviceContainers';
+import { createRunLog, currentRunLog } from '../runLog';
 import { coverageParser } from '../coverageParser';
+import { junitReporter } from '../reporters/junitReporter';
+import { tapReporter } from '../reporters/tapReporter';
+import { createRunSummary, jsonSummaryReporter, SummaryHandler } from '../reporters/jsonSummaryReporter';
+import { diffRunSummaries, readRunSummary } from '../reporters/runSummaryDiff';
+import { buildMatrixReport, matrixReporter, MatrixRunResult, unifyCoverage } from '../reporters/matrixReporter';
+import { buildDurationReport, durationReporter } from '../reporters/durationReporter';
+import { testListReporter } from '../reporters/testListReporter';
+import { runPlanReporter } from '../reporters/runPlanReporter';
+import { TEST_INVENTORY_SCHEMA_VERSION, testInventoryReporter } from '../reporters/testInventoryReporter';
+import { htmlCoverageReporter, moduleSourceFS } from '../reporters/htmlCoverageReporter';
+import { buildTagFlags, GoPackageInfo, goPackageSelector } from '../goPackageSelector';
+import { coverageProfileService } from '../coverageProfileService';
+import { changedLineCoverageService } from '../changedLineCoverage';
+import { lintService } from '../lintService';
+import { webhookService } from '../webhookService';
+import { FileResultStore, ResultStore } from '../resultStore';
+import { checkpointSelection, isUnfinishedCheckpoint, resumableCheckpoint, RunCheckpointer } from '../runCheckpoint';
+import { goroutineLeakCheck } from '../goroutineLeakCheck';
+import { parseS3Location, S3ResultStore } from '../s3ResultStore';
+import { benchmarkService } from '../benchmarkService';
+import { goFrameworkDetector } from '../goFrameworkDetector';
+import { testSharder, TestTimings } from '../testSharder';
+import { GinkgoRunner } from './ginkgoRunner';
+import { GoTestCommand, GoTestExecutor, goToolchainExecutor, PrebuiltBinaryExecutor } from './goTestExecutor';
+import { CoverageGate } from '../coverageGate';
+import { TestResultCache, CachedPackageResult } from '../testResultCache';
+import { raceReportParser } from '../raceReportParser';
+import { buildErrorParser } from '../buildErrorParser';
+import { TestEnvironment, testEnvironmentLoader } from '../testEnvironment';
+import { testLabelScanner } from '../testLabelScanner';
+import { sandboxService, SandboxConfig, ArtifactCopyResult } from '../sandboxService';
+import { runWorkerPool } from '../../utils/workerPool';
+import { CancelledError } from '../../utils/cancellation';
+import { colorEnabled } from '../../utils/terminalColor';
+import { EventHandler, GoTestEvent, GoTestEventStream } from './goTestEventStream';
 
 const execFileAsync = promisify(execFile);
 
+// One go test invocation in parallel mode: a package, or a -run shard of one
+interface PackageRun {
+  pkg: string;
+  tests?: string[];
+}
+
+interface GoTestProcessResult {
+  exitCode: number;
+  stdout: string;
+  stderr: string;
+  buildOutput?: Map<string, string>;
+  timedOutTests?: { pkg: string; test: string }[];
+  goroutineDump?: string;
+  oomKilled?: boolean;
+  artifacts?: ArtifactCopyResult[];
+  cancelled?: boolean;          // Stopped by the abort signal; output is partial
+  rawOutput?: string;           // stdout and stderr interleaved as received
+  packageOutput?: Map<string, string>; // Raw output of each package's processes, for keep_logs
+}
+
+/**
+ * Collects compiler output per package so build failures keep their
+ * diagnostics even though -json emits no test events for them
+ */
+class BuildOutputCollector implements EventHandler {
+  readonly byPackage = new Map<string, string>();
+
+  handleEvent(event: GoTestEvent): void {
+    if (!event.build || event.action !== 'output' || !event.output) {
+      return;
+    }
+    this.byPackage.set(event.package, (this.byPackage.get(event.package) || '') + event.output);
+  }
+}
+
+/**
+ * Aborts the run on the first failed test or package (fail_fast)
+ * Chained to the caller's signal so an outside cancel still stops the run;
+ * dispose() detaches from it once the run's processes have finished.
+ */
+class FailFastTrigger implements EventHandler {
+  firstFailure?: string;
+
+  private controller = new AbortController();
+  private onParentAbort = () => this.controller.abort();
+
+  constructor(private parent?: AbortSignal) {
+    if (parent?.aborted) {
+      this.controller.abort();
+    } else {
+      parent?.addEventListener('abort', this.onParentAbort, { once: true });
+    }
+  }
+
+  get signal(): AbortSignal {
+    return this.controller.signal;
+  }
+
+  handleEvent(event: GoTestEvent): void {
+    if (event.action !== 'fail' || this.controller.signal.aborted) {
+      return;
+    }
+    this.firstFailure = event.test ? `${event.package} ${event.test}` : event.package;
+    this.controller.abort();
+  }
+
+  dispose(): void {
+    this.parent?.removeEventListener('abort', this.onParentAbort);
+  }
+}
+
+/**
+ * Watches per-test durations and fires once when a leaf test runs too long
+ * Paused (t.Parallel) tests do not accrue time. After firing, all further
+ * output is captured as the goroutine dump.
+ */
+class TestTimeoutWatchdog implements EventHandler {
+  readonly timedOutTests: { pkg: string; test: string }[] = [];
+  triggered = false;
+
+  private running = new Map<string, { pkg: string; test: string; activeSince: number | null; accruedMs: number }>();
+  private dumpLines: string[] = [];
+  private timer: NodeJS.Timeout;
+
+  constructor(private timeoutSeconds: number, private onTimeout: () => void) {
+    const timeoutMs = timeoutSeconds * 1000;
+    this.timer = setInterval(() => this.check(timeoutMs), Math.min(1000, timeoutMs));
+  }
+
+  get goroutineDump(): string {
+    return this.dumpLines.join('');
+  }
+
+  handleEvent(event: GoTestEvent): void {
+    if (this.triggered && event.action === 'output' && event.output) {
+      this.dumpLines.push(event.output);
+    }
+    if (!event.test) {
+      return;
+    }
+
+    const key = `${event.package} ${event.test}`;
+    const now = Date.now();
+    const entry = this.running.get(key);
+
+    if (event.action === 'run') {
+      this.running.set(key, { pkg: event.package, test: event.test, activeSince: now, accruedMs: 0 });
+    } else if (event.action === 'pause' && entry && entry.activeSince !== null) {
+      entry.accruedMs += now - entry.activeSince;
+      entry.activeSince = null;
+    } else if (event.action === 'cont' && entry) {
+      entry.activeSince = now;
+    } else if (event.action === 'pass' || event.action === 'fail' || event.action === 'skip') {
+      this.running.delete(key);
+    }
+  }
+
+  stop(): void {
+    clearInterval(this.timer);
+  }
+
+  private check(timeoutMs: number): void {
+    if (this.triggered) {
+      return;
+    }
+
+    const now = Date.now();
+    const active = Array.from(this.running.values()).filter(t => t.activeSince !== null);
+
+    // Only leaf tests count: a parent waiting on its subtests is not itself hung
+    const hung = active.filter(t =>
+      t.accruedMs + (now - t.activeSince!) > timeoutMs &&
+      !active.some(o => o.pkg === t.pkg && o.test.startsWith(`${t.test}/`))
+    );
+
+    if (hung.length > 0) {
+      this.timedOutTests.push(...hung.map(t => ({ pkg: t.pkg, test: t.test })));
+      this.triggered = true;
+      logger.warn(
+        `Tests exceeded ${this.timeoutSeconds}s timeout: ` +
+        `${hung.map(t => `${t.pkg} ${t.test}`).join(', ')}. Sending SIGQUIT for goroutine dump`
+      );
+      this.onTimeout();
+    }
+  }
+}
+
 export class GoTestRunner implements TestRunner {
   framework: TestFramework = 'go_testing';
+  private eventHandlers: EventHandler[];
+  private summaryHandlers: SummaryHandler[];
+  private ginkgoRunner = new GinkgoRunner(); // For packages routed to Ginkgo by detect_framework
+  private testEnvironments = new WeakMap<TestExecutionOptions, Promise<TestEnvironment>>();
+  private sourceVolumes = new WeakMap<TestExecutionOptions, string>();
+  private runningServices = new WeakMap<TestExecutionOptions, RunningServices>();
+  private checkpointers = new WeakMap<TestExecutionOptions, RunCheckpointer>();
+  private checkpointIds = new WeakMap<TestExecutionOptions, string>(); // Set by the matrix for each version's run
+
+  /**
+   * @param eventHandlers Subscribers for live go test events (e.g. progress UIs)
+   * @param summaryHandlers Receive the RunSummary of every finished run
+   */
+  constructor(eventHandlers: EventHandler[] = [], summaryHandlers: SummaryHandler[] = []) {
+    this.eventHandlers = eventHandlers;
+    this.summaryHandlers = summaryHandlers;
+  }
+
+  /**
+   * Subscribe to live go test events
+   */
+  addEventHandler(handler: EventHandler): void {
+    this.eventHandlers.push(handler);
+  }
+
+  async execute(
+    workspacePath: string,
+    codeFilePath: string,
+    testFilePath: string,
+    options: TestExecutionOptions,
+    signal?: AbortSignal
+  ): Promise<TestExecutionResult> {
+    // Everything the run starts, including sandbox calls, logs with its run_id
+    const log = createRunLog(crypto.randomUUID(), { level: options.log_level, format: options.log_format });
+    return log.run(() => this.executeRun(workspacePath, codeFilePath, testFilePath, options, signal));
+  }
+
+  private async executeRun(
+    workspacePath: string,
+    codeFilePath: string,
+    testFilePath: string,
+    options: TestExecutionOptions,
+    signal?: AbortSignal
+  ): Promise<TestExecutionResult> {
+    const startTime = Date.now();
+    const log = currentRunLog();
+
+    logger.info(`Executing Go tests from ${testFilePath}`);
+
+    try {
+      if (options.dry_run) {
+        return await this.dryRun(workspacePath, options, startTime);
+      }
+
+      if (options.list) {
+        return await this.listRun(workspacePath, options, startTime);
+      }
+
+      if (options.go_versions && options.go_versions.length > 0) {
+        return await this.executeMatrix(workspacePath, codeFilePath, testFilePath, options, signal);
+      }
+
+      // Prepare paths
+      const reportsDir = path.join(workspacePath, 'reports');
+      const coverageProfilePath = path.join(reportsDir, 'coverage.out');
+
+      // A resumed run replays the selection of the run it continues
+      const resumed = options.resume_run_id ? await this.readCheckpoint(options) : undefined;
+
+      // Restrict to affected packages when running incrementally
+      const packages = resumed ? resumed.checkpoint!.selection.packages : await this.selectPackages(workspacePath, options);
+      if (packages.length === 0) {
+        logger.info(`No packages affected since ${options.since_ref}, skipping go test`);
+        return {
+          success: true,
+          passed_tests: 0,
+          failed_tests: 0,
+          total_tests: 0,
+          coverage_percentage: 0,
+          duration_ms: Date.now() - startTime,
+          failures: [],
+          stdout: '',
+          stderr: '',
+          test_cases: [],
+        };
+      }
+      log.info('Run started', { packages: packages.length, sandbox: Boolean(options.sandbox) });
+
+      // Fail before scheduling anything on a bad env entry, an unreadable secret,
+      // or an image that is missing and cannot be pulled
+      const testEnv = await this.testEnvironment(options);
+      if (options.sandbox) {
+        const sandboxConfig = this.getSandboxConfig(workspacePath, options);
+        await sandboxService.ensureImage(sandboxConfig.image, undefined, sandboxConfig.registry_mirror);
+      }
+
+      // A copy mount snapshots the source once; every container of the run shares it
+      if (options.sandbox && options.mount === 'copy') {
+        const volume = await sandboxService.snapshotWorkspace(
+          this.getSandboxConfig(workspacePath, options), workspacePath, workspacePath, options.mount_exclude
+        );
+        this.sourceVolumes.set(options, volume);
+      }
+
+      // Dependencies come up, healthy, before the first test; the finally below removes them
+      if (options.services && options.services.length > 0) {
+        this.runningServices.set(options, await serviceContainers.start(options.services, {
+          sandbox: Boolean(options.sandbox),
+          enableNetwork: options.enable_network,
+        }));
+      }
+
+      if (options.bench) {
+        return await this.executeBenchmarks(workspacePath, packages, options, startTime, signal);
+      }
+
+      // Packages detected (or configured) as Ginkgo suites run through the Ginkgo CLI instead
+      const routing = options.detect_framework
+        ? await this.routeByFramework(workspacePath, packages, options)
+        : undefined;
+      const goPackages = routing ? routing.standard : packages;
+
+      // Prebuilt binaries have coverage and -race compiled in and no sources to hash
+      const executor = this.executorFor(options);
+      const prebuilt = options.test_binaries_dir !== undefined;
+
+      // The leak check's TestMain is overlaid at build time, which needs the sources
+      const leakCheck = options.leak_check !== undefined && options.leak_check.length > 0;
+      if (leakCheck && prebuilt) {
+        throw new Error('leak_check needs package sources and cannot be used with test_binaries_dir');
+      }
+      const leakOverlay = leakCheck
+        ? await goroutineLeakCheck.prepare(
+          await goPackageSelector.listPackages(workspacePath, options.tags), options.leak_check!, path.join(reportsDir, 'leakcheck')
+        )
+        : undefined;
+
+      const testFlags = [...this.testFlags(options), ...(leakOverlay ? [`-overlay=${leakOverlay}`] : [])];
+      if (options.race && !prebuilt) {
+        await this.assertRaceDetectorAvailable(workspacePath, options);
+      }
+
+      // "on" picks the base seed here rather than in go test so it can be recorded and replayed
+      const shuffleSeed = resumed ? resumed.checkpoint!.selection.shuffle_seed : this.resolveShuffleSeed(options.shuffle);
+
+      // Labels resolve to -run names from the sources, which prebuilt binaries do not have
+      const labelled = this.hasLabelSelection(options);
+      if (labelled && prebuilt) {
+        throw new Error('labels and exclude_labels need package sources and cannot be used with test_binaries_dir');
+      }
+
+      // Progress is stored after every go test process, so a killed run can be resumed
+      const checkpointer = options.checkpoint || resumed
+        ? this.startCheckpoint(packages, options, shuffleSeed, startTime, resumed)
+        : undefined;
+      if (checkpointer) {
+        this.checkpointers.set(options, checkpointer);
+      }
+
+      // Reuse passing results for packages whose content hash is unchanged
+      const cache = this.resultCache(options);
+      const lookup = cache
+        ? await this.lookupCachedPackages(cache, workspacePath, goPackages, testFlags, testEnv, options.tags)
+        : undefined;
+      const packagesToRun = lookup ? lookup.misses : goPackages;
+
+      // With fail_fast the first failure stops scheduling and kills in-flight packages
+      const failFast = options.fail_fast ? new FailFastTrigger(signal) : undefined;
+      const runSignal = failFast ? failFast.signal : signal;
+      const runHandlers = failFast ? [failFast] : [];
+
+      // Execute go test, with a per-test watchdog when requested
+      let result: GoTestProcessResult = { exitCode: 0, stdout: '', stderr: '' };
+      // Each prebuilt binary is its own process, label selections differ per
+      // package, and checkpoints are taken per process, so all of them always
+      // go through the pool
+      const sharded = options.shards !== undefined && options.shards > 1;
+      const pooled = options.parallel !== undefined || sharded || prebuilt || labelled || checkpointer !== undefined;
+      const runPackages = async (pkgs: string[], runFlags: string[], profilePath: string): Promise<GoTestProcessResult> => {
+        if (pooled) {
+          return this.executePackagesInParallel(
+            workspacePath, pkgs, runFlags, profilePath, options, runSignal, runHandlers, shuffleSeed
+          );
+        }
+        // Go requires tests to be in the same package, so we run from workspace.
+        // One process takes one -shuffle seed, so every package shares the base seed.
+        const flags = shuffleSeed !== undefined ? [...runFlags, `-shuffle=${shuffleSeed}`] : runFlags;
+        const command = executor.command({ packages: pkgs, flags, coverProfile: profilePath, run: options.run });
+        log.debug('Packages scheduled', { packages: pkgs.length });
+        const started = Date.now();
+        const processResult = await this.executeGoTest(workspacePath, command, options, runSignal, runHandlers);
+        log.debug('Packages completed', {
+          exit_code: processResult.exitCode,
+          duration_ms: Date.now() - started,
+          cancelled: processResult.cancelled || undefined,
+        });
+        return processResult;
+      };
+
+      // A resumed run whose processes all finished has nothing left to run
+      const ran = packagesToRun.length > 0 && !checkpointer?.finished;
+      let cacheBypassed: string[] | undefined;
+      try {
+        if (ran) {
+          result = await runPackages(packagesToRun, testFlags, coverageProfilePath);
+        }
+
+        // A result go replays from its build cache can come without coverage, so
+        // those packages run again for real; -count disables the cache already
+        const replayed = options.count === undefined && !prebuilt && !result.cancelled
+          ? this.goCachedPackages(result.stdout)
+          : [];
+        if (replayed.length > 0) {
+          logger.info(`go test returned cached results for ${replayed.length} packages; rerunning them with -count=1`);
+          currentRunLog().debug('Go test cache bypassed', { packages: replayed });
+          const freshProfilePath = coverageProfilePath.replace(/\.out$/, '-fresh.out');
+          const fresh = await runPackages(replayed, [...testFlags, '-count=1'], freshProfilePath);
+          result = this.replacePackageResults(result, fresh, replayed);
+          await this.replacePackageCoverage(coverageProfilePath, freshProfilePath, replayed);
+          cacheBypassed = replayed;
+        }
+      } finally {
+        failFast?.dispose();
+        await checkpointer?.flush();
+      }
+
+      // A stopped run still reports what finished, but skips follow-up work.
+      // Only a stop that actually cut work short counts as aborted early.
+      const cancelled = Boolean(result.cancelled || signal?.aborted);
+      const abortedEarly = cancelled && !signal?.aborted && failFast?.firstFailure !== undefined;
+      if (abortedEarly) {
+        logger.warn(`Fail-fast: stopped after ${failFast!.firstFailure} failed; reporting partial results`);
+      } else if (cancelled) {
+        logger.warn('Go test run cancelled; reporting partial results');
+      }
+
+      if (lookup && lookup.hits.length > 0) {
+        await this.mergeCachedCoverage(coverageProfilePath, lookup.hits, packagesToRun.length > 0);
+      }
+      if (checkpointer) {
+        await checkpointer.restoreCoverage(coverageProfilePath, ran);
+      }
+
+      const ginkgo = routing && routing.ginkgo.length > 0 && !cancelled
+        ? await this.ginkgoRunner.runSuites(workspacePath, routing.ginkgo, path.join(reportsDir, 'ginkgo'), options)
+        : undefined;
+      if (ginkgo) {
+        result.exitCode = Math.max(result.exitCode, ginkgo.exitCode);
+        await this.mergePackageProfiles([coverageProfilePath, ginkgo.coverageProfilePath], coverageProfilePath);
+      }
+
+      // Filter the profile on disk so the percentage, gate, and every report agree
+      if (options.coverage_exclude && options.coverage_exclude.length > 0) {
+        await this.excludeFromCoverage(coverageProfilePath, options.coverage_exclude);
+      }
+
+      // Parse coverage report
+      let coverageReport;
+      try {
+        await fs.access(coverageProfilePath);
+        coverageReport = await coverageParser.parseGoCoverageProfile(coverageProfilePath);
+      } catch {
+        // Try parsing from stdout if profile not available
+        logger.warn('Coverage profile not found, parsing from stdout');
+        coverageReport = await coverageParser.parseGoCoverage(result.stdout);
+      }
+
+      // Parse test results from JSON output
+      const testResults = this.parseGoTestOutput(result.stdout, result.stderr, result.buildOutput, cancelled);
+      if (result.timedOutTests && result.timedOutTests.length > 0) {
+        this.applyTimeouts(testResults, result, options.test_timeout_seconds || 0);
+      }
+      if (result.oomKilled) {
+        this.applyOutOfMemory(testResults, options);
+      }
+      if (ginkgo) {
+        this.mergeGinkgoResults(testResults, ginkgo);
+      }
+      if (!prebuilt && testResults.testCases.some(c => c.panic)) {
+        await this.markUnrunTests(workspacePath, testResults.testCases, options);
+      }
+      const dataRaces = options.race ? this.attachDataRaces(testResults) : undefined;
+      const goroutineLeaks = leakOverlay ? this.attachGoroutineLeaks(testResults.testCases, result.stdout) : undefined;
+      const shuffle = shuffleSeed !== undefined ? this.recordShuffleSeeds(shuffleSeed, result.stdout, testResults.testCases) : undefined;
+
+      if (options.retries && options.retries > 0 && testResults.failed > 0 && !cancelled) {
+        await this.retryFailedTests(workspacePath, testResults, options, testFlags, signal, runHandlers);
+      }
+
+      // Results of processes that finished before the interruption are restored, not rerun
+      if (checkpointer && this.applyResumedResults(testResults, checkpointer)) {
+        result.exitCode = Math.max(result.exitCode, 1);
+      }
+
+      const packageLogs = await this.writePackageLogs(
+        workspacePath, result.packageOutput || this.splitOutputByPackage(result.rawOutput || ''), testResults.testCases, options
+      );
+
+      if (options.timings_path && !cancelled) {
+        await testSharder.updateTimings(options.timings_path, testResults.testCases).catch((error: any) =>
+          logger.warn(`Failed to update test timings: ${error.message}`)
+        );
+      }
+
+      if (cache && lookup && !cancelled) {
+        await this.storeCachedPackages(cache, lookup.keys, packagesToRun, testResults.testCases, coverageProfilePath);
+        this.applyCachedResults(testResults, lookup.hits);
+      }
+
+      // Enforce per-package coverage minimums
+      let coverageViolations: CoverageViolation[] | undefined;
+      if (options.coverage_config_path && !cancelled) {
+        const gate = await CoverageGate.fromFile(options.coverage_config_path);
+        const profile = await coverageProfileService.readProfile(coverageProfilePath);
+        coverageViolations = gate.evaluate(profile);
+      }
+
+      const changed = options.changed_coverage_base && !cancelled
+        ? await this.measureChangedCoverage(workspacePath, coverageProfilePath, options.changed_coverage_base, options)
+        : undefined;
+      if (changed?.failure) {
+        testResults.failures.push(changed.failure);
+      }
+
+      const lint = options.lint && !cancelled ? await this.runLint(workspacePath, options) : undefined;
+      if (lint?.failure) {
+        testResults.failures.push(lint.failure);
+      }
+
+      const success = !cancelled && this.isRunSuccessful(result.exitCode, testResults.testCases, options) &&
+        (!coverageViolations || coverageViolations.length === 0) &&
+        (!changed || changed.passed) &&
+        (!lint || lint.passed);
+
+      let testDiff: DiffSummary | undefined;
+      let storedRunId: string | undefined;
+      if (this.wantsSummary(options)) {
+        const summary = createRunSummary({
+          framework: this.framework,
+          startedAt: new Date(startTime),
+          finishedAt: new Date(),
+          goVersion: await this.getGoVersion(workspacePath).catch(() => undefined),
+          gitSha: await this.getGitSha(workspacePath),
+          runId: log.runId,
+          tests: testResults.testCases,
+          coveragePercentage: coverageReport.line_coverage,
+          coverageProfile: await coverageProfileService.readProfile(coverageProfilePath).catch(() => undefined),
+          changedCoverage: changed?.report,
+          lintFindings: lint?.findings.length,
+          buildFailures: testResults.buildFailures,
+          shuffle,
+          slowest: options.slowest,
+          success,
+          cancelled: cancelled && !abortedEarly,
+          abortedEarly,
+        });
+        if (checkpointer) {
+          summary.checkpoint = checkpointer.progress();
+        }
+        await this.writeReports(workspacePath, summary, options);
+        for (const handler of this.summaryHandlers) {
+          await handler.handleSummary(summary);
+        }
+        if (options.webhook_url) {
+          await webhookService.notify(summary, { url: options.webhook_url, template: options.webhook_template });
+        }
+        const store = this.resultStore(options);
+        if (options.baseline_summary_path) {
+          const baselinePath = options.baseline_summary_path;
+          testDiff = await this.diffAgainstBaseline(baselinePath, () => readRunSummary(baselinePath), summary);
+        } else if (store && options.baseline_run_id) {
+          const baselineRun = options.baseline_run_id;
+          testDiff = await this.diffAgainstBaseline(`run ${baselineRun}`, () => this.readStoredBaseline(store, baselineRun, checkpointer?.runId), summary);
+        }
+        if (checkpointer && !checkpointer.finished) {
+          // The last checkpoint stays in place: this summary also holds partial results of unfinished processes
+          logger.info(`Run ${checkpointer.runId} did not finish; continue it with resume_run_id=${checkpointer.runId}`);
+        } else if (store) {
+          storedRunId = await this.storeSummary(store, summary, options.result_store!, checkpointer?.runId);
+        }
+      }
+
+      const durations = options.slowest !== undefined
+        ? buildDurationReport(testResults.testCases, options.slowest)
+        : undefined;
+      if (durations) {
+        logger.info(durationReporter.renderText(durations, { color: colorEnabled(options.color) }));
+      }
+      if (options.collapse_subtests) {
+        const list = testListReporter.renderText(testResults.testCases, { collapseSubtests: true, color: colorEnabled(options.color) });
+        logger.info(`Test results:\n${list}`);
+      }
+
+      log.info('Run finished', {
+        success,
+        tests: testResults.total,
+        failed: testResults.failed,
+        duration_ms: Date.now() - startTime,
+      });
+
+      return {
+        success,
+        passed_tests: testResults.passed,
+        failed_tests: testResults.failed,
+        total_tests: testResults.total,
+        coverage_percentage: coverageReport.line_coverage,
+        duration_ms: Date.now() - startTime,
+        failures: testResults.failures,
+        stdout: result.stdout,
+        stderr: result.stderr,
+        test_cases: testResults.testCases,
+        coverage_violations: coverageViolations,
+        data_races: dataRaces,
+        goroutine_leaks: goroutineLeaks,
+        out_of_memory: result.oomKilled || undefined,
+        lint_findings: lint?.findings,
+        ...this.artifactFields(result.artifacts),
+        cancelled: (cancelled && !abortedEarly) || undefined,
+        aborted_early: abortedEarly || undefined,
+        build_failures: testResults.buildFailures.length > 0 ? testResults.buildFailures : undefined,
+        shuffle,
+        test_diff: testDiff,
+        changed_coverage: changed?.report,
+        durations,
+        package_logs: packageLogs,
+        stored_run_id: storedRunId,
+        cache_bypassed: cacheBypassed,
+        checkpoint_run_id: checkpointer?.runId,
+      };
+
+    } catch (error: any) {
+      logger.error(`Go test execution failed: ${error.message}`);
+      log.debug('Run failed', { error: error.message, duration_ms: Date.now() - startTime });
+
+      return {
+        success: false,
+        passed_tests: 0,
+        failed_tests: 0,
+        total_tests: 0,
+        coverage_percentage: 0,
+        duration_ms: Date.now() - startTime,
+        failures: [{
+          test_name: 'go_test_execution',
+          error_message: error.message,
+          stack_trace: error.stack || '',
+          location: 'unknown',
+        }],
+        stdout: error.stdout || '',
+        stderr: error.stderr || error.message,
+      };
+    } finally {
+      const volume = this.sourceVolumes.get(options);
+      if (volume) {
+        this.sourceVolumes.delete(options);
+        await sandboxService.removeWorkspaceSnapshot(volume);
+      }
+      const services = this.runningServices.get(options);
+      if (services) {
+        this.runningServices.delete(options);
+        await serviceContainers.stop(services);
+      }
+      this.checkpointers.delete(options);
+    }
+  }
+
+  /**
+   * Run the suite once per Go version, each in its own golang image
+   * Every image is validated (and pulled) before the first version runs.
+   * Reports requested in the options are written once per version, with the
+   * version appended to the file name.
+   */
+  private async executeMatrix(
+    workspacePath: string,
+    codeFilePath: string,
+    testFilePath: string,
+    options: TestExecutionOptions,
+    signal?: AbortSignal
+  ): Promise<TestExecutionResult> {
+    const startTime = Date.now();
+    const versions = options.go_versions!;
+    const images = versions.map(v => this.matrixImage(v));
+
+    const mirror = sandboxService.getDefaultConfig(options).registry_mirror;
+    for (const image of images) {
+      await sandboxService.ensureImage(image, undefined, mirror);
+    }
+
+    // Every version writes the same coverage.out; each profile is read back before the next version runs
+    const coverageProfilePath = path.join(workspacePath, 'reports', 'coverage.out');
+
+    // Each version checkpoints as <matrix run>-go<version>; resuming continues
+    // the versions that have a checkpoint and runs the others from the start
+    const checkpointed = options.checkpoint || options.resume_run_id !== undefined;
+    const matrixRunId = options.resume_run_id || currentRunLog().runId;
+    const storedRuns = options.resume_run_id ? new Set(await this.checkpointStore(options).list()) : undefined;
+
+    const runs: MatrixRunResult[] = [];
+    for (let i = 0; i < versions.length && !signal?.aborted; i++) {
+      logger.info(`Go version matrix: running on ${images[i]} (${i + 1}/${versions.length})`);
+      await fs.rm(coverageProfilePath, { force: true });
+      const versionRunId = `${matrixRunId}-go${versions[i]}`;
+      const versionOptions: TestExecutionOptions = {
+        ...options,
+        go_versions: undefined,
+        sandbox: true,
+        image: images[i],
+        summary_json_path: this.versionedPath(options.summary_json_path, versions[i]),
+        junit_output_path: this.versionedPath(options.junit_output_path, versions[i]),
+        tap_output_path: this.versionedPath(options.tap_output_path, versions[i]),
+        coverage_html_dir: this.versionedPath(options.coverage_html_dir, versions[i]),
+        bench_output_path: this.versionedPath(options.bench_output_path, versions[i]),
+        checkpoint: checkpointed || undefined,
+        resume_run_id: storedRuns?.has(versionRunId) ? versionRunId : undefined,
+      };
+      if (checkpointed) {
+        this.checkpointIds.set(versionOptions, versionRunId);
+      }
+      const result = await this.execute(workspacePath, codeFilePath, testFilePath, versionOptions, signal);
+      const coverageProfile = await coverageProfileService.readProfile(coverageProfilePath).catch(() => undefined);
+      if (coverageProfile) {
+        await coverageProfileService.writeProfile(this.versionedPath(coverageProfilePath, versions[i])!, coverageProfile);
+      }
+      runs.push({ goVersion: versions[i], image: images[i], result, coverageProfile });
+    }
+
+    // A statement covered on any version counts as covered; coverage.out ends up holding the union
+    const unified = unifyCoverage(runs);
+    if (unified) {
+      await coverageProfileService.writeProfile(coverageProfilePath, unified.profile);
+      unified.coverage.profile_path = coverageProfilePath;
+    }
+
+    const matrix = buildMatrixReport(runs, unified?.coverage);
+    if (options.matrix_report_path) {
+      await matrixReporter.writeReport(options.matrix_report_path, matrix);
+    }
+    logger.info(`Go version matrix:\n${matrixReporter.renderText(matrix)}`);
+
+    const results = runs.map(r => r.result);
+    const sum = (field: 'passed_tests' | 'failed_tests' | 'total_tests') => results.reduce((n, r) => n + r[field], 0);
+
+    return {
+      success: runs.length === versions.length && results.every(r => r.success),
+      passed_tests: sum('passed_tests'),
+      failed_tests: sum('failed_tests'),
+      total_tests: sum('total_tests'),
+      coverage_percentage: results.length > 0 ? Math.min(...results.map(r => r.coverage_percentage)) : 0, // Lowest across versions
+      duration_ms: Date.now() - startTime,
+      failures: runs.flatMap(r => r.result.failures.map(f => ({ ...f, test_name: `[go${r.goVersion}] ${f.test_name}` }))),
+      stdout: results.map(r => r.stdout).join(''),
+      stderr: results.map(r => r.stderr).join(''),
+      matrix,
+      cancelled: Boolean(signal?.aborted || results.some(r => r.cancelled)) || undefined,
+      checkpoint_run_id: checkpointed ? matrixRunId : undefined,
+    };
+  }
+
+  /**
+   * Append a Go version to a report path: reports/junit.xml -> reports/junit-go1.22.xml
+   */
+  private versionedPath(outputPath: string | undefined, version: string): string | undefined {
+    if (!outputPath) {
+      return undefined;
+    }
+    const parsed = path.parse(outputPath);
+    return path.join(parsed.dir, `${parsed.name}-go${version}${parsed.ext}`);
+  }
+
+  private matrixImage(version: string): string {
+    return `golang:${version}-alpine`;
+  }
+
+  /**
+   * Plan the run and report the plan instead of running it
+   * A matrix is planned once, on the first version's image; every version
+   * would run the same processes one after another.
+   */
+  private async dryRun(workspacePath: string, options: TestExecutionOptions, startTime: number): Promise<TestExecutionResult> {
+    const versions = options.go_versions && options.go_versions.length > 0 ? options.go_versions : undefined;
+    const planOptions = versions ? { ...options, sandbox: true, image: this.matrixImage(versions[0]) } : options;
+
+    const packages = await this.selectPackages(workspacePath, options);
+    const plan = await this.planRun(workspacePath, packages, planOptions);
+    if (versions) {
+      plan.go_versions = versions;
+      plan.images = versions.map(v => this.matrixImage(v));
+      plan.containers *= versions.length;
+      plan.estimated_duration_ms = plan.estimated_duration_ms !== undefined
+        ? plan.estimated_duration_ms * versions.length
+        : undefined;
+    }
+
+    logger.info(`Dry run, nothing was executed:\n${runPlanReporter.renderText(plan)}`);
+    if (options.plan_json_path) {
+      await runPlanReporter.writeReport(options.plan_json_path, plan);
+    }
+
+    return {
+      success: true,
+      passed_tests: 0,
+      failed_tests: 0,
+      total_tests: 0,
+      coverage_percentage: 0,
+      duration_ms: Date.now() - startTime,
+      failures: [],
+      stdout: '',
+      stderr: '',
+      plan,
+    };
+  }
+
+  /**
+   * List the tests the run would execute instead of running them
+   * Packages come from the run's selection (since_ref, packages) and build
+   * tags; go test -list names their top-level tests, which the run and label
+   * selection then narrow as they would a run. A matrix is listed on the
+   * first version's image.
+   */
+  private async listRun(workspacePath: string, options: TestExecutionOptions, startTime: number): Promise<TestExecutionResult> {
+    if (options.test_binaries_dir !== undefined) {
+      throw new Error('list needs package sources and cannot be used with test_binaries_dir');
+    }
+    const versions = options.go_versions && options.go_versions.length > 0 ? options.go_versions : undefined;
+    const listOptions = versions ? { ...options, sandbox: true, image: this.matrixImage(versions[0]) } : options;
+    if (listOptions.sandbox) {
+      const sandboxConfig = this.getSandboxConfig(workspacePath, listOptions);
+      await sandboxService.ensureImage(sandboxConfig.image, undefined, sandboxConfig.registry_mirror);
+    }
+
+    const packages = await this.selectPackages(workspacePath, options);
+    const listed = packages.length > 0
+      ? await this.listPackageTests(workspacePath, packages, listOptions)
+      : { tests: new Map<string, string[]>(), failed: [] };
+    const infos = new Map((await goPackageSelector.listPackages(workspacePath, options.tags)).map(p => [p.ImportPath, p]));
+    const timings: TestTimings = options.timings_path ? await testSharder.readTimings(options.timings_path) : {};
+    const nameFilter = options.run !== undefined ? new RegExp(options.run) : undefined;
+
+    const tests: ListedTest[] = [];
+    for (const [pkg, names] of listed.tests) {
+      const info = infos.get(pkg);
+      const scanned = info ? await this.scanTests(info) : {};
+      // Tests the scan missed (e.g. in generated files) have no labels
+      const labels = Object.fromEntries(names.map(name => [name, scanned[name] || []]));
+      for (const name of testLabelScanner.select(labels, options).filter(n => !nameFilter || nameFilter.test(n))) {
+        tests.push({ id: `${pkg}.${name}`, package: pkg, name, labels: labels[name], duration_ms: timings[pkg]?.[name] });
+      }
+    }
+
+    const inventory: TestInventory = {
+      schemaVersion: TEST_INVENTORY_SCHEMA_VERSION,
+      since_ref: options.since_ref,
+      tags: options.tags,
+      packages: [...new Set(tests.map(t => t.package))].sort(),
+      unlisted_packages: [...listed.failed].sort(),
+      tests: tests.sort((a, b) => a.package.localeCompare(b.package) || a.name.localeCompare(b.name)),
+    };
+
+    logger.info(`Listed ${inventory.tests.length} tests in ${inventory.packages.length} packages, nothing was run`);
+    if (inventory.unlisted_packages.length > 0) {
+      logger.warn(`Could not list the tests of ${inventory.unlisted_packages.join(', ')}`);
+    }
+    if (options.list_json_path) {
+      await testInventoryReporter.writeReport(options.list_json_path, inventory);
+    }
+
+    return {
+      success: inventory.unlisted_packages.length === 0,
+      passed_tests: 0,
+      failed_tests: 0,
+      total_tests: 0,
+      coverage_percentage: 0,
+      duration_ms: Date.now() - startTime,
+      failures: inventory.unlisted_packages.map(pkg => ({
+        test_name: `${pkg} [list failed]`,
+        error_message: 'go test -list failed; the package likely does not compile',
+        stack_trace: '',
+        location: pkg,
+      })),
+      stdout: testInventoryReporter.renderJson(inventory),
+      stderr: '',
+      inventory,
+    };
+  }
+
+  /**
+   * Top-level tests of every package matching the patterns, from one go test -list
+   * go test prints each package's names followed by its ok, ? or FAIL line.
+   * Benchmarks are left out, since a run does not execute them.
+   * @returns Tests by import path, and the packages whose tests could not be listed
+   */
+  private async listPackageTests(
+    workspacePath: string,
+    patterns: string[],
+    options: TestExecutionOptions
+  ): Promise<{ tests: Map<string, string[]>; failed: string[] }> {
+    const args = ['test', '-list', '.', ...buildTagFlags(options.tags), ...patterns];
+    let stdout: string;
+    if (options.sandbox) {
+      stdout = (await sandboxService.executeInSandbox(
+        this.getSandboxConfig(workspacePath, options), ['go', ...args], workspacePath, workspacePath
+      )).stdout;
+    } else {
+      try {
+        stdout = (await execFileAsync('go', args, { cwd: workspacePath, maxBuffer: 10 * 1024 * 1024 })).stdout;
+      } catch (error: any) {
+        // A package that fails to build fails the command; the others are still listed
+        if (typeof error.stdout !== 'string') {
+          throw error;
+        }
+        stdout = error.stdout;
+      }
+    }
+
+    const tests = new Map<string, string[]>();
+    const failed: string[] = [];
+    let pending: string[] = [];
+    for (const line of stdout.split('\n').map(l => l.trim())) {
+      const summary = line.match(/^(ok|FAIL|\?)\s+(\S+)/);
+      if (summary) {
+        if (summary[1] === 'FAIL') {
+          failed.push(summary[2]);
+        } else if (pending.length > 0) {
+          tests.set(summary[2], pending);
+        }
+        pending = [];
+      } else if (/^(Test|Example|Fuzz)\w*$/.test(line)) {
+        pending.push(line);
+      }
+    }
+    return { tests, failed };
+  }
+
+  /**
+   * Work out what a run would execute
+   * Uses the run's framework routing, cache lookup, sharding, and label
+   * selection, but builds nothing and creates no container: shards are
+   * balanced over the top-level tests found in the sources rather than
+   * listed by go test -list, and images are neither inspected nor pulled.
+   */
+  private async planRun(workspacePath: string, packages: string[], options: TestExecutionOptions): Promise<RunPlan> {
+    const testEnv = await this.testEnvironment(options);
+    const routing = options.detect_framework && packages.length > 0
+      ? await this.routeByFramework(workspacePath, packages, options)
+      : undefined;
+    const goPackages = routing ? routing.standard : packages;
+
+    const prebuilt = options.test_binaries_dir !== undefined;
+    const labelled = this.hasLabelSelection(options);
+    if (labelled && prebuilt) {
+      throw new Error('labels and exclude_labels need package sources and cannot be used with test_binaries_dir');
+    }
+
+    const cache = this.resultCache(options);
+    const lookup = cache && goPackages.length > 0
+      ? await this.lookupCachedPackages(cache, workspacePath, goPackages, this.testFlags(options), testEnv, options.tags)
+      : undefined;
+    const packagesToRun = lookup ? lookup.misses : goPackages;
+    const importPaths = packagesToRun.length > 0
+      ? (await this.executorFor(options).resolvePackages(workspacePath, packagesToRun, options.tags)).sort()
+      : [];
+
+    const sharded = options.shards !== undefined && options.shards > 1;
+    const pooled = options.parallel !== undefined || sharded || prebuilt || labelled;
+    const concurrency = options.parallel && options.parallel > 0 ? options.parallel : os.cpus().length;
+
+    const listed = sharded
+      ? new Map((await goPackageSelector.listPackages(workspacePath, options.tags).catch(() => [])).map(p => [p.ImportPath, p]))
+      : new Map<string, GoPackageInfo>();
+    const listTests = async (pkg: string) => {
+      const info = listed.get(pkg);
+      return info ? Object.keys(await this.scanTests(info).catch(() => ({}))).sort() : [];
+    };
+
+    const timings = options.timings_path ? await testSharder.readTimings(options.timings_path) : undefined;
+    const estimate = (pkg: string, tests?: string[]): number | undefined => {
+      const known = timings?.[pkg];
+      return known ? (tests || Object.keys(known)).reduce((sum, test) => sum + (known[test] || 0), 0) : undefined;
+    };
+
+    let processes: PlannedProcess[];
+    if (pooled) {
+      const runs = await this.schedulePackageRuns(workspacePath, importPaths, options, listTests);
+      processes = runs.map(run => ({ packages: [run.pkg], tests: run.tests, estimated_ms: estimate(run.pkg, run.tests) }));
+    } else if (importPaths.length > 0) {
+      // go test runs the packages of one process up to GOMAXPROCS at a time
+      const known = importPaths.map(pkg => estimate(pkg)).filter((ms): ms is number => ms !== undefined);
+      processes = [{
+        packages: importPaths,
+        estimated_ms: known.length > 0 ? testSharder.estimateWallTime(known, concurrency) : undefined,
+      }];
+    } else {
+      processes = [];
+    }
+
+    const estimated = processes.filter(p => p.estimated_ms !== undefined).map(p => p.estimated_ms!);
+    const selected = packages.length > 0 ? await this.executorFor(options).resolvePackages(workspacePath, packages, options.tags) : [];
+    return {
+      since_ref: options.since_ref,
+      packages: [...selected].sort(),
+      cached: (lookup?.hits.map(hit => hit.package) || []).sort(),
+      ginkgo_suites: (routing?.ginkgo || []).sort(),
+      processes,
+      concurrency,
+      images: options.sandbox ? [this.getSandboxConfig(workspacePath, options).image] : [],
+      containers: options.sandbox ? processes.length : 0,
+      // A single process's estimate already accounts for its packages running side by side
+      estimated_duration_ms: timings ? testSharder.estimateWallTime(estimated, pooled ? concurrency : 1) : undefined,
+      unestimated_processes: processes.length - estimated.length,
+    };
+  }
+
+  /**
+   * Determine which packages to test
+   * Defaults to every package in the workspace (or options.packages); with
+   * since_ref, only packages affected by changes since that ref (falls back
+   * to all requested packages on error).
+   */
+  private async selectPackages(
+    workspacePath: string,
+    options: TestExecutionOptions
+  ): Promise<string[]> {
+    const requested = options.packages && options.packages.length > 0 ? options.packages : ['./...'];
+    if (!options.since_ref) {
+      return requested; // All packages in the workspace unless a subset was requested
+    }
+
+    try {
+      const changed = await goPackageSelector.changedFilesSince(workspacePath, options.since_ref);
+      return await goPackageSelector.affectedPackages(workspacePath, changed, options.tags);
+    } catch (error: any) {
+      logger.warn(`Incremental package selection failed, testing all packages: ${error.message}`);
+      return requested;
+    }
+  }
+
+  /**
+   * Building the summary costs a go and a git invocation, so only do it when something consumes it
+   */
+  private wantsSummary(options: TestExecutionOptions): boolean {
+    return Boolean(
+      options.summary_json_path || options.junit_output_path || options.tap_output_path || options.coverage_html_dir ||
+      options.baseline_summary_path || options.webhook_url || options.result_store || this.summaryHandlers.length > 0
+    );
+  }
+
+  /**
+   * Render every requested report from the run summary
+   * A report that cannot be written is logged and does not fail the run.
+   */
+  private async writeReports(workspacePath: string, summary: RunSummary, options: TestExecutionOptions): Promise<void> {
+    const { summary_json_path: jsonPath, junit_output_path: junitPath, tap_output_path: tapPath, coverage_html_dir: htmlDir } = options;
+
+    if (jsonPath) {
+      await this.writeReport('JSON summary', () => jsonSummaryReporter.writeReport(jsonPath, summary));
+    }
+
+    if (junitPath) {
+      await this.writeReport('JUnit', () => junitReporter.writeReport(junitPath, summary));
+    }
+
+    if (tapPath) {
+      await this.writeReport('TAP', () => tapReporter.writeReport(tapPath, summary));
+    }
+
+    if (htmlDir) {
+      await this.writeReport('HTML coverage', async () => {
+        const goMod = await fs.readFile(path.join(workspacePath, 'go.mod'), 'utf-8');
+        const modulePath = goMod.match(/^module\s+(\S+)/m)?.[1] || '';
+        await htmlCoverageReporter.writeHtmlCoverage(htmlDir, summary, moduleSourceFS(workspacePath, modulePath));
+      });
+    }
+  }
+
+  private async writeReport(kind: string, write: () => Promise<void>): Promise<void> {
+    try {
+      await write();
+    } catch (error: any) {
+      logger.warn(`Failed to write ${kind} report: ${error.message}`);
+    }
+  }
+
+  /**
+   * Compare this run with the target branch's stored summary
+   * A missing or unreadable baseline (e.g. the first run on a branch) is
+   * logged and yields no diff rather than failing the run.
+   * @param baseline Describes the baseline in messages
+   * @param load Reads the baseline summary
+   */
+  private async diffAgainstBaseline(
+    baseline: string,
+    load: () => Promise<RunSummary>,
+    summary: RunSummary
+  ): Promise<DiffSummary | undefined> {
+    try {
+      const diff = diffRunSummaries(await load(), summary);
+      logger.info(
+        `Against baseline: ${diff.newly_failing.length} newly failing, ${diff.newly_passing.length} fixed, ` +
+        `${diff.still_failing.length} still failing, ${diff.added.length} added, ${diff.removed.length} removed`
+      );
+      return diff;
+    } catch (error: any) {
+      logger.warn(`Failed to read baseline summary ${baseline}: ${error.message}`);
+      return undefined;
+    }
+  }
+
+  /**
+   * Result store named by result_store, if any
+   */
+  private resultStore(options: TestExecutionOptions): ResultStore | undefined {
+    if (!options.result_store) {
+      return undefined;
+    }
+    return options.result_store.startsWith('s3://')
+      ? new S3ResultStore({
+        ...parseS3Location(options.result_store),
+        endpoint: options.result_store_endpoint,
+        region: options.result_store_region,
+      })
+      : new FileResultStore(path.resolve(options.result_store));
+  }
+
+  /**
+   * A stored run by ID; 'latest' is the most recently stored run
+   * @param currentRunId ID this run checkpoints under, which is never its own baseline
+   */
+  private async readStoredBaseline(store: ResultStore, runId: string, currentRunId?: string): Promise<RunSummary> {
+    if (runId !== 'latest') {
+      return store.get(runId);
+    }
+
+    // A checkpointed run that has not finished yet is no baseline
+    const runs = await store.list();
+    for (let i = runs.length - 1; i >= 0; i--) {
+      if (runs[i] === currentRunId) {
+        continue;
+      }
+      const summary = await store.get(runs[i]);
+      if (!isUnfinishedCheckpoint(summary)) {
+        return summary;
+      }
+    }
+    throw new Error('no runs stored yet');
+  }
+
+  /**
+   * Keep the summary for later runs under its run_id
+   * A store that cannot be reached is logged and does not fail the run.
+   * @returns Stored run ID, or undefined when storing failed
+   */
+  private async storeSummary(
+    store: ResultStore,
+    summary: RunSummary,
+    location: string,
+    runId: string = summary.metadata.run_id || summary.metadata.started_at.replace(/[:.]/g, '-')
+  ): Promise<string | undefined> {
+    try {
+      await store.put(runId, summary);
+      logger.info(`Stored run ${runId} in ${location}`);
+      return runId;
+    } catch (error: any) {
+      logger.warn(`Failed to store run ${runId} in ${location}: ${error.message}`);
+      return undefined;
+    }
+  }
+
+  /**
+   * Result store holding the checkpoints
+   * @throws If no result_store is set
+   */
+  private checkpointStore(options: TestExecutionOptions): ResultStore {
+    const store = this.resultStore(options);
+    if (!store) {
+      throw new Error('checkpoint and resume_run_id need a result_store');
+    }
+    return store;
+  }
+
+  /**
+   * Summary stored by the run resume_run_id names
+   * @throws If it has no checkpoint, or one taken with options selecting other tests
+   */
+  private async readCheckpoint(options: TestExecutionOptions): Promise<RunSummary> {
+    const runId = options.resume_run_id!;
+    const summary = await this.checkpointStore(options).get(runId);
+    resumableCheckpoint(summary, runId, options);
+    return summary;
+  }
+
+  /**
+   * Checkpointer storing the run's progress under its ID, or continuing a resumed run under the same one
+   */
+  private startCheckpoint(
+    packages: string[],
+    options: TestExecutionOptions,
+    shuffleSeed: number | undefined,
+    startTime: number,
+    resumed?: RunSummary
+  ): RunCheckpointer {
+    const runId = this.checkpointIds.get(options) || options.resume_run_id || currentRunLog().runId;
+    const selection = resumed ? resumed.checkpoint!.selection : checkpointSelection(packages, options, shuffleSeed);
+
+    return new RunCheckpointer(this.checkpointStore(options), runId, selection, (tests, buildFailures, profile) => createRunSummary({
+      framework: this.framework,
+      startedAt: new Date(startTime),
+      finishedAt: new Date(),
+      runId: currentRunLog().runId,
+      tests,
+      coveragePercentage: profile ? coverageProfileService.statementCoverage(profile).percentage : 0,
+      coverageProfile: profile,
+      buildFailures,
+      success: false,
+    }), resumed);
+  }
+
+  /**
+   * HEAD commit of the workspace, or undefined outside a git checkout
+   */
+  private async getGitSha(workspacePath: string): Promise<string | undefined> {
+    try {
+      const { stdout } = await execFileAsync('git', ['rev-parse', 'HEAD'], { cwd: workspacePath });
+      return stdout.trim() || undefined;
+    } catch {
+      return undefined;
+    }
+  }
+
+  /**
+   * Sandbox settings for go test containers
+   * Build and module caches live under the mounted workspace so they survive
+   * between runs (go ignores dot-directories when matching ./...).
+   */
+  private getSandboxConfig(workspacePath: string, options: TestExecutionOptions): SandboxConfig {
+    return {
+      ...sandboxService.getDefaultConfig(options),
+      image: options.image || sandboxService.getImageForFramework(this.framework),
+      artifacts_dir: options.artifacts_dir || path.join(workspacePath, 'reports', 'artifacts'),
+      env: {
+        HOME: '/tmp',
+        GOCACHE: path.join(workspacePath, '.cache', 'go-build'),
+        GOMODCACHE: path.join(workspacePath, '.cache', 'go-mod'),
+        ...this.buildEnvironment(),
+      },
+      // Copy mounts keep reports and caches on the host so profiles and builds survive
+      mount: options.mount,
+      source_volume: this.sourceVolumes.get(options),
+      network: this.runningServices.get(options)?.network,
+      output_paths: options.mount === 'copy'
+        ? [path.join(workspacePath, 'reports'), path.join(workspacePath, '.cache')]
+        : undefined,
+    };
+  }
+
+  /**
+   * Summarize artifact copies across containers for the execution result
+   */
+  private artifactFields(artifacts: ArtifactCopyResult[] = []): Pick<TestExecutionResult, 'artifact_dirs' | 'artifact_warnings'> {
+    if (artifacts.length === 0) {
+      return {};
+    }
+
+    const dirs = artifacts.map(a => a.dir).filter((d): d is string => Boolean(d));
+    const warnings = artifacts.flatMap(a => a.warnings);
+    return {
+      artifact_dirs: dirs.length > 0 ? dirs : undefined,
+      artifact_warnings: warnings.length > 0 ? warnings : undefined,
+    };
+  }
+
+  /**
+   * Report an OOM-killed container as out of memory instead of a bare crash
+   * Packages killed mid-run fail outside of any test, so their synthetic
+   * package results carry the message.
+   */
+  private applyOutOfMemory(
+    testResults: { failed: number; total: number; failures: TestFailure[]; testCases: TestCaseResult[] },
+    options: TestExecutionOptions
+  ): void {
+    const limitMb = sandboxService.getDefaultConfig(options).memory_limit_mb;
+    const message = `Out of memory: exceeded the ${limitMb}MB container memory limit`;
+    const crashed = testResults.testCases.filter(c => c.name === '[package]');
+
+    if (crashed.length === 0) {
+      crashed.push({ package: '', name: '[out of memory]', status: 'error', duration_ms: 0, output: '' });
+      testResults.testCases.push(crashed[0]);
+    }
+
+    for (const testCase of crashed) {
+      testCase.failure_message = message;
+      testResults.failures.push({
+        test_name: testCase.package ? `${testCase.package} ${testCase.name}` : testCase.name,
+        error_message: message,
+        stack_trace: testCase.output,
+        location: 'unknown',
+      });
+    }
+
+    logger.error(message);
+  }
+
+  /**
+   * Split packages between go test and Ginkgo
+   * Ginkgo suites are returned as directories relative to the workspace,
+   * which is what the ginkgo CLI expects.
+   */
+  private async routeByFramework(
+    workspacePath: string,
+    packages: string[],
+    options: TestExecutionOptions
+  ): Promise<{ standard: string[]; ginkgo: string[] }> {
+    const selected = new Set(await goToolchainExecutor.resolvePackages(workspacePath, packages, options.tags));
+    const listed = (await goPackageSelector.listPackages(workspacePath, options.tags)).filter(p => selected.has(p.ImportPath));
+    const standard: string[] = [];
+    const ginkgo: string[] = [];
+
+    for (const pkg of listed) {
+      const framework = await goFrameworkDetector.resolve(pkg.ImportPath, pkg.Dir, options.framework_overrides);
+      if (framework === GoFramework.Ginkgo) {
+        ginkgo.push('./' + (path.relative(workspacePath, pkg.Dir) || '.'));
+      } else {
+        standard.push(pkg.ImportPath);
+      }
+    }
+
+    logger.info(`Framework detection: ${standard.length} go test packages, ${ginkgo.length} Ginkgo suites`);
+    return { standard, ginkgo };
+  }
+
+  /**
+   * Fold Ginkgo spec results into the go test results
+   */
+  private mergeGinkgoResults(
+    testResults: { passed: number; failed: number; total: number; failures: TestFailure[]; testCases: TestCaseResult[] },
+    ginkgo: { testCases: TestCaseResult[]; failures: TestFailure[] }
+  ): void {
+    const passed = ginkgo.testCases.filter(c => c.status === 'passed').length;
+
+    testResults.testCases.push(...ginkgo.testCases);
+    testResults.failures.push(...ginkgo.failures);
+    testResults.passed += passed;
+    testResults.failed += ginkgo.failures.length;
+    testResults.total += passed + ginkgo.failures.length;
+  }
+
+  /**
+   * Bench mode: run only benchmarks and compare them with a saved baseline
+   * Regressions beyond bench_threshold_percent are reported as failures.
+   */
+  private async executeBenchmarks(
+    workspacePath: string,
+    packages: string[],
+    options: TestExecutionOptions,
+    startTime: number,
+    signal?: AbortSignal
+  ): Promise<TestExecutionResult> {
+    const command = goToolchainExecutor.command({
+      packages,
+      flags: ['-json', '-run=^$', `-bench=${options.bench}`, '-benchmem', ...buildTagFlags(options.tags)],
+    });
+    const result = await this.executeGoTest(workspacePath, command, options, signal);
+    if (result.cancelled) {
+      logger.warn('Benchmark run cancelled; reporting partial results');
+    }
+    const testResults = this.parseGoTestOutput(result.stdout, result.stderr, result.buildOutput);
+    const benchmarks = this.parseBenchmarks(result.stdout);
+
+    let comparison: BenchmarkComparison | undefined;
+    if (options.bench_baseline_path) {
+      const baseline = JSON.parse(await fs.readFile(options.bench_baseline_path, 'utf-8')) as BenchResult[];
+      comparison = benchmarkService.diff(baseline, benchmarks, options.bench_threshold_percent ?? 10);
+    }
+
+    if (options.bench_output_path) {
+      await fs.mkdir(path.dirname(options.bench_output_path), { recursive: true });
+      await fs.writeFile(options.bench_output_path, JSON.stringify(benchmarks, null, 2) + '\n', 'utf-8');
+    }
+
+    const failures = [...testResults.failures];
+    for (const regression of comparison?.regressions || []) {
+      const delta = regression.delta_percent === null ? 'up from 0' : `+${regression.delta_percent.toFixed(1)}%`;
+      failures.push({
+        test_name: regression.name,
+        error_message: `${regression.metric} regressed ${delta} (${regression.base} -> ${regression.current}), ` +
+          `threshold ${comparison!.threshold_percent}%`,
+        stack_trace: '',
+        location: regression.package,
+      });
+    }
+
+    return {
+      success: !result.cancelled && result.exitCode === 0 && (!comparison || comparison.regressions.length === 0),
+      passed_tests: testResults.passed,
+      failed_tests: testResults.failed,
+      total_tests: testResults.total,
+      coverage_percentage: 0,
+      duration_ms: Date.now() - startTime,
+      failures,
+      stdout: result.stdout,
+      stderr: result.stderr,
+      test_cases: testResults.testCases,
+      benchmarks,
+      benchmark_comparison: comparison,
+      cancelled: result.cancelled || undefined,
+    };
+  }
+
+  /**
+   * Collect benchmark results per package
+   * test2json may split a result line across output events (the name is
+   * printed before the benchmark runs), so each package's output is joined
+   * before parsing.
+   */
+  private parseBenchmarks(stdout: string): BenchResult[] {
+    const outputByPackage = new Map<string, string>();
+
+    for (const line of stdout.split('\n')) {
+      let event: any;
+      try {
+        event = JSON.parse(line);
+      } catch {
+        continue;
+      }
+      if (event && event.Action === 'output' && event.Output) {
+        const pkg = event.Package || '';
+        outputByPackage.set(pkg, (outputByPackage.get(pkg) || '') + event.Output);
+      }
+    }
+
+    return Array.from(outputByPackage.entries()).flatMap(([pkg, output]) => benchmarkService.parse(output, pkg));
+  }
+
+  /**
+   * Run golangci-lint alongside the tests
+   * Findings only fail the run when lint_fail_on_severity is set; a lint
+   * tool failure always does, but never discards the test results.
+   */
+  private async runLint(
+    workspacePath: string,
+    options: TestExecutionOptions
+  ): Promise<{ findings: LintFinding[]; passed: boolean; failure?: TestFailure }> {
+    try {
+      const findings = await lintService.run(workspacePath, {
+        config_path: options.lint_config_path,
+        timeout_seconds: options.timeout_seconds,
+      });
+      const blocking = options.lint_fail_on_severity
+        ? lintService.atOrAbove(findings, options.lint_fail_on_severity)
+        : [];
+      return { findings, passed: blocking.length === 0 };
+    } catch (error: any) {
+      logger.error(`Lint failed: ${error.message}`);
+      return {
+        findings: [],
+        passed: false,
+        failure: {
+          test_name: 'golangci_lint_execution',
+          error_message: error.message,
+          stack_trace: '',
+          location: 'unknown',
+        },
+      };
+    }
+  }
+
+  /**
+   * Coverage of the lines changed since a git ref, checked against changed_coverage_minimum
+   * Without a minimum a failure to diff only loses the report; with one it
+   * fails the run rather than letting the check pass unmeasured.
+   */
+  private async measureChangedCoverage(
+    workspacePath: string,
+    coverageProfilePath: string,
+    baseRef: string,
+    options: TestExecutionOptions
+  ): Promise<{ report?: ChangedCoverageReport; passed: boolean; failure?: TestFailure }> {
+    const minimum = options.changed_coverage_minimum;
+    try {
+      const profile = await coverageProfileService.readProfile(coverageProfilePath);
+      const diff = await changedLineCoverageService.changedLinesSince(workspacePath, baseRef);
+      const report = { ...changedLineCoverageService.changedLineCoverage(profile, diff), required: minimum };
+
+      logger.info(
+        `Changed lines since ${baseRef}: ${report.covered_lines} of ${report.executable_lines} ` +
+        `executable lines covered (${report.percentage.toFixed(1)}%)`
+      );
+      const passed = minimum === undefined || report.percentage >= minimum;
+      return {
+        report,
+        passed,
+        failure: passed ? undefined : {
+          test_name: 'changed_line_coverage',
+          error_message: `Changed lines are ${report.percentage.toFixed(1)}% covered, below the required ${minimum}%`,
+          stack_trace: '',
+          location: report.files
+            .filter(f => f.uncovered_lines.length > 0)
+            .map(f => `${f.file}:${f.uncovered_lines.join(',')}`)
+            .join(' ') || 'unknown',
+        },
+      };
+    } catch (error: any) {
+      logger.warn(`Could not measure coverage of changed lines since ${baseRef}: ${error.message}`);
+      return {
+        passed: minimum === undefined,
+        failure: minimum === undefined ? undefined : {
+          test_name: 'changed_line_coverage',
+          error_message: `Could not measure coverage of changed lines since ${baseRef}: ${error.message}`,
+          stack_trace: '',
+          location: 'unknown',
+        },
+      };
+    }
+  }
+
+  /**
+   * The race detector needs cgo; fail fast with a clear message instead of
+   * the toolchain's error (our Docker image builds with CGO_ENABLED=0)
+   */
+  private async assertRaceDetectorAvailable(workspacePath: string, options: TestExecutionOptions): Promise<void> {
+    const stdout = options.sandbox
+      ? (await sandboxService.executeInSandbox(
+        this.getSandboxConfig(workspacePath, options), ['go', 'env', 'CGO_ENABLED'], workspacePath, workspacePath
+      )).stdout
+      : (await execFileAsync('go', ['env', 'CGO_ENABLED'], { cwd: workspacePath })).stdout;
+
+    if (stdout.trim() !== '1') {
+      throw new Error(
+        'Race detection requested but cgo is disabled (CGO_ENABLED=0). ' +
+        '-race requires CGO_ENABLED=1 and a C toolchain such as gcc'
+      );
+    }
+  }
+
+  /**
+   * Attach parsed race reports to the tests that raised them
+   * A test can both fail an assertion and trip the detector; the assertion
+   * message is kept and the races are recorded alongside it.
+   */
+  private attachDataRaces(
+    testResults: { failures: TestFailure[]; testCases: TestCaseResult[] }
+  ): DataRace[] {
+    const allRaces: DataRace[] = [];
+
+    for (const testCase of testResults.testCases) {
+      const races = raceReportParser.parse(testCase.output).map(race => ({
+        ...race,
+        package: testCase.package,
+        test: testCase.name.startsWith('[') ? undefined : testCase.name,
+      }));
+      if (races.length === 0) {
+        continue;
+      }
+
+      testCase.data_races = races;
+      allRaces.push(...races);
+
+      if (testCase.status === 'failed' && testCase.failure_message === 'Test failed') {
+        testCase.failure_message = 'Data race detected';
+        const failure = testResults.failures.find(f => f.test_name === testCase.name);
+        if (failure) {
+          failure.error_message = testCase.failure_message;
+        }
+      }
+    }
+
+    if (allRaces.length > 0) {
+      logger.warn(`Race detector reported ${allRaces.length} data races`);
+    }
+
+    return allRaces;
+  }
+
+  /**
+   * Collect the leak check's reports from each package's output
+   * A package that failed only because of leaks gets a [goroutine leak]
+   * result in place of the generic [package] one.
+   */
+  private attachGoroutineLeaks(testCases: TestCaseResult[], stdout: string): GoroutineLeak[] {
+    const packageOutput = new Map<string, string>();
+    for (const line of stdout.split('\n')) {
+      try {
+        const event = JSON.parse(line);
+        if (event.Action === 'output' && event.Package && !event.Test) {
+          packageOutput.set(event.Package, (packageOutput.get(event.Package) || '') + event.Output);
+        }
+      } catch {
+        // Not a go test -json event
+      }
+    }
+
+    const leaks: GoroutineLeak[] = [];
+    for (const [pkg, output] of packageOutput) {
+      const found = goroutineLeakCheck.parse(output).map(leak => ({ package: pkg, ...leak }));
+      if (found.length === 0) {
+        continue;
+      }
+      leaks.push(...found);
+
+      const failed = testCases.find(c => c.package === pkg && c.name === '[package]' && !c.panic);
+      if (failed) {
+        failed.name = '[goroutine leak]';
+        failed.failure_message = `${found.length} goroutines still running after the tests finished`;
+      }
+    }
+
+    if (leaks.length > 0) {
+      logger.warn(`Leak check found ${leaks.length} leaked goroutines in ${new Set(leaks.map(l => l.package)).size} packages`);
+    }
+    return leaks;
+  }
+
+  /**
+   * Split selected packages into cache hits and packages that must run
+   * Any failure to list or hash packages disables the cache for this run.
+   */
+  private async lookupCachedPackages(
+    cache: TestResultCache,
+    workspacePath: string,
+    selected: string[],
+    testFlags: string[],
+    testEnv: TestEnvironment,
+    tags?: string[]
+  ): Promise<{ keys: Map<string, string>; hits: CachedPackageResult[]; misses: string[] } | undefined> {
+    try {
+      const listed = await goPackageSelector.listPackages(workspacePath, tags);
+      // Patterns such as ./store or ./api/... name packages by directory, not import path
+      const importPaths = new Set(await goToolchainExecutor.resolvePackages(workspacePath, selected, tags));
+      const wanted = listed.filter(p => importPaths.has(p.ImportPath));
+      const keys = await cache.computeKeys(workspacePath, listed, {
+        goVersion: await this.getGoVersion(workspacePath),
+        flags: testFlags,
+        // Only names of secrets, so a rotated token does not invalidate every entry
+        env: { ...this.buildEnvironment(), ...testEnv.env, ...this.secretNames(testEnv) },
+      });
+
+      const hits: CachedPackageResult[] = [];
+      const misses: string[] = [];
+      for (const pkg of wanted) {
+        const entry = await cache.get(keys.get(pkg.ImportPath)!);
+        if (entry) {
+          hits.push(entry);
+        } else {
+          misses.push(pkg.ImportPath);
+        }
+        currentRunLog().debug(entry ? 'Cache hit' : 'Cache miss', { package: pkg.ImportPath });
+      }
+
+      logger.info(`Test cache: ${hits.length} of ${wanted.length} packages unchanged, running ${misses.length}`);
+      return { keys, hits, misses };
+
+    } catch (error: any) {
+      logger.warn(`Test cache unavailable, running all packages: ${error.message}`);
+      return undefined;
+    }
+  }
+
+  /**
+   * Store results for packages that ran; the cache itself rejects failures
+   */
+  private async storeCachedPackages(
+    cache: TestResultCache,
+    keys: Map<string, string>,
+    ranPackages: string[],
+    testCases: TestCaseResult[],
+    coverageProfilePath: string
+  ): Promise<void> {
+    let profile: GoCoverageProfile | undefined;
+    try {
+      profile = await coverageProfileService.readProfile(coverageProfilePath);
+    } catch {
+      profile = undefined;
+    }
+
+    for (const pkg of ranPackages) {
+      const key = keys.get(pkg);
+      if (!key) {
+        continue;
+      }
+
+      try {
+        await cache.put(key, {
+          package: pkg,
+          test_cases: testCases.filter(c => c.package === pkg),
+          coverage: profile ? this.packageCoverage(profile, pkg) : undefined,
+          stored_at: new Date().toISOString(),
+        });
+      } catch (error: any) {
+        logger.warn(`Failed to cache results for ${pkg}: ${error.message}`);
+      }
+    }
+  }
+
+  /**
+   * Add cached test cases to the results of this run
+   */
+  private applyCachedResults(
+    testResults: { passed: number; failed: number; total: number; testCases: TestCaseResult[] },
+    hits: CachedPackageResult[]
+  ): void {
+    for (const hit of hits) {
+      for (const testCase of hit.test_cases) {
+        testResults.testCases.push({ ...testCase, cached: true });
+        if (testCase.status === 'passed') {
+          testResults.passed++;
+        }
+      }
+    }
+    testResults.total = testResults.passed + testResults.failed;
+  }
+
+  /**
+   * Add the results a resumed run restored from its checkpoint
+   * @returns Whether any restored test or build failed
+   */
+  private applyResumedResults(
+    testResults: { passed: number; failed: number; total: number; failures: TestFailure[]; testCases: TestCaseResult[]; buildFailures: BuildFailure[] },
+    checkpointer: RunCheckpointer
+  ): boolean {
+    let failing = checkpointer.restoredBuildFailures.length > 0;
+
+    for (const testCase of checkpointer.restoredTests) {
+      testResults.testCases.push(testCase);
+      if (testCase.status === 'passed') {
+        testResults.passed++;
+      } else if (testCase.status === 'failed' || testCase.status === 'error' || testCase.status === 'timed_out') {
+        testResults.failed++;
+        testResults.failures.push({
+          test_name: testCase.name,
+          error_message: testCase.failure_message || 'Test failed',
+          stack_trace: testCase.output,
+          location: this.extractLocation(testCase.output),
+        });
+        failing = true;
+      }
+    }
+    testResults.buildFailures.push(...checkpointer.restoredBuildFailures);
+    testResults.total = testResults.passed + testResults.failed;
+    return failing;
+  }
+
+  /**
+   * Fold cached coverage into the profile written by this run
+   */
+  private async mergeCachedCoverage(
+    coverageProfilePath: string,
+    hits: CachedPackageResult[],
+    ranPackages: boolean
+  ): Promise<void> {
+    const profiles = hits
+      .map(h => h.coverage)
+      .filter((p): p is GoCoverageProfile => p !== undefined);
+
+    try {
+      if (ranPackages) {
+        profiles.push(await coverageProfileService.readProfile(coverageProfilePath));
+      }
+      if (profiles.length > 0) {
+        await fs.mkdir(path.dirname(coverageProfilePath), { recursive: true });
+        await coverageProfileService.writeProfile(coverageProfilePath, coverageProfileService.mergeProfiles(...profiles));
+      }
+    } catch (error: any) {
+      logger.warn(`Failed to merge cached coverage: ${error.message}`);
+    }
+  }
+
+  /**
+   * Rewrite the profile without excluded files; a run without a profile is left alone
+   */
+  private async excludeFromCoverage(coverageProfilePath: string, exclude: string[]): Promise<void> {
+    let profile: GoCoverageProfile;
+    try {
+      profile = await coverageProfileService.readProfile(coverageProfilePath);
+    } catch {
+      logger.debug(`No coverage profile at ${coverageProfilePath} to filter`);
+      return;
+    }
+    await coverageProfileService.writeProfile(coverageProfilePath, coverageProfileService.filter(profile, exclude));
+  }
+
+  /**
+   * Blocks of a profile belonging to one package's files
+   */
+  private packageCoverage(profile: GoCoverageProfile, pkg: string): GoCoverageProfile {
+    const files: GoCoverageProfile['files'] = {};
+    for (const [fileName, blocks] of Object.entries(profile.files)) {
+      if (path.posix.dirname(fileName) === pkg) {
+        files[fileName] = blocks;
+      }
+    }
+    return { mode: profile.mode, files };
+  }
+
+  /**
+   * Toolchain version, part of every cache key
+   * Run from the workspace so a go.mod toolchain directive is honored.
+   */
+  private async getGoVersion(workspacePath: string): Promise<string> {
+    const { stdout } = await execFileAsync('go', ['env', 'GOVERSION'], { cwd: workspacePath });
+    return stdout.trim();
+  }
+
+  /**
+   * Variables injected into go test, resolved once per run
+   */
+  private testEnvironment(options: TestExecutionOptions): Promise<TestEnvironment> {
+    let env = this.testEnvironments.get(options);
+    if (!env) {
+      env = testEnvironmentLoader.load(options);
+      this.testEnvironments.set(options, env);
+    }
+    return env;
+  }
+
+  private secretNames(testEnv: TestEnvironment): Record<string, string> {
+    return Object.fromEntries(Object.keys(testEnv.secrets).map(name => [name, '<secret>']));
+  }
+
+  /**
+   * Environment variables that change how packages build
+   */
+  private buildEnvironment(): Record<string, string> {
+    const env: Record<string, string> = {};
+    for (const name of ['GOFLAGS', 'GOOS', 'GOARCH', 'CGO_ENABLED', 'GOEXPERIMENT']) {
+      if (process.env[name] !== undefined) {
+        env[name] = process.env[name]!;
+      }
+    }
+    return env;
+  }
+
+  /**
+   * Re-run failing tests to separate flaky tests from genuine failures
+   * Each package's failing top-level tests are retried in a single go test
+   * invocation with -count set to the number of retries, so the package is
+   * built once and a sandboxed run reuses one container for all its retries.
+   * Go runs every repetition, so attempts counts them all. Retries stop once
+   * the run is cancelled; a package whose retries were cut short keeps its
+   * original results. A retry that leaks goroutines does not count as passing.
+   * @param testFlags Flags of the original invocation, leak check overlay included
+   * @param handlers Extra subscribers for the retry invocations
+   */
+  private async retryFailedTests(
+    workspacePath: string,
+    testResults: { passed: number; failed: number; failures: TestFailure[]; testCases: TestCaseResult[] },
+    options: TestExecutionOptions,
+    testFlags: string[],
+    signal?: AbortSignal,
+    handlers: EventHandler[] = []
+  ): Promise<void> {
+    const maxRetries = options.retries || 0;
+    const failingTests = new Map<string, Set<string>>();
+
+    for (const testCase of testResults.testCases) {
+      const failing = testCase.status === 'failed' || testCase.status === 'error';
+      if (!failing || testCase.name.startsWith('[')) {
+        continue;
+      }
+      const tests = failingTests.get(testCase.package) || new Set<string>();
+      tests.add(testCase.name.split('/')[0]);
+      failingTests.set(testCase.package, tests);
+    }
+
+    for (const [pkg, tests] of failingTests) {
+      if (signal?.aborted) {
+        logger.warn('Run cancelled; skipping remaining retries');
+        break;
+      }
+
+      logger.info(`Retrying ${tests.size} failing tests of ${pkg} up to ${maxRetries} times`);
+      currentRunLog().debug('Retrying tests', { package: pkg, tests: [...tests], retries: maxRetries });
+      const command = this.executorFor(options).command({
+        packages: [pkg || './...'],
+        // The run's own flags, so a failure -race or the leak check caught is retried under it too
+        flags: [...testFlags.filter(flag => !flag.startsWith('-count=')), `-count=${maxRetries}`],
+        run: `^(${[...tests].map(t => this.escapeRegExp(t)).join('|')})$`,
+      });
+      const retryResult = await this.executeGoTest(workspacePath, command, options, signal, handlers);
+      if (retryResult.cancelled) {
+        break;
+      }
+      const verdicts = this.retryVerdicts(retryResult.stdout);
+      // A leak is reported once the package's tests finish, so it cannot be pinned on one of them
+      const leaked = this.attachGoroutineLeaks([], retryResult.stdout).length > 0;
+
+      for (const test of tests) {
+        const retries = verdicts.get(test) || [];
+        const attempts = 1 + retries.length;
+        let passedOnRetry = retries.includes('pass');
+        const related = testResults.testCases.filter(
+          c => c.package === pkg && (c.name === test || c.name.startsWith(`${test}/`))
+        );
+
+        // A data race is a real bug even when a retry happens not to hit it
+        const raced = related.some(c => c.data_races && c.data_races.length > 0);
+        if (passedOnRetry && raced) {
+          logger.warn(`Test ${pkg} ${test} passed on retry but raced; keeping it failed`);
+          passedOnRetry = false;
+        }
+        if (passedOnRetry && leaked) {
+          logger.warn(`Test ${pkg} ${test} passed on retry but the package leaked goroutines; keeping it failed`);
+          passedOnRetry = false;
+        }
+
+        for (const testCase of related) {
+          testCase.attempts = attempts;
+          if (passedOnRetry && (testCase.status === 'failed' || testCase.status === 'error')) {
+            testCase.status = 'passed';
+            testCase.flaky = true;
+            testResults.failed--;
+            testResults.passed++;
+          }
+        }
+
+        if (passedOnRetry) {
+          logger.warn(`Test ${pkg} ${test} is flaky: passed ${retries.filter(v => v === 'pass').length} of ${attempts} attempts`);
+          const flakyNames = new Set(related.map(c => c.name));
+          testResults.failures = testResults.failures.filter(f => !flakyNames.has(f.test_name));
+        }
+      }
+    }
+  }
+
+  /**
+   * Pass/fail verdicts of each top-level test, one per -count repetition, in order
+   */
+  private retryVerdicts(stdout: string): Map<string, string[]> {
+    const verdicts = new Map<string, string[]>();
+    for (const line of stdout.split('\n')) {
+      let event: any;
+      try {
+        event = JSON.parse(line);
+      } catch {
+        continue;
+      }
+      if (!event?.Test || event.Test.includes('/') || (event.Action !== 'pass' && event.Action !== 'fail')) {
+        continue;
+      }
+      verdicts.set(event.Test, [...(verdicts.get(event.Test) || []), event.Action]);
+    }
+    return verdicts;
+  }
+
+  /**
+   * Determine overall success, optionally tolerating flaky tests
+   */
+  private isRunSuccessful(
+    exitCode: number,
+    testCases: TestCaseResult[],
+    options: TestExecutionOptions
+  ): boolean {
+    if (exitCode === 0) {
+      return true;
+    }
+
+    const hardFailures = testCases.filter(
+      c => c.status === 'failed' || c.status === 'error' || c.status === 'timed_out'
+    );
+    const hasFlaky = testCases.some(c => c.flaky);
+
+    return hardFailures.length === 0 && hasFlaky && options.fail_on_flaky === false;
+  }
+
+  /**
+   * Base seed for -shuffle, or undefined when shuffling is off
+   */
+  private resolveShuffleSeed(shuffle: TestExecutionOptions['shuffle']): number | undefined {
+    if (shuffle === undefined || shuffle === 'off') {
+      return undefined;
+    }
+    return shuffle === 'on' ? Date.now() : shuffle;
+  }
+
+  /**
+   * Per-package seed derived from the base seed and import path (FNV-1a)
+   */
+  private packageSeed(baseSeed: number, pkg: string): number {
+    let hash = 0x811c9dc5;
+    for (const ch of `${baseSeed}/${pkg}`) {
+      hash = Math.imul(hash ^ ch.charCodeAt(0), 0x01000193) >>> 0;
+    }
+    return hash;
+  }
+
+  /**
+   * Collect the seed each package reported (go test prints "-test.shuffle N"
+   * before its first test) and log how to replay the packages that failed
+   */
+  private recordShuffleSeeds(baseSeed: number, stdout: string, testCases: TestCaseResult[]): ShuffleInfo {
+    const seeds: Record<string, number> = {};
+
+    for (const line of stdout.split('\n')) {
+      let event: any;
+      try {
+        event = JSON.parse(line);
+      } catch {
+        continue;
+      }
+      const match = typeof event?.Output === 'string' && event.Output.match(/^-test\.shuffle (\d+)/);
+      if (match && event.Package) {
+        seeds[event.Package] = parseInt(match[1], 10);
+      }
+    }
+
+    const failing = new Set(
+      testCases.filter(c => c.status === 'failed' || c.status === 'error' || c.status === 'timed_out').map(c => c.package)
+    );
+    for (const pkg of Array.from(failing).sort()) {
+      if (seeds[pkg] !== undefined) {
+        logger.warn(`${pkg} failed with shuffled test order; replay with: go test -shuffle=${seeds[pkg]} ${pkg}`);
+      }
+    }
+
+    return { base_seed: baseSeed, seeds };
+  }
+
+  private escapeRegExp(value: string): string {
+    return value.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+  }
+
+  /**
+   * -run pattern for a shard's tests, keeping any subtest levels of the run's own pattern
+   */
+  private shardRunPattern(tests: string[], run?: string): string {
+    const subtests = run !== undefined ? this.splitRunPattern(run).slice(1) : [];
+    return [`^(${tests.map(t => this.escapeRegExp(t)).join('|')})$`, ...subtests].join('/');
+  }
+
+  /**
+   * Split a -run pattern into one regexp per test level, as go test does
+   * A slash inside brackets or parentheses, or escaped, does not split.
+   */
+  private splitRunPattern(run: string): string[] {
+    const levels: string[] = [];
+    let depth = 0;
+    let start = 0;
+    for (let i = 0; i < run.length; i++) {
+      const ch = run[i];
+      if (ch === '\\') {
+        i++;
+      } else if (ch === '[' || ch === '(') {
+        depth++;
+      } else if ((ch === ']' || ch === ')') && depth > 0) {
+        depth--;
+      } else if (ch === '/' && depth === 0) {
+        levels.push(run.slice(start, i));
+        start = i + 1;
+      }
+    }
+    levels.push(run.slice(start));
+    return levels;
+  }
+
+  /**
+   * Run each package in its own go test process (or container) on a bounded pool
+   * With shards set, large packages are further split into -run shards.
+   * Output is combined in package order so results are stable regardless of
+   * completion order. A package whose process fails to start is reported as a
+   * failed package without affecting the others.
+   */
+  private async executePackagesInParallel(
+    workspacePath: string,
+    packages: string[],
+    testFlags: string[],
+    coverageProfilePath: string,
+    options: TestExecutionOptions,
+    signal?: AbortSignal,
+    handlers: EventHandler[] = [],
+    shuffleSeed?: number
+  ): Promise<GoTestProcessResult> {
+    const executor = this.executorFor(options);
+    const importPaths = (await executor.resolvePackages(workspacePath, packages, options.tags)).sort();
+    const concurrency = options.parallel && options.parallel > 0 ? options.parallel : os.cpus().length;
+    const profilePath = (index: number) => coverageProfilePath.replace(/\.out$/, `-${index}.out`);
+
+    // A checkpointed run replays its stored schedule, if it has one, and checkpoints each process as it exits
+    const checkpointer = this.checkpointers.get(options);
+    const runs = checkpointer
+      ? await checkpointer.schedule(() => this.schedulePackageRuns(workspacePath, importPaths, options))
+      : await this.schedulePackageRuns(workspacePath, importPaths, options);
+
+    logger.info(`Running ${importPaths.length} packages as ${runs.length} go test processes, up to ${concurrency} in parallel`);
+
+    const runLog = currentRunLog();
+    for (const run of runs) {
+      runLog.debug('Package scheduled', { package: run.pkg, tests: run.tests?.length });
+    }
+
+    const outcomes = await runWorkerPool(runs, concurrency, (run, index) => {
+      // Container events from this package's sandbox calls carry its import path
+      const log = runLog.with({ package: run.pkg });
+      return log.run(async () => {
+        // Each package gets its own seed, stable for a given base seed, so parallel runs replay exactly
+        const command = executor.command({
+          packages: [run.pkg],
+          flags: shuffleSeed !== undefined ? [...testFlags, `-shuffle=${this.packageSeed(shuffleSeed, run.pkg)}`] : testFlags,
+          coverProfile: profilePath(index),
+          run: run.tests ? this.shardRunPattern(run.tests, options.run) : options.run,
+        });
+        log.debug('Package started');
+        const started = Date.now();
+        const result = await this.executeGoTest(workspacePath, command, options, signal, handlers);
+        log.debug('Package completed', {
+          exit_code: result.exitCode,
+          duration_ms: Date.now() - started,
+          cancelled: result.cancelled || undefined,
+        });
+        if (checkpointer && !result.cancelled) {
+          const parsed = this.parseGoTestOutput(result.stdout, result.stderr, result.buildOutput);
+          if (result.timedOutTests && result.timedOutTests.length > 0) {
+            this.applyTimeouts(parsed, result, options.test_timeout_seconds || 0);
+          }
+          await checkpointer.complete(run, parsed.testCases, parsed.buildFailures, profilePath(index));
+        }
+        return result;
+      });
+    }, signal);
+
+    const combined: GoTestProcessResult = {
+      exitCode: 0,
+      stdout: '',
+      stderr: '',
+      buildOutput: new Map(),
+      timedOutTests: [],
+      goroutineDump: '',
+      oomKilled: false,
+      artifacts: [],
+      cancelled: false,
+      packageOutput: new Map(),
+    };
+    const appendOutput = (pkg: string, text: string) =>
+      combined.packageOutput!.set(pkg, (combined.packageOutput!.get(pkg) || '') + text);
+
+    for (const outcome of outcomes) {
+      if (!outcome.ok && outcome.error instanceof CancelledError) {
+        // Never started; a package with no verdict is reported as cancelled
+        combined.cancelled = true;
+        runLog.debug('Package not started', { package: outcome.item.pkg });
+        combined.stdout += JSON.stringify({ Action: 'start', Package: outcome.item.pkg }) + '\n';
+        continue;
+      }
+
+      if (!outcome.ok) {
+        logger.error(`go test for ${outcome.item.pkg} failed to run: ${outcome.error.message}`);
+        appendOutput(outcome.item.pkg, `${(outcome.error as any).rawOutput || ''}${outcome.error.message}\n`);
+        combined.exitCode = Math.max(combined.exitCode, 1);
+        combined.stdout += JSON.stringify({ Action: 'output', Package: outcome.item.pkg, Output: `${outcome.error.message}\n` }) + '\n';
+        combined.stdout += JSON.stringify({ Action: 'fail', Package: outcome.item.pkg }) + '\n';
+        continue;
+      }
+
+      const result = outcome.value;
+      appendOutput(outcome.item.pkg, result.rawOutput || '');
+      combined.exitCode = Math.max(combined.exitCode, result.exitCode);
+      combined.stdout += result.stdout;
+      combined.stderr += result.stderr;
+      for (const [pkg, output] of result.buildOutput || []) {
+        combined.buildOutput!.set(pkg, (combined.buildOutput!.get(pkg) || '') + output);
+      }
+      combined.timedOutTests!.push(...(result.timedOutTests || []));
+      combined.goroutineDump += result.goroutineDump || '';
+      combined.oomKilled = combined.oomKilled || result.oomKilled;
+      combined.artifacts!.push(...(result.artifacts || []));
+      combined.cancelled = combined.cancelled || result.cancelled;
+    }
+
+    await this.mergePackageProfiles(runs.map((_, index) => profilePath(index)), coverageProfilePath);
+    return combined;
+  }
+
+  /**
+   * The go test processes a pooled run starts: one per package or shard,
+   * narrowed by the label selection
+   * @param listTests Top-level tests of a package, for sharding (default: go test -list)
+   */
+  private async schedulePackageRuns(
+    workspacePath: string,
+    importPaths: string[],
+    options: TestExecutionOptions,
+    listTests: (pkg: string) => Promise<string[]> = pkg => this.listTopLevelTests(workspacePath, pkg, options)
+  ): Promise<PackageRun[]> {
+    let runs: PackageRun[] = options.shards !== undefined && options.shards > 1
+      ? await this.shardPackages(importPaths, options.shards, options, listTests)
+      : importPaths.map(pkg => ({ pkg }));
+    if (this.hasLabelSelection(options)) {
+      runs = await this.selectByLabel(workspacePath, runs, options);
+    }
+    return runs;
+  }
+
+  /**
+   * Split packages into -run shards balanced by historical test durations
+   * Only tests the run's own -run pattern selects at the top level are
+   * sharded. A package that cannot be listed, or has fewer than two such
+   * tests, runs whole.
+   */
+  private async shardPackages(
+    importPaths: string[],
+    shards: number,
+    options: TestExecutionOptions,
+    listTests: (pkg: string) => Promise<string[]>
+  ): Promise<PackageRun[]> {
+    const timings = options.timings_path ? await testSharder.readTimings(options.timings_path) : {};
+    const topLevel = options.run !== undefined ? new RegExp(this.splitRunPattern(options.run)[0]) : undefined;
+    const runs: PackageRun[] = [];
+
+    for (const pkg of importPaths) {
+      const tests = !options.shard_packages || options.shard_packages.includes(pkg)
+        ? (await listTests(pkg)).filter(t => !topLevel || topLevel.test(t))
+        : [];
+      if (tests.length < 2) {
+        runs.push({ pkg });
+        continue;
+      }
+
+      const balanced = testSharder.balance(tests.map(name => ({ name, duration_ms: timings[pkg]?.[name] })), shards);
+      logger.info(`Sharding ${pkg}: ${tests.length} tests across ${balanced.length} shards`);
+      runs.push(...balanced.map(shard => ({ pkg, tests: shard })));
+    }
+
+    return runs;
+  }
+
+  /**
+   * Verbose JSON output with coverage, plus -race, -count and build tags when requested
+   */
+  private testFlags(options: TestExecutionOptions): string[] {
+    return [
+      '-v', '-json', '-cover',
+      ...(options.race ? ['-race'] : []),
+      ...(options.count !== undefined ? [`-count=${options.count}`] : []),
+      ...buildTagFlags(options.tags),
+    ];
+  }
+
+  /**
+   * Result cache for the run, unless cached passes cannot stand in for it
+   * A cached pass was not run in this order, or may cover only some of the
+   * package's tests, so shuffled and filtered runs always execute.
+   */
+  private resultCache(options: TestExecutionOptions): TestResultCache | undefined {
+    const shuffled = options.shuffle !== undefined && options.shuffle !== 'off';
+    const filtered = options.run !== undefined || this.hasLabelSelection(options);
+    const prebuilt = options.test_binaries_dir !== undefined;
+    // A checkpoint records only packages that ran, so a resumed run would lose the cached ones
+    const checkpointed = options.checkpoint || options.resume_run_id !== undefined;
+
+    return options.cache_dir && !options.no_cache && !prebuilt && !shuffled && !filtered && !checkpointed
+      ? new TestResultCache(options.cache_dir)
+      : undefined;
+  }
+
+  private hasLabelSelection(options: TestExecutionOptions): boolean {
+    return (options.labels?.length || 0) > 0 || (options.exclude_labels?.length || 0) > 0;
+  }
+
+  /**
+   * Narrow runs to the tests a label selection keeps
+   * A run left with no tests is dropped. A package where only exclusions
+   * apply and none match runs whole, so tests the scan cannot see still run.
+   * With run set, the selected names are further filtered by it (top-level names only).
+   */
+  private async selectByLabel(workspacePath: string, runs: PackageRun[], options: TestExecutionOptions): Promise<PackageRun[]> {
+    const listed = new Map((await goPackageSelector.listPackages(workspacePath, options.tags)).map(p => [p.ImportPath, p]));
+    const nameFilter = options.run !== undefined ? new RegExp(options.run) : undefined;
+    const selections = new Map<string, { tests: string[]; all: boolean }>();
+
+    const selectionFor = async (pkg: string) => {
+      let selection = selections.get(pkg);
+      if (!selection) {
+        const info = listed.get(pkg);
+        const scanned = info ? await this.scanTests(info) : {};
+        const tests = testLabelScanner.select(scanned, options).filter(t => !nameFilter || nameFilter.test(t));
+        const all = !options.labels?.length && !nameFilter && tests.length === Object.keys(scanned).length;
+        selection = { tests, all };
+        selections.set(pkg, selection);
+      }
+      return selection;
+    };
 
-  async execute(
-    workspacePath: string,
-    codeFilePath: string,
-    testFilePath: string,
-    options: TestExecutionOptions
-  ): Promise<TestExecutionResult> {
-    const startTime = Date.now();
+    const selected: PackageRun[] = [];
+    for (const run of runs) {
+      const { tests, all } = await selectionFor(run.pkg);
+      if (all) {
+        selected.push(run);
+        continue;
+      }
+      const kept = run.tests ? run.tests.filter(t => tests.includes(t)) : tests;
+      if (kept.length > 0) {
+        selected.push({ pkg: run.pkg, tests: kept });
+      }
+    }
 
-    logger.info(`Executing Go tests from ${testFilePath}`);
+    const total = Array.from(selections.values()).reduce((sum, s) => sum + s.tests.length, 0);
+    logger.info(`Label selection kept ${total} tests in ${new Set(selected.map(r => r.pkg)).size} of ${selections.size} packages`);
+    return selected;
+  }
+
+  /**
+   * Report the tests a panic kept from starting as not run
+   * go test emits nothing for tests after the one that panicked, so they are
+   * found in the package sources. Any -run or label selection applies, so
+   * deliberately skipped tests are not reported.
+   */
+  private async markUnrunTests(workspacePath: string, testCases: TestCaseResult[], options: TestExecutionOptions): Promise<void> {
+    const panicked = new Map<string, string>();
+    for (const testCase of testCases) {
+      if (testCase.panic && !testCase.name.startsWith('[') && !panicked.has(testCase.package)) {
+        panicked.set(testCase.package, testCase.name);
+      }
+    }
+    if (panicked.size === 0) {
+      return;
+    }
 
     try {
-      // Prepare paths
-      const reportsDir = path.join(workspacePath, 'reports');
-      const coverageProfilePath = path.join(reportsDir, 'coverage.out');
+      const listed = new Map((await goPackageSelector.listPackages(workspacePath, options.tags)).map(p => [p.ImportPath, p]));
+      const nameFilter = options.run !== undefined ? new RegExp(options.run) : undefined;
 
-      // Build go test command
-      // Go requires tests to be in the same package, so we run from workspace
-      const args = [
-        'test',
-        '-v', // Verbose output
-        '-json', // JSON output for parsing
-        '-cover', // Enable coverage
-        '-coverprofile=' + coverageProfilePath,
-        './...', // Test all packages in workspace
-      ];
+      for (const [pkg, test] of panicked) {
+        const info = listed.get(pkg);
+        if (!info) {
+          continue;
+        }
 
-      // Execute go test
-      const result = await this.executeGoTest(workspacePath, args, options);
+        const seen = new Set(testCases.filter(c => c.package === pkg).map(c => c.name.split('/')[0]));
+        const unrun = testLabelScanner.select(await this.scanTests(info), options)
+          .filter(name => !seen.has(name) && (!nameFilter || nameFilter.test(name)));
+        for (const name of unrun) {
+          testCases.push({ package: pkg, name, status: 'not_run', duration_ms: 0, output: '', failure_message: `Not run: ${test} panicked` });
+        }
+        if (unrun.length > 0) {
+          logger.warn(`${test} panicked in ${pkg}; ${unrun.length} tests after it did not run`);
+        }
+      }
+    } catch (error: any) {
+      logger.warn(`Could not list tests left unrun by a panic: ${error.message}`);
+    }
+  }
 
-      // Parse coverage report
-      let coverageReport;
+  /**
+   * Labels of a package's tests, from the test files go list kept for the build tags
+   */
+  private scanTests(pkg: GoPackageInfo): Promise<Record<string, string[]>> {
+    return testLabelScanner.scan(pkg.Dir, [...(pkg.TestGoFiles || []), ...(pkg.XTestGoFiles || [])]);
+  }
+
+  /**
+   * Top-level tests, examples, and fuzz targets of a package, via go test -list
+   */
+  private async listTopLevelTests(workspacePath: string, pkg: string, options: TestExecutionOptions): Promise<string[]> {
+    const args = ['test', '-list', '.', ...buildTagFlags(options.tags), pkg];
+    try {
+      const stdout = options.sandbox
+        ? (await sandboxService.executeInSandbox(
+          this.getSandboxConfig(workspacePath, options), ['go', ...args], workspacePath, workspacePath
+        )).stdout
+        : (await execFileAsync('go', args, { cwd: workspacePath })).stdout;
+      return stdout.split('\n').map(l => l.trim()).filter(l => /^(Test|Example|Fuzz)\w*$/.test(l));
+    } catch (error: any) {
+      logger.warn(`Could not list tests in ${pkg}, running it unsharded: ${error.message}`);
+      return [];
+    }
+  }
+
+  /**
+   * Prebuilt test binaries when test_binaries_dir is set, otherwise go test
+   */
+  private executorFor(options: TestExecutionOptions): GoTestExecutor {
+    return options.test_binaries_dir ? new PrebuiltBinaryExecutor(options.test_binaries_dir) : goToolchainExecutor;
+  }
+
+
+  /**
+   * Packages whose result go test replayed from its build cache
+   * go test marks them in the package summary line: "ok  <pkg>  (cached)".
+   */
+  private goCachedPackages(stdout: string): string[] {
+    const cached = new Set<string>();
+    for (const line of stdout.split('\n')) {
+      let event: any;
       try {
-        await fs.access(coverageProfilePath);
-        coverageReport = await coverageParser.parseGoCoverageProfile(coverageProfilePath);
+        event = JSON.parse(line);
       } catch {
-        // Try parsing from stdout if profile not available
-        logger.warn('Coverage profile not found, parsing from stdout');
-        coverageReport = await coverageParser.parseGoCoverage(result.stdout);
+        continue;
+      }
+      if (event?.Action === 'output' && event.Package && !event.Test && /^ok\s+\S+\s+\(cached\)/.test(event.Output || '')) {
+        cached.add(event.Package);
       }
+    }
+    return Array.from(cached).sort();
+  }
 
-      // Parse test results from JSON output
-      const testResults = this.parseGoTestOutput(result.stdout);
+  /**
+   * Swap the replayed packages' output for that of their fresh run
+   */
+  private replacePackageResults(result: GoTestProcessResult, fresh: GoTestProcessResult, packages: string[]): GoTestProcessResult {
+    const replaced = new Set(packages);
+    const keep = (output: string) => output.split('\n').filter(line => {
+      try {
+        return !replaced.has(JSON.parse(line)?.Package);
+      } catch {
+        return true;
+      }
+    }).join('\n');
+    const concat = <T>(a?: T[], b?: T[]) => (a || b ? [...(a || []), ...(b || [])] : undefined);
+    const packageOutput = result.packageOutput || fresh.packageOutput
+      ? new Map([...(result.packageOutput || []), ...(fresh.packageOutput || [])])
+      : undefined;
+    const buildOutput = result.buildOutput || fresh.buildOutput
+      ? new Map([...(result.buildOutput || []), ...(fresh.buildOutput || [])])
+      : undefined;
 
-      return {
-        success: result.exitCode === 0,
-        passed_tests: testResults.passed,
-        failed_tests: testResults.failed,
-        total_tests: testResults.total,
-        coverage_percentage: coverageReport.line_coverage,
-        duration_ms: Date.now() - startTime,
-        failures: testResults.failures,
-        stdout: result.stdout,
-        stderr: result.stderr,
-      };
+    return {
+      ...result,
+      exitCode: Math.max(result.exitCode, fresh.exitCode),
+      stdout: keep(result.stdout).replace(/\n*$/, '\n') + fresh.stdout,
+      stderr: result.stderr + fresh.stderr,
+      rawOutput: result.rawOutput !== undefined ? keep(result.rawOutput).replace(/\n*$/, '\n') + (fresh.rawOutput || '') : undefined,
+      packageOutput,
+      buildOutput,
+      timedOutTests: concat(result.timedOutTests, fresh.timedOutTests),
+      artifacts: concat(result.artifacts, fresh.artifacts),
+      oomKilled: result.oomKilled || fresh.oomKilled,
+      cancelled: result.cancelled || fresh.cancelled,
+    };
+  }
 
-    } catch (error: any) {
-      logger.error(`Go test execution failed: ${error.message}`);
+  /**
+   * Replace the replayed packages' coverage with that of their fresh run
+   * Their blocks are dropped even when the fresh run produced no profile, so
+   * coverage is never reported from a cached result.
+   */
+  private async replacePackageCoverage(coverageProfilePath: string, freshProfilePath: string, packages: string[]): Promise<void> {
+    const replaced = new Set(packages);
+    const profiles: GoCoverageProfile[] = [];
 
-      return {
-        success: false,
-        passed_tests: 0,
-        failed_tests: 0,
-        total_tests: 0,
-        coverage_percentage: 0,
-        duration_ms: Date.now() - startTime,
-        failures: [{
-          test_name: 'go_test_execution',
-          error_message: error.message,
-          stack_trace: error.stack || '',
-          location: 'unknown',
-        }],
-        stdout: error.stdout || '',
-        stderr: error.stderr || error.message,
-      };
+    for (const profilePath of [coverageProfilePath, freshProfilePath]) {
+      let profile: GoCoverageProfile;
+      try {
+        profile = await coverageProfileService.readProfile(profilePath);
+      } catch {
+        logger.debug(`No coverage profile at ${profilePath}`);
+        continue;
+      }
+      if (profilePath === coverageProfilePath) {
+        // Profile file names are <import path>/<file>.go
+        const files = Object.entries(profile.files).filter(([file]) => !replaced.has(path.posix.dirname(file)));
+        profile = { mode: profile.mode, files: Object.fromEntries(files) };
+      }
+      profiles.push(profile);
+    }
+
+    if (profiles.length > 0) {
+      await coverageProfileService.writeProfile(coverageProfilePath, coverageProfileService.mergeProfiles(...profiles));
+    }
+  }
+
+  /**
+   * Merge per-package coverage profiles; packages that failed to build have none
+   */
+  private async mergePackageProfiles(profilePaths: string[], coverageProfilePath: string): Promise<void> {
+    const profiles: GoCoverageProfile[] = [];
+
+    for (const profilePath of profilePaths) {
+      try {
+        profiles.push(await coverageProfileService.readProfile(profilePath));
+      } catch {
+        logger.debug(`No coverage profile at ${profilePath}`);
+      }
+    }
+
+    if (profiles.length > 0) {
+      await coverageProfileService.writeProfile(coverageProfilePath, coverageProfileService.mergeProfiles(...profiles));
     }
   }
 
   /**
-   * Execute go test command with timeout
+   * Execute go test, streaming -json events to subscribers as they arrive
+   * With test_timeout_seconds set, a watchdog sends SIGQUIT to the process
+   * group when a test hangs so the Go runtime prints every goroutine's stack.
+   * Aborting the signal kills the process (or container) and resolves with
+   * the output so far, marked cancelled.
+   * @param command Process to run; its decoder, if any, turns stdout into -json events
+   * @param handlers Extra subscribers for this invocation only
    */
   private async executeGoTest(
     workspacePath: string,
-    args: string[],
-    options: TestExecutionOptions
-  ): Promise<{ exitCode: number; stdout: string; stderr: string }> {
+    command: GoTestCommand,
+    options: TestExecutionOptions,
+    signal?: AbortSignal,
+    handlers: EventHandler[] = []
+  ): Promise<GoTestProcessResult> {
     const timeoutMs = (options.timeout_seconds || 300) * 1000;
+    const notStarted: GoTestProcessResult = { exitCode: 1, stdout: '', stderr: '', cancelled: true };
+    if (signal?.aborted) {
+      return notStarted;
+    }
+
+    // Injected variables reach the tests only, never the helper invocations that share the sandbox config
+    const testEnv = await this.testEnvironment(options);
+    const serviceEnv = this.runningServices.get(options)?.env || {};
 
+    // In sandbox mode the workspace is mounted at the same path, so host paths stay valid
+    const baseConfig = options.sandbox ? this.getSandboxConfig(workspacePath, options) : undefined;
+    const sandboxConfig = baseConfig
+      ? { ...baseConfig, env: { ...baseConfig.env, ...serviceEnv, ...testEnv.env }, secret_env: testEnv.secrets }
+      : undefined;
+    let container: { child: ChildProcess; containerId: string } | undefined;
     try {
-      const { stdout, stderr } = await execFileAsync('go', args, {
-        cwd: workspacePath,
-        timeout: timeoutMs,
-        maxBuffer: 10 * 1024 * 1024, // 10MB buffer
+      container = sandboxConfig
+        ? await sandboxService.spawnInSandbox(sandboxConfig, command.argv, workspacePath, workspacePath, signal)
+        : undefined;
+    } catch (error) {
+      if (error instanceof CancelledError) {
+        return notStarted; // The sandbox already removed the container
+      }
+      throw error;
+    }
+
+    return new Promise((resolve, reject) => {
+      const child = container ? container.child : spawn(command.argv[0], command.argv.slice(1), {
+        cwd: command.cwd || workspacePath,
+        detached: true, // Own process group so signals reach the test binary
         env: {
           ...process.env,
           GOPATH: process.env.GOPATH || path.join(process.env.HOME || '~', 'go'),
+          ...serviceEnv,
+          ...testEnv.env,
+          ...testEnv.secrets,
         },
       });
 
-      return {
-        exitCode: 0,
-        stdout,
-        stderr,
+      const signalGroup = (signal: NodeJS.Signals) => {
+        if (container) {
+          void sandboxService.signalContainer(container.containerId, signal);
+          return;
+        }
+        try {
+          process.kill(-child.pid!, signal);
+        } catch {
+          child.kill(signal);
+        }
       };
 
-    } catch (error: any) {
-      // go test returns non-zero exit code on test failures, which is expected
-      if (error.code === 1) {
-        // Exit code 1: tests failed
-        return {
-          exitCode: 1,
-          stdout: error.stdout || '',
-          stderr: error.stderr || '',
+      const buildOutput = new BuildOutputCollector();
+      const watchdog = options.test_timeout_seconds
+        ? new TestTimeoutWatchdog(options.test_timeout_seconds, () => signalGroup('SIGQUIT'))
+        : undefined;
+      const stream = new GoTestEventStream([
+        buildOutput,
+        ...(watchdog ? [watchdog] : []),
+        ...this.eventHandlers,
+        ...handlers,
+      ]);
+
+      let killed = false;
+      let cancelled = false;
+      let stdout = '';
+      let stderr = '';
+      let rawOutput = '';
+
+      child.stdout!.on('data', (chunk: Buffer) => {
+        const text = command.decoder ? command.decoder.write(chunk.toString()) : chunk.toString();
+        stdout += text;
+        rawOutput += text;
+        stream.writeStdout(text);
+      });
+
+      child.stderr!.on('data', (chunk: Buffer) => {
+        const text = chunk.toString();
+        stderr += text;
+        rawOutput += text;
+        stream.writeStderr(text);
+      });
+
+      const overallTimer = setTimeout(() => {
+        killed = true;
+        signalGroup('SIGKILL');
+      }, timeoutMs);
+
+      const onAbort = () => {
+        cancelled = true;
+        signalGroup('SIGKILL');
+      };
+      signal?.addEventListener('abort', onAbort, { once: true });
+      if (signal?.aborted) {
+        onAbort(); // Aborted while the container was starting
+      }
+
+      const finish = () => {
+        watchdog?.stop();
+        clearTimeout(overallTimer);
+        signal?.removeEventListener('abort', onAbort);
+      };
+
+      child.on('error', (error) => {
+        finish();
+        if (container) {
+          void sandboxService.finishContainer(container.containerId, 127, sandboxConfig);
+        }
+        reject(error);
+      });
+
+      child.on('close', async (code) => {
+        finish();
+        const exitCode = code ?? 1;
+        if (command.decoder) {
+          const tail = command.decoder.end(exitCode);
+          stdout += tail;
+          rawOutput += tail;
+          stream.writeStdout(tail);
+        }
+        stream.end();
+
+        const finished = container
+          ? await sandboxService.finishContainer(container.containerId, exitCode, sandboxConfig)
+          : undefined;
+        const oomKilled = finished?.oomKilled || false;
+        const attach = (error: any) => {
+          error.stdout = stdout;
+          error.stderr = stderr;
+          error.rawOutput = rawOutput;
+          return error;
         };
+
+        if (cancelled) {
+          resolve({
+            exitCode,
+            stdout,
+            stderr,
+            buildOutput: buildOutput.byPackage,
+            cancelled: true,
+            artifacts: finished?.artifacts ? [finished.artifacts] : undefined,
+            rawOutput,
+          });
+          return;
+        }
+
+        if (killed) {
+          const error: any = new Error(`go test exceeded ${options.timeout_seconds || 300}s timeout`);
+          error.code = 'ETIMEDOUT';
+          error.killed = true;
+          reject(attach(error));
+          return;
+        }
+
+        // go test exits 1 on test and build failures, which is expected;
+        // anything else (bad flags, missing toolchain) is an execution error
+        if (exitCode > 1 && !watchdog?.triggered && !oomKilled) {
+          const error: any = new Error(`${command.argv.join(' ')} exited with code ${exitCode}: ${stderr.trim()}`);
+          error.code = exitCode;
+          reject(attach(error));
+          return;
+        }
+
+        resolve({
+          exitCode,
+          stdout,
+          stderr,
+          buildOutput: buildOutput.byPackage,
+          timedOutTests: watchdog?.timedOutTests,
+          goroutineDump: watchdog?.goroutineDump,
+          oomKilled,
+          artifacts: finished?.artifacts ? [finished.artifacts] : undefined,
+          rawOutput,
+        });
+      });
+    });
+  }
+
+  /**
+   * Attribute the raw output of one go test process covering several packages
+   * JSON events go to their package. Lines outside the -json stream (build
+   * errors, toolchain messages) cannot be attributed, so every package gets them.
+   */
+  private splitOutputByPackage(rawOutput: string): Map<string, string> {
+    const lines = rawOutput.split('\n').filter((line, i, all) => line !== '' || i < all.length - 1);
+    const owners = lines.map(line => {
+      try {
+        const event = JSON.parse(line);
+        return typeof event.Package === 'string' ? event.Package : undefined;
+      } catch {
+        return undefined;
       }
+    });
 
-      // Other errors (timeout, command not found, etc.)
-      throw error;
+    const byPackage = new Map<string, string>();
+    for (const pkg of owners) {
+      if (pkg && !byPackage.has(pkg)) {
+        byPackage.set(pkg, '');
+      }
+    }
+    lines.forEach((line, i) => {
+      for (const pkg of owners[i] ? [owners[i]] : byPackage.keys()) {
+        byPackage.set(pkg, byPackage.get(pkg) + line + '\n');
+      }
+    });
+
+    return byPackage;
+  }
+
+  /**
+   * Keep each package's raw output as a log under the artifacts directory
+   * keep_logs picks the packages (default: failed, including flaky passes);
+   * their test results reference the log.
+   * @returns Log path per package, or undefined when none was kept
+   */
+  private async writePackageLogs(
+    workspacePath: string,
+    output: Map<string, string>,
+    testCases: TestCaseResult[],
+    options: TestExecutionOptions
+  ): Promise<Record<string, string> | undefined> {
+    const keep = options.keep_logs || 'failed';
+    if (keep === 'none') {
+      return undefined;
+    }
+
+    const logsDir = path.join(options.artifacts_dir || path.join(workspacePath, 'reports', 'artifacts'), 'logs');
+    const logs: Record<string, string> = {};
+    for (const [pkg, text] of output) {
+      const cases = testCases.filter(c => c.package === pkg);
+      const failed = cases.some(c => c.status === 'failed' || c.status === 'error' || c.status === 'timed_out' || c.flaky);
+      if (keep === 'failed' && !failed) {
+        continue;
+      }
+
+      const logPath = path.join(logsDir, `${pkg}.log`);
+      try {
+        await fs.mkdir(path.dirname(logPath), { recursive: true });
+        await fs.writeFile(logPath, text, 'utf-8');
+      } catch (error: any) {
+        logger.warn(`Failed to write the go test log for ${pkg}: ${error.message}`);
+        continue;
+      }
+      logs[pkg] = logPath;
+      cases.forEach(c => { c.log_path = logPath; });
+    }
+
+    return Object.keys(logs).length > 0 ? logs : undefined;
+  }
+
+  /**
+   * Mark watchdog-killed tests as timed out and attach the goroutine dump
+   */
+  private applyTimeouts(
+    testResults: { passed: number; failed: number; failures: TestFailure[]; testCases: TestCaseResult[] },
+    result: GoTestProcessResult,
+    timeoutSeconds: number
+  ): void {
+    const message = `Test exceeded ${timeoutSeconds}s timeout`;
+
+    for (const { pkg, test } of result.timedOutTests || []) {
+      let testCase = testResults.testCases.find(c => c.package === pkg && c.name === test);
+
+      if (!testCase) {
+        testCase = { package: pkg, name: test, status: 'timed_out', duration_ms: timeoutSeconds * 1000, output: '' };
+        testResults.testCases.push(testCase);
+        testResults.failed++;
+      } else if (testCase.status === 'passed') {
+        testResults.passed--;
+        testResults.failed++;
+      }
+
+      testCase.status = 'timed_out';
+      testCase.failure_message = message;
+      testCase.goroutine_dump = result.goroutineDump;
+
+      testResults.failures = testResults.failures.filter(f => f.test_name !== test);
+      testResults.failures.push({
+        test_name: test,
+        error_message: message,
+        stack_trace: result.goroutineDump || '',
+        location: this.extractLocation(testCase.output),
+      });
     }
   }
 
@@ -144,72 +2811,192 @@ export class GoTestRunner implements TestRunner {
    * Format: One JSON object per line
    * {"Time":"2024-01-01T12:00:00Z","Action":"run","Package":"example","Test":"TestAdd"}
    * {"Time":"2024-01-01T12:00:00Z","Action":"pass","Package":"example","Test":"TestAdd","Elapsed":0.01}
+   * For a cancelled run, tests and packages that never reached a verdict are
+   * reported as cancelled instead of being dropped.
    */
-  private parseGoTestOutput(stdout: string): {
+  private parseGoTestOutput(stdout: string, stderr: string = '', buildOutput?: Map<string, string>, cancelled: boolean = false): {
     passed: number;
     failed: number;
     total: number;
     failures: TestFailure[];
+    testCases: TestCaseResult[];
+    buildFailures: BuildFailure[];
   } {
     const failures: TestFailure[] = [];
     let passed = 0;
     let failed = 0;
-    const testResults = new Map<string, { action: string; output?: string }>();
+    const testResults = new Map<string, { pkg: string; test: string; action: string; output: string; elapsed: number }>();
+    const packageResults = new Map<string, { action: string; output: string; failedBuild: boolean; causedBy?: string }>();
 
     // Parse JSON lines
     const lines = stdout.split('\n').filter(l => l.trim());
 
     for (const line of lines) {
+      let event: any;
       try {
-        const event = JSON.parse(line);
-
-        // Track test events
-        if (event.Test && event.Action) {
-          const testName = event.Test;
-
-          if (event.Action === 'pass') {
-            testResults.set(testName, { action: 'pass' });
-          } else if (event.Action === 'fail') {
-            testResults.set(testName, { action: 'fail', output: event.Output || '' });
-          } else if (event.Action === 'output' && event.Output) {
-            // Accumulate output for failed tests
-            const existing = testResults.get(testName);
-            if (existing) {
-              existing.output = (existing.output || '') + event.Output;
-            } else {
-              testResults.set(testName, { action: 'output', output: event.Output });
-            }
-          }
-        }
+        event = JSON.parse(line);
       } catch {
         // Skip non-JSON lines (coverage summary, etc.)
         continue;
       }
+
+      if (!event.Action) {
+        continue;
+      }
+
+      const pkg = event.Package || '';
+
+      // Package-level events (no Test) carry build failures and package verdicts
+      if (!event.Test) {
+        const pkgResult = packageResults.get(pkg) || { action: 'output', output: '', failedBuild: false };
+        if (event.Action === 'output' && event.Output) {
+          pkgResult.output += event.Output;
+        } else if (event.Action === 'pass' || event.Action === 'fail' || event.Action === 'skip') {
+          // Shards of one package each report a verdict; any failure fails the package
+          pkgResult.action = pkgResult.action === 'fail' ? 'fail' : event.Action;
+        }
+        if (event.FailedBuild) {
+          // Names the package that did not compile, which for a dependent is not this one
+          const broken = this.packageOfBuild(event.FailedBuild);
+          pkgResult.failedBuild = true;
+          pkgResult.causedBy = broken !== pkg ? broken : undefined;
+        }
+        packageResults.set(pkg, pkgResult);
+        continue;
+      }
+
+      // Track test events
+      const key = `${pkg} ${event.Test}`;
+      const existing = testResults.get(key) || { pkg, test: event.Test, action: 'run', output: '', elapsed: 0 };
+
+      if (event.Action === 'pass' || event.Action === 'fail' || event.Action === 'skip') {
+        // With -count each repetition reports a verdict; a failure in any of them fails the test
+        existing.action = existing.action === 'fail' ? 'fail' : event.Action;
+        existing.elapsed = event.Elapsed || 0;
+      } else if (event.Action === 'output' && event.Output) {
+        // Accumulate output for failed tests
+        existing.output += event.Output;
+      }
+
+      testResults.set(key, existing);
+    }
+
+    // A panic kills the test binary. The panicking test may have no verdict
+    // when a goroutine it started panicked, so unfinished tests of a failed
+    // package are checked too.
+    const panics = new Map<string, TestPanic>();
+    const panickedIn = new Map<string, string>();
+    for (const [key, result] of testResults) {
+      const unfinished = result.action === 'run' && packageResults.get(result.pkg)?.action === 'fail';
+      const panic = result.action === 'fail' || unfinished ? this.extractPanic(result.output) : undefined;
+      if (panic) {
+        panics.set(key, panic);
+        panickedIn.set(result.pkg, panickedIn.get(result.pkg) || result.test);
+      }
     }
 
     // Count results and extract failures
-    for (const [testName, result] of testResults.entries()) {
-      if (result.action === 'pass') {
+    const testCases: TestCaseResult[] = [];
+    const packagesWithFailedTests = new Set<string>();
+
+    for (const [key, result] of testResults) {
+      const output = result.output;
+      const panic = panics.get(key);
+
+      if (panic) {
+        failed++;
+        packagesWithFailedTests.add(result.pkg);
+        failures.push({
+          test_name: result.test,
+          error_message: `panic: ${panic.message}`,
+          stack_trace: panic.stack,
+          location: this.extractLocation(output),
+        });
+        testCases.push({ ...this.toTestCase(result, 'error', `panic: ${panic.message}`), panic });
+      } else if (result.action === 'pass') {
         passed++;
+        testCases.push(this.toTestCase(result, 'passed'));
+      } else if (result.action === 'skip') {
+        testCases.push(this.toTestCase(result, 'skipped', this.extractSkipReason(output)));
       } else if (result.action === 'fail') {
         failed++;
+        packagesWithFailedTests.add(result.pkg);
 
         // Extract error message from output
-        const output = result.output || '';
         const errorMatch = output.match(/Error:\s*(.*?)(?=\n|$)/);
         const errorMessage = errorMatch ? errorMatch[1] : 'Test failed';
 
         failures.push({
-          test_name: testName,
+          test_name: result.test,
           error_message: errorMessage,
           stack_trace: output,
           location: this.extractLocation(output),
         });
+
+        testCases.push(this.toTestCase(result, 'failed', errorMessage));
+      } else if (cancelled) {
+        testCases.push(this.toTestCase(result, 'cancelled', 'Run cancelled before the test finished'));
+      } else if (panickedIn.has(result.pkg)) {
+        // Running in parallel or paused when the binary died
+        testCases.push(this.toTestCase(result, 'not_run', `Not run: ${panickedIn.get(result.pkg)} panicked`));
+      }
+    }
+
+    // Packages that failed without a failing test: build failures, init/TestMain panics
+    const buildFailures: BuildFailure[] = [];
+    for (const [pkg, pkgResult] of packageResults.entries()) {
+      if (cancelled && pkgResult.action === 'output' && !testCases.some(c => c.package === pkg)) {
+        testCases.push({
+          package: pkg,
+          name: '[cancelled]',
+          status: 'cancelled',
+          duration_ms: 0,
+          output: pkgResult.output,
+          failure_message: 'Run cancelled before the package finished',
+        });
+        continue;
+      }
+
+      if (pkgResult.action !== 'fail' || packagesWithFailedTests.has(pkg)) {
+        continue;
+      }
+
+      const buildFailed = pkgResult.failedBuild || pkgResult.output.includes('[build failed]');
+      if (!buildFailed) {
+        // e.g. a panic in init or TestMain
+        const panic = this.extractPanic(pkgResult.output);
+        testCases.push({
+          package: pkg,
+          name: '[package]',
+          status: 'error',
+          duration_ms: 0,
+          output: pkgResult.output,
+          failure_message: panic ? `panic: ${panic.message}` : 'Package failed outside of a test',
+          panic,
+        });
+        continue;
       }
+
+      // A dependent has no diagnostics of its own; they were printed for the broken package
+      const output = buildOutput?.get(pkg) ||
+        (pkgResult.causedBy && buildOutput?.get(pkgResult.causedBy)) || stderr || pkgResult.output;
+      const failure: BuildFailure = { package: pkg, errors: buildErrorParser.parse(output), caused_by: pkgResult.causedBy };
+      buildFailures.push(failure);
+
+      testCases.push({
+        package: pkg,
+        name: '[build failed]',
+        status: 'error',
+        duration_ms: 0,
+        output,
+        failure_message: failure.caused_by
+          ? `Build failed: dependency ${failure.caused_by} does not compile`
+          : failure.errors.length > 0 ? `Build failed: ${buildErrorParser.format(failure.errors[0])}` : 'Build failed',
+      });
     }
 
     // Fallback: parse non-JSON output if no JSON events found
-    if (passed === 0 && failed === 0) {
+    if (passed === 0 && failed === 0 && testCases.length === 0) {
       return this.parseNonJsonOutput(stdout);
     }
 
@@ -218,9 +3005,63 @@ export class GoTestRunner implements TestRunner {
       failed,
       total: passed + failed,
       failures,
+      testCases,
+      buildFailures,
+    };
+  }
+
+  /**
+   * The first panic in a test's output, with the goroutine stacks that follow it
+   * "panic: boom [recovered]" is reported as boom; the stack is everything
+   * from the first goroutine header on, unaltered.
+   */
+  private extractPanic(output: string): TestPanic | undefined {
+    const match = output.match(/^panic: (.*?)(?: \[recovered\])?$/m);
+    // go test's own -timeout panics too; that is a timeout, not a bug in the test
+    if (!match || match[1].startsWith('test timed out after')) {
+      return undefined;
+    }
+
+    const rest = output.slice(match.index!);
+    const stackStart = rest.search(/^goroutine \d+ \[/m);
+    return { message: match[1], stack: stackStart >= 0 ? rest.slice(stackStart) : '' };
+  }
+
+  /**
+   * Map a FailedBuild import path to the package that did not compile
+   * "example.com/pkg_test [example.com/pkg.test]" -> "example.com/pkg"
+   * "example.com/dep [example.com/pkg.test]" -> "example.com/dep"
+   */
+  private packageOfBuild(importPath: string): string {
+    return importPath.trim().split(/\s+/)[0].replace(/_test$/, '');
+  }
+
+  /**
+   * Build a test case result from accumulated JSON events
+   */
+  private toTestCase(
+    result: { pkg: string; test: string; output: string; elapsed: number },
+    status: TestCaseStatus,
+    failureMessage?: string
+  ): TestCaseResult {
+    return {
+      package: result.pkg,
+      name: result.test,
+      status,
+      duration_ms: Math.round(result.elapsed * 1000),
+      output: result.output,
+      failure_message: failureMessage,
     };
   }
 
+  /**
+   * Extract the t.Skip reason from test output
+   */
+  private extractSkipReason(output: string): string | undefined {
+    const match = output.match(/^\s+\S+_test\.go:\d+:\s*(.*)$/m);
+    return match ? match[1] : undefined;
+  }
+
   /**
    * Extract file location from Go test output
    */
@@ -242,10 +3083,13 @@ export class GoTestRunner implements TestRunner {
     failed: number;
     total: number;
     failures: TestFailure[];
+    testCases: TestCaseResult[];
+    buildFailures: BuildFailure[];
   } {
     let passed = 0;
     let failed = 0;
     const failures: TestFailure[] = [];
+    const testCases: TestCaseResult[] = [];
 
     // Parse summary line: "PASS" or "FAIL"
     // Count test functions
@@ -254,6 +3098,7 @@ export class GoTestRunner implements TestRunner {
 
     for (const match of passMatches) {
       passed++;
+      testCases.push({ package: '', name: match[1], status: 'passed', duration_ms: 0, output: '' });
     }
 
     for (const match of failMatches) {
@@ -270,6 +3115,14 @@ export class GoTestRunner implements TestRunner {
         stack_trace: '',
         location: 'unknown',
       });
+      testCases.push({
+        package: '',
+        name: testName,
+        status: 'failed',
+        duration_ms: 0,
+        output: '',
+        failure_message: errorMatch ? errorMatch[1] : 'Test failed',
+      });
     }
 
     return {
@@ -277,6 +3130,8 @@ export class GoTestRunner implements TestRunner {
       failed,
       total: passed + failed,
       failures,
+      testCases,
+      buildFailures: [],
     };
   }
 
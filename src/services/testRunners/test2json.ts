@@ -0,0 +1,99 @@
+/**
+ * Test2JSON Converter
+ *
+ * Converts the -test.v output of a compiled test binary into `go test -json`
+ * events, like `go tool test2json`, so prebuilt binaries can be parsed
+ * without a Go toolchain. Events are attributed to the test named by the
+ * most recent === RUN/CONT/NAME line or --- result line; the trailing
+ * PASS/FAIL and coverage lines belong to the package.
+ */
+
+/**
+ * Turns raw process stdout into go test -json lines
+ */
+export interface OutputDecoder {
+  write(chunk: string): string;   // JSON lines for every complete input line
+  end(exitCode: number): string;  // Flush a trailing partial line and emit the package verdict
+}
+
+const MARKER_LINE = /^=== (RUN|PAUSE|CONT|NAME)\s+(\S+)/;
+const RESULT_LINE = /^\s*--- (PASS|FAIL|SKIP): (\S+) \((\d+(?:\.\d+)?)s\)/;
+const PACKAGE_LINE = /^(PASS|FAIL|coverage: .*)$/;
+
+const ACTIONS: Record<string, string> = {
+  RUN: 'run',
+  PAUSE: 'pause',
+  CONT: 'cont',
+  PASS: 'pass',
+  FAIL: 'fail',
+  SKIP: 'skip',
+};
+
+export class Test2JsonConverter implements OutputDecoder {
+  private pending = '';
+  private current: string | undefined;
+  private started = false;
+  private startedAt: number;
+
+  /**
+   * @param pkg Package label stamped on every event
+   * @param now Clock, for the package's elapsed time
+   */
+  constructor(private pkg: string, private now: () => number = Date.now) {
+    this.startedAt = now();
+  }
+
+  write(chunk: string): string {
+    const lines = (this.pending + chunk).split('\n');
+    this.pending = lines.pop()!;
+    return this.begin() + lines.map(line => this.convert(line + '\n')).join('');
+  }
+
+  end(exitCode: number): string {
+    let out = this.begin();
+    if (this.pending) {
+      out += this.convert(this.pending);
+      this.pending = '';
+    }
+    return out + this.event({
+      Action: exitCode === 0 ? 'pass' : 'fail',
+      Elapsed: (this.now() - this.startedAt) / 1000,
+    });
+  }
+
+  private begin(): string {
+    if (this.started) {
+      return '';
+    }
+    this.started = true;
+    return this.event({ Action: 'start' });
+  }
+
+  private convert(line: string): string {
+    const text = line.replace(/\n$/, '');
+
+    const marker = text.match(MARKER_LINE);
+    if (marker) {
+      this.current = marker[2];
+      const output = this.event({ Action: 'output', Test: this.current, Output: line });
+      // === NAME only switches attribution; it is not a state change
+      return marker[1] === 'NAME' ? output : this.event({ Action: ACTIONS[marker[1]], Test: this.current }) + output;
+    }
+
+    const result = text.match(RESULT_LINE);
+    if (result) {
+      this.current = result[2];
+      return this.event({ Action: 'output', Test: this.current, Output: line }) +
+        this.event({ Action: ACTIONS[result[1]], Test: this.current, Elapsed: parseFloat(result[3]) });
+    }
+
+    if (PACKAGE_LINE.test(text)) {
+      this.current = undefined;
+    }
+    return this.event({ Action: 'output', Test: this.current, Output: line });
+  }
+
+  private event(fields: { Action: string; Test?: string; Output?: string; Elapsed?: number }): string {
+    return JSON.stringify({ Action: fields.Action, Package: this.pkg, ...fields }) + '\n';
+  }
+}
@@ -0,0 +1,115 @@
+/**
+ * Test Sharder
+ *
+ * Splits a package's top-level tests into shards of similar total duration
+ * so one large package can run as several `go test -run` processes.
+ * Durations come from a timings file updated after every run:
+ *   { "example.com/mod/store": { "TestMigrate": 5120, "TestQuery": 240 } }
+ */
+
+import * as fs from 'fs/promises';
+import * as path from 'path';
+import { TestCaseResult } from '../types/mcp';
+import { logger } from './loggerService';
+
+export interface TestTiming {
+  name: string;
+  duration_ms?: number;            // Undefined when the test has no history
+}
+
+// Package import path -> top-level test name -> last duration in ms
+export type TestTimings = Record<string, Record<string, number>>;
+
+export class TestSharder {
+  /**
+   * Partition tests into at most n shards of balanced total duration
+   * Greedy longest-processing-time: the slowest remaining test goes to the
+   * currently lightest shard. Tests without history are assumed to take the
+   * average known duration; with no history at all, tests are dealt
+   * round-robin by name.
+   * @param tests Top-level tests of one package
+   * @param n Number of shards
+   * @returns Test names per shard; empty shards are dropped
+   */
+  balance(tests: TestTiming[], n: number): string[][] {
+    const shardCount = Math.max(1, Math.min(n, tests.length));
+    const shards: string[][] = Array.from({ length: shardCount }, () => []);
+    const byName = [...tests].sort((a, b) => a.name.localeCompare(b.name));
+    const known = tests.filter(t => t.duration_ms !== undefined);
+
+    if (known.length === 0) {
+      byName.forEach((test, i) => shards[i % shardCount].push(test.name));
+      return shards.filter(s => s.length > 0);
+    }
+
+    const average = known.reduce((sum, t) => sum + t.duration_ms!, 0) / known.length;
+    const weighted = byName
+      .map(t => ({ name: t.name, duration: t.duration_ms ?? average }))
+      .sort((a, b) => b.duration - a.duration); // Stable, so ties stay in name order
+    const totals = new Array<number>(shardCount).fill(0);
+
+    for (const test of weighted) {
+      const lightest = totals.indexOf(Math.min(...totals));
+      shards[lightest].push(test.name);
+      totals[lightest] += test.duration;
+    }
+
+    return shards.filter(s => s.length > 0);
+  }
+
+  /**
+   * Wall time of running jobs on a fixed number of workers
+   * Each job starts on the first free worker, in order, as the worker pool does.
+   * @param durations Job durations in scheduling order
+   * @param workers Jobs running at once
+   */
+  estimateWallTime(durations: number[], workers: number): number {
+    const free = new Array<number>(Math.max(1, Math.min(workers, durations.length))).fill(0);
+
+    for (const duration of durations) {
+      const next = free.indexOf(Math.min(...free));
+      free[next] += duration;
+    }
+
+    return Math.max(...free);
+  }
+
+  /**
+   * Read a timings file
+   * @returns Stored timings, or none if the file does not exist yet
+   */
+  async readTimings(timingsPath: string): Promise<TestTimings> {
+    try {
+      return JSON.parse(await fs.readFile(timingsPath, 'utf-8')) as TestTimings;
+    } catch (error: any) {
+      if (error.code !== 'ENOENT') {
+        logger.warn(`Ignoring unreadable timings file ${timingsPath}: ${error.message}`);
+      }
+      return {};
+    }
+  }
+
+  /**
+   * Record the durations of this run's top-level tests
+   * Packages and tests that did not run keep their previous timings.
+   * @param timingsPath Timings file (created if missing)
+   * @param testCases Results of the run
+   */
+  async updateTimings(timingsPath: string, testCases: TestCaseResult[]): Promise<void> {
+    const timings = await this.readTimings(timingsPath);
+
+    for (const testCase of testCases) {
+      const topLevel = !testCase.name.includes('/') && !testCase.name.startsWith('[');
+      if (!topLevel || testCase.cached || (testCase.status !== 'passed' && testCase.status !== 'failed')) {
+        continue;
+      }
+      timings[testCase.package] = { ...timings[testCase.package], [testCase.name]: testCase.duration_ms };
+    }
+
+    await fs.mkdir(path.dirname(timingsPath), { recursive: true });
+    await fs.writeFile(timingsPath, JSON.stringify(timings, null, 2) + '\n', 'utf-8');
+  }
+}
+
+// Export singleton instance
+export const testSharder = new TestSharder();
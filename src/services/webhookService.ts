@@ -0,0 +1,130 @@
+/**
+ * Webhook Service
+ *
+ * POSTs a finished run's summary to a configured URL, e.g. a Slack or Teams
+ * incoming webhook. Delivery is best effort: 5xx responses and network
+ * errors are retried with exponential backoff, and a webhook that still
+ * fails is logged without failing the run.
+ *
+ * Templates shape the body for endpoints that expect their own format.
+ * Placeholders are dotted paths into the summary plus `status`, and are
+ * JSON-escaped so they can sit inside string literals:
+ *   {"text": "Nightly run {{status}}: {{totals.failed}} failed at {{metadata.git_sha}}"}
+ */
+
+import axios from 'axios';
+import { RunSummary } from '../types/mcp';
+import { logger } from './loggerService';
+import { EXIT_CANCELLED } from './reporters/jsonSummaryReporter';
+import { RetryPolicy } from './sandboxService';
+
+export interface WebhookConfig {
+  url: string;
+  template?: string;            // Body template; defaults to a compact JSON subset of the summary
+  timeout_ms?: number;
+}
+
+export const DEFAULT_WEBHOOK_RETRY_POLICY: RetryPolicy = {
+  maxRetries: 3,
+  baseDelayMs: 1000,
+  maxDelayMs: 10000,
+  sleep: ms => new Promise(resolve => setTimeout(resolve, ms)),
+};
+
+const PLACEHOLDER = /\{\{\s*([\w.]+)\s*\}\}/g;
+
+export class WebhookService {
+  private retryPolicy: RetryPolicy;
+
+  constructor(retryPolicy: RetryPolicy = DEFAULT_WEBHOOK_RETRY_POLICY) {
+    this.retryPolicy = retryPolicy;
+  }
+
+  /**
+   * Deliver a run summary
+   * @param summary Finished run
+   * @param config Endpoint and optional body template
+   * @returns Whether the endpoint accepted the notification; never throws
+   */
+  async notify(summary: RunSummary, config: WebhookConfig): Promise<boolean> {
+    const body = this.renderBody(summary, config.template);
+
+    for (let attempt = 0; ; attempt++) {
+      let retryable: boolean;
+      let detail: string;
+
+      try {
+        const response = await axios.post(config.url, body, {
+          headers: { 'Content-Type': 'application/json' },
+          timeout: config.timeout_ms ?? 10000,
+          validateStatus: () => true, // Classify statuses ourselves
+        });
+        if (response.status < 300) {
+          logger.info(`Webhook delivered to ${this.redact(config.url)} (HTTP ${response.status})`);
+          return true;
+        }
+        retryable = response.status >= 500;
+        detail = `HTTP ${response.status}`;
+      } catch (error: any) {
+        retryable = true; // No response at all: connection refused, timeout, DNS
+        detail = error.message;
+      }
+
+      if (!retryable || attempt >= this.retryPolicy.maxRetries) {
+        logger.warn(`Webhook to ${this.redact(config.url)} failed after ${attempt + 1} attempts: ${detail}`);
+        return false;
+      }
+
+      const delay = Math.min(this.retryPolicy.baseDelayMs * Math.pow(2, attempt), this.retryPolicy.maxDelayMs);
+      logger.debug(`Webhook attempt ${attempt + 1} failed (${detail}), retrying in ${delay}ms`);
+      await this.retryPolicy.sleep(delay);
+    }
+  }
+
+  /**
+   * Build the request body
+   * @param summary Finished run
+   * @param template Body template; without one, a compact JSON subset is sent
+   */
+  renderBody(summary: RunSummary, template?: string): string {
+    const status = summary.exit_status === 0 ? 'passed' : summary.exit_status === EXIT_CANCELLED ? 'cancelled' : 'failed';
+
+    if (!template) {
+      return JSON.stringify({
+        status,
+        schemaVersion: summary.schemaVersion,
+        metadata: summary.metadata,
+        totals: summary.totals,
+        coverage: { percentage: summary.coverage.percentage },
+        exit_status: summary.exit_status,
+        failed_tests: summary.tests
+          .filter(t => t.status === 'failed' || t.status === 'error' || t.status === 'timed_out')
+          .map(t => ({ package: t.package, name: t.name, failure_message: t.failure_message })),
+      });
+    }
+
+    const fields: Record<string, unknown> = { ...summary, status };
+    return template.replace(PLACEHOLDER, (_match, key: string) => {
+      const value = key.split('.').reduce<any>((obj, part) => (obj == null ? undefined : obj[part]), fields);
+      if (value === undefined || value === null) {
+        return '';
+      }
+      const text = typeof value === 'object' ? JSON.stringify(value) : String(value);
+      return JSON.stringify(text).slice(1, -1);
+    });
+  }
+
+  /**
+   * Webhook URLs embed their secret; only log the origin
+   */
+  private redact(url: string): string {
+    try {
+      return new URL(url).origin;
+    } catch {
+      return '<invalid url>';
+    }
+  }
+}
+
+// Export singleton instance
+export const webhookService = new WebhookService();
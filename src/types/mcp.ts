@@ -210,7 +210,7 @@ export interface RunCriticReviewResponse {
 
 // Interfaces for generate_test_suite tool (L-3)
 export type TestFramework =
-  | 'pytest' | 'jest' | 'go_testing' | 'rust_test'
+  | 'pytest' | 'jest' | 'go_testing' | 'ginkgo' | 'rust_test'
   | 'gtest' | 'junit5' | 'jasmine' | 'pgtap';
 
 export interface GenerateTestSuiteParams {
@@ -283,6 +283,71 @@ export interface TestFailure {
   location: string; // file:line
 }
 
+export type TestCaseStatus = 'passed' | 'failed' | 'skipped' | 'error' | 'timed_out' | 'cancelled' | 'not_run';
+
+// A panic raised while a test ran
+export interface TestPanic {
+  message: string;            // The panic value, e.g. runtime error: index out of range [3] with length 3
+  stack: string;              // Goroutine stacks exactly as the runtime printed them
+}
+
+export interface TestCaseResult {
+  package: string;
+  name: string;               // Full name including subtest path, e.g. TestFoo/case_1
+  status: TestCaseStatus;
+  duration_ms: number;
+  output: string;
+  failure_message?: string;
+  attempts?: number;          // Total executions including retries
+  flaky?: boolean;            // Failed at least once, then passed on retry
+  goroutine_dump?: string;    // Full goroutine dump captured when the test hung
+  hierarchy?: string[];       // Enclosing containers, e.g. Ginkgo Describe/Context texts
+  cached?: boolean;           // Reused from the result cache instead of re-running
+  data_races?: DataRace[];    // Race detector reports raised while this test ran
+  panic?: TestPanic;          // Set on the test that panicked; the rest of its package is not_run
+  log_path?: string;          // Raw go test output of its package, when kept by keep_logs
+  resumed?: boolean;          // Finished before the run was interrupted; restored from its checkpoint
+}
+
+export interface CompilerError {
+  file: string;               // As printed by the compiler, e.g. ./calc.go
+  line: number;
+  column?: number;
+  message: string;            // Continuation lines (have/want) are joined with newlines
+}
+
+// A package that did not compile; its tests never ran
+export interface BuildFailure {
+  package: string;
+  errors: CompilerError[];
+  caused_by?: string;         // Dependency whose build failure broke this package
+}
+
+export interface RaceAccess {
+  operation: string;          // e.g. "write", "read", "atomic write"
+  goroutine: string;          // Goroutine id, or "main"
+  stack: string;              // Frames as printed by the race detector
+}
+
+export interface DataRace {
+  address: string;            // Memory address both goroutines accessed
+  current: RaceAccess;        // Access that detected the race
+  previous: RaceAccess;       // Earlier conflicting access
+  package?: string;
+  test?: string;
+  raw: string;                // Full WARNING: DATA RACE block
+}
+
+// A goroutine started during a package's tests and still running after they finished
+export interface GoroutineLeak {
+  package: string;
+  goroutine: number;          // Goroutine ID
+  state: string;              // e.g. chan receive, select, IO wait
+  function: string;           // Topmost function of the stack
+  created_by?: string;        // Function whose go statement started it
+  stack: string;              // As printed by runtime.Stack
+}
+
 export interface TestExecutionResult {
   success: boolean;
   passed_tests: number;
@@ -293,13 +358,302 @@ export interface TestExecutionResult {
   failures: TestFailure[];
   stdout: string;
   stderr: string;
+  test_cases?: TestCaseResult[]; // Per-test results, when the runner reports them
+  coverage_violations?: CoverageViolation[];
+  data_races?: DataRace[];    // Listed separately from assertion failures
+  goroutine_leaks?: GoroutineLeak[]; // Goroutines left running by packages under leak_check
+  out_of_memory?: boolean;    // The sandbox container hit its memory limit
+  lint_findings?: LintFinding[]; // golangci-lint issues, when lint was requested
+  benchmarks?: BenchResult[];  // Bench mode results
+  benchmark_comparison?: BenchmarkComparison; // Against bench_baseline_path, when set
+  artifact_dirs?: string[];   // Host directories holding artifacts copied out of sandbox containers
+  artifact_warnings?: string[]; // Artifacts that existed but could not be copied
+  cancelled?: boolean;        // The run was cancelled; results are partial
+  aborted_early?: boolean;    // fail_fast stopped the run after the first failure; results are partial
+  build_failures?: BuildFailure[]; // Packages that did not compile, with parsed compiler errors
+  shuffle?: ShuffleInfo;      // Seeds used when shuffle was set
+  test_diff?: DiffSummary;    // Against baseline_summary_path or baseline_run_id, when set
+  changed_coverage?: ChangedCoverageReport; // Coverage of lines changed since changed_coverage_base
+  matrix?: MatrixReport;      // Per-version outcomes, when go_versions was set
+  durations?: DurationReport; // Slowest tests and package totals, when slowest was set
+  plan?: RunPlan;             // What would have run, when dry_run was set
+  inventory?: TestInventory;  // The tests the run would execute, when list was set
+  cache_bypassed?: string[];  // Packages go test replayed from its cache, rerun with -count=1 so their coverage counts
+  checkpoint_run_id?: string; // Pass as resume_run_id to continue this run where it stopped
+  package_logs?: Record<string, string>; // Package -> raw go test log kept by keep_logs
+  stored_run_id?: string;     // Key of this run's summary in result_store
+}
+
+// One Go version's run in a version matrix
+export interface MatrixRun {
+  go_version: string;
+  image: string;
+  success: boolean;
+  passed_tests: number;
+  failed_tests: number;
+  total_tests: number;
+  coverage_percentage: number; // This version's own coverage
+}
+
+// A test's outcome on each Go version it ran on
+export interface MatrixTestRow {
+  package: string;
+  name: string;
+  statuses: Record<string, TestCaseStatus>; // Keyed by Go version; missing where the test did not run
+  version_specific: boolean;  // Outcomes differ between versions
+}
+
+export interface MatrixReport {
+  go_versions: string[];
+  runs: MatrixRun[];
+  tests: MatrixTestRow[];     // Sorted by package and name
+  unified_coverage?: UnifiedCoverage; // When at least one version produced a coverage profile
+}
+
+// Coverage of the union of every version's profile: a statement counts as covered if any version ran it
+export interface UnifiedCoverage {
+  percentage: number;
+  statements_covered: number;
+  statements_total: number;
+  profile_path?: string;      // The unioned profile, in coverage.out format
+  missing_versions: string[]; // Versions without a profile, e.g. because the build failed
+  version_specific_files: string[]; // In only some versions' profiles, e.g. behind go1.N build constraints
+  conflicting_files: string[]; // Instrumented differently between versions; the most covered version's blocks were kept
+}
+
+export interface SlowTest {
+  package: string;
+  name: string;               // Top-level test; subtest time is part of its parent's
+  status: TestCaseStatus;
+  duration_ms: number;
+}
+
+export interface PackageDuration {
+  package: string;
+  duration_ms: number;        // Sum of its top-level tests; parallel tests can add up to more than wall time
+  tests: number;
+  percentage: number;         // Share of the run's total test time
+}
+
+// Where a run's test time went
+export interface DurationReport {
+  total_ms: number;           // Sum of every top-level test's duration
+  slowest: SlowTest[];        // At most `slowest` tests, longest first
+  packages: PackageDuration[]; // Longest first
+}
+
+// One go test process a run would start
+export interface PlannedProcess {
+  packages: string[];         // Import paths; several only when the run uses a single go test process
+  tests?: string[];           // -run selection from sharding or labels; every test when unset
+  estimated_ms?: number;      // From timings_path; unset when the package has no history
+}
+
+// What a run would execute, from dry_run; contains nothing time- or run-specific so plans can be diffed
+export interface RunPlan {
+  since_ref?: string;
+  packages: string[];         // Selected packages, sorted
+  cached: string[];           // Unchanged packages whose cached results would be reused
+  ginkgo_suites: string[];    // Package directories that would run through the Ginkgo CLI
+  processes: PlannedProcess[]; // In scheduling order
+  concurrency: number;        // Processes at once; packages at once for a single go test process
+  go_versions?: string[];     // Matrix runs repeat the processes once per version
+  images: string[];           // Container images, when sandboxed
+  containers: number;         // Test containers that would be created
+  estimated_duration_ms?: number; // Wall time from historical timings; unset without timings_path
+  unestimated_processes: number; // Processes left out of the estimate for lack of history
 }
 
 export interface TestExecutionOptions {
   timeout_seconds?: number;
   memory_limit_mb?: number;
-  cpu_limit?: number;
+  memory?: string;            // Docker-style memory limit, e.g. "2g"; overrides memory_limit_mb
+  cpu_limit?: number;         // CPUs per container, e.g. 1.5
+  pids_limit?: number;        // Max processes per container
   enable_network?: boolean;
+  junit_output_path?: string; // Write a JUnit XML report here after execution
+  retries?: number;           // Re-run each failing test N more times to detect flakiness
+  fail_on_flaky?: boolean;    // Treat flaky-but-passing tests as a failed run (default: true)
+  since_ref?: string;         // Only test packages affected by changes since this git ref
+  test_timeout_seconds?: number; // Per-test watchdog; hung tests get SIGQUIT for a goroutine dump
+  coverage_config_path?: string; // JSON CoverageGateConfig; violations fail the run
+  cache_dir?: string;         // Reuse passing per-package results keyed by content hash
+  no_cache?: boolean;         // Bypass the result cache even when cache_dir is set
+  race?: boolean;             // Build with -race (requires cgo) and report data races
+  coverage_html_dir?: string; // Write a browsable HTML coverage report here
+  sandbox?: boolean;          // Run the tests inside a resource-limited Docker container
+  container_create_retries?: number; // Retries for transient Docker errors when creating a container
+  artifacts?: string[];       // Container paths to copy out after a sandboxed run, e.g. /go/test-output
+  artifacts_dir?: string;     // Host directory for copied artifacts (default: <workspace>/reports/artifacts)
+  detect_framework?: boolean; // Route each package to plain go test or Ginkgo based on its sources
+  framework_overrides?: Record<string, GoFramework>; // Import path glob -> framework, wins over detection
+  bench?: string;             // Run benchmarks matching this regexp (with -benchmem) instead of tests
+  bench_baseline_path?: string; // JSON BenchResult[] from a previous run to compare against
+  bench_threshold_percent?: number; // Regressions beyond this fail the run (default: 10)
+  bench_output_path?: string; // Save this run's BenchResult[] as JSON, e.g. as the next baseline
+  parallel?: number;          // Run packages separately, up to N at once (0 = one per CPU)
+  lint?: boolean;             // Also run golangci-lint and report its findings
+  lint_config_path?: string;  // Existing .golangci.yml to pass through to golangci-lint
+  lint_fail_on_severity?: LintSeverity; // Fail the run on findings at or above this severity
+  summary_json_path?: string; // Write the versioned RunSummary JSON here
+  baseline_summary_path?: string; // RunSummary JSON from the target branch to diff this run against
+  packages?: string[];        // Go package patterns to test (default: ./...); since_ref selects its own
+  shards?: number;            // Split each package's top-level tests into up to N -run shards
+  shard_packages?: string[];  // Import paths to shard (default: every package)
+  timings_path?: string;      // Historical test durations used to balance shards; updated after each run
+  image?: string;             // Sandbox image, e.g. golang:1.22-alpine (default: per framework)
+  go_versions?: string[];     // Run the suite in the sandbox once per Go version, e.g. ["1.21", "1.22"]
+  matrix_report_path?: string; // Write the combined per-version MatrixReport JSON here
+  coverage_exclude?: string[]; // Drop coverage for matching profile file names, e.g. ["*.pb.go", "vendor/", "mocks/"]
+  webhook_url?: string;       // POST the run summary here on completion; failures are logged, never fatal
+  webhook_template?: string;  // Body template with {{totals.failed}}-style placeholders; defaults to a compact summary
+  fail_fast?: boolean;        // Stop scheduling and kill in-flight packages on the first test failure
+  test_binaries_dir?: string; // Run the <package>.test binaries here (built with go test -c -cover) instead of compiling
+  shuffle?: 'on' | 'off' | number; // go test -shuffle; "on" picks a recorded seed, parallel packages derive theirs from it
+  log_level?: LogLevel;       // Run diagnostics on stderr at this level, e.g. debug to trace each package
+  log_format?: LogFormat;     // Run diagnostics as JSON lines or key=value text
+  env?: string[];             // KEY=VALUE pairs set for go test only, not go list or other helpers
+  env_file?: string;          // File of KEY=VALUE lines; env entries override it
+  secret_files?: string[];    // KEY=path pairs; the value is read from the file and redacted from logs
+  changed_coverage_base?: string; // Git ref; report coverage of the lines changed since it
+  changed_coverage_minimum?: number; // Fail when less of the changed executable lines are covered (0-100)
+  run?: string;               // Passed to go test -run; with labels, filters the labelled top-level tests
+  labels?: string[];          // Only run tests annotated with one of these // +alcs:<label> labels
+  exclude_labels?: string[];  // Skip tests annotated with any of these labels
+  mount?: MountStrategy;      // bind (default) mounts the workspace; copy snapshots it to a volume once per run
+  mount_exclude?: string[];   // Extra globs left out of a copy mount, on top of .dockerignore and .gitignore
+  slowest?: number;           // Tests listed in the slowest-tests report (default: 10); also logs the report
+  registry_mirror?: string;   // Registry prefix to pull from when Docker Hub is unreachable, e.g. registry.internal/
+  registry_mirror_rules?: string[]; // from=to image prefix rewrites for the mirror, tried before registry_mirror
+  collapse_subtests?: boolean; // Log the test results with fully passing subtests folded into their parent
+  dry_run?: boolean;          // Plan the run (selection, sharding, containers, estimate) without building or running anything
+  plan_json_path?: string;    // Write the dry-run plan as JSON
+  keep_logs?: KeepLogs;       // Packages whose raw go test output is kept under artifacts_dir/logs (default: failed)
+  tags?: string[];            // Build tags for every go list and go test of the run, e.g. ['integration', 'e2e']
+  result_store?: string;      // Keep each run's RunSummary here: a directory, or s3://bucket/prefix
+  result_store_endpoint?: string; // S3-compatible endpoint for an s3:// result_store, e.g. http://minio:9000
+  result_store_region?: string;   // Signing region for an s3:// result_store (default: AWS_REGION, then us-east-1)
+  baseline_run_id?: string;   // Run in result_store to diff against, or 'latest' for the most recently stored
+  leak_check?: string[];      // Import path globs of packages that fail on goroutines left running after their tests
+  color?: ColorMode;          // ANSI color in the test list and slowest-tests output (default: auto)
+  services?: ServiceDependency[]; // Containers started and health-checked before the tests, removed after them
+  list?: boolean;             // List the tests the run would execute as a TestInventory instead of running them
+  list_json_path?: string;    // Write the list-mode TestInventory JSON here
+  count?: number;             // Passed to go test -count; any value bypasses go's test cache, 1 forces a fresh run
+  tap_output_path?: string;   // Write a TAP version 13 report here after execution
+  checkpoint?: boolean;       // Store progress in result_store after every package, so the run can be resumed
+  resume_run_id?: string;     // Checkpointed run in result_store to continue; only what it did not finish runs
+}
+
+// One test in a TestInventory
+export interface ListedTest {
+  id: string;                 // <import path>.<name>, e.g. example.com/mod/calc.TestAdd
+  package: string;
+  name: string;               // Top-level test, example or fuzz target; never a subtest
+  labels: string[];           // +alcs: labels from the test's doc comment
+  duration_ms?: number;       // From timings_path, when the test has history
+}
+
+// Flat list of the tests a run would execute, from list mode
+// Tests are what go test -list reports for the run's packages and build
+// tags, narrowed by its run and label selection. Subtests created with t.Run
+// only exist while the parent runs and cannot be listed; they run as part of
+// their listed parent.
+export interface TestInventory {
+  schemaVersion: number;      // Bumped on incompatible changes
+  since_ref?: string;
+  tags?: string[];
+  packages: string[];         // Packages of the selection that have tests, sorted
+  unlisted_packages: string[]; // Packages go test -list failed on, e.g. because they do not compile
+  tests: ListedTest[];        // Sorted by package and name
+}
+
+// Canonical record of one run; the JSON, JUnit, and HTML reports are all rendered from it
+export interface RunSummary {
+  schemaVersion: number;      // Bumped on incompatible changes (camelCase is part of the published schema)
+  metadata: RunMetadata;
+  tests: TestCaseResult[];
+  totals: {
+    passed: number;
+    failed: number;           // Includes errors and timeouts
+    skipped: number;
+    cancelled: number;        // In flight or queued when the run was cancelled or aborted early
+    not_run: number;          // Never finished because another test in the package panicked
+    panicked: number;         // Tests that panicked; also counted as failed
+    total: number;
+  };
+  coverage: {
+    percentage: number;
+    profile?: GoCoverageProfile; // Merged profile, when one was produced
+    changed?: ChangedCoverageReport; // Lines changed since changed_coverage_base
+  };
+  lint: {
+    findings_count: number;
+  };
+  build_failures: BuildFailure[]; // Also present in tests as [build failed] cases
+  durations: DurationReport;  // Slowest tests and per-package test time
+  shuffle?: ShuffleInfo;      // Present when tests ran in shuffled order
+  exit_status: number;        // 0 when the run succeeded, 130 when cancelled, 1 otherwise
+  aborted_early?: boolean;    // fail_fast stopped the run after the first failure; results are partial
+  checkpoint?: RunCheckpoint; // Progress of a checkpointed run; results are partial while any run is pending
+}
+
+// What a checkpointed run was asked to do, and how far it got
+export interface RunCheckpoint {
+  selection: CheckpointSelection;
+  runs?: CheckpointedRun[];   // go test processes in schedule order; unset until they were scheduled
+}
+
+// Options a resumed run must share with the checkpoint, so both select the same tests
+export interface CheckpointSelection {
+  packages: string[];         // As selected, e.g. ./... or the packages affected since since_ref
+  tags?: string[];
+  run?: string;
+  labels?: string[];
+  exclude_labels?: string[];
+  shards?: number;
+  shuffle_seed?: number;      // Base seed, replayed by the resumed run
+  race?: boolean;
+  count?: number;
+}
+
+export interface CheckpointedRun {
+  package: string;
+  tests?: string[];           // Top-level tests of a -run shard; unset when the package ran whole
+  status: 'pending' | 'passed' | 'failed'; // Pending until its process exits; an interrupted one runs again
+}
+
+// Enough to replay a shuffled run: rerun with shuffle set to base_seed, or one package with its seed
+export interface ShuffleInfo {
+  base_seed: number;
+  seeds: Record<string, number>; // Package -> seed go test reported using
+}
+
+export interface RunMetadata {
+  framework: TestFramework;
+  started_at: string;         // ISO 8601
+  finished_at: string;        // ISO 8601
+  duration_ms: number;
+  go_version?: string;
+  git_sha?: string;           // HEAD of the workspace, when it is a git checkout
+  run_id?: string;            // Matches run_id in the run's diagnostic log
+}
+
+// A test present in either run, identified by package + name
+export interface TestDiffEntry {
+  package: string;
+  name: string;
+  baseline_status?: TestCaseStatus; // Absent for added tests
+  current_status?: TestCaseStatus;  // Absent for removed tests
+}
+
+// How each test changed between a baseline run and this one; unchanged tests are omitted
+export interface DiffSummary {
+  newly_failing: TestDiffEntry[];   // Passed (or skipped) in the baseline, failing now
+  newly_passing: TestDiffEntry[];   // Failing in the baseline, passing now
+  still_failing: TestDiffEntry[];
+  added: TestDiffEntry[];           // Only in this run; a renamed test shows up as removed + added
+  removed: TestDiffEntry[];         // Only in the baseline
 }
 
 export interface CoverageReport {
@@ -329,4 +683,145 @@ export interface StaticAnalysisResult {
   high_count: number;
   medium_count: number;
   low_count: number;
-}
\ No newline at end of file
+}
+
+// Go package test frameworks; values match the TestFramework of the runner used
+export enum GoFramework {
+  Standard = 'go_testing',    // testing package, including testify suites
+  Ginkgo = 'ginkgo',
+}
+
+// Interfaces for Go benchmarks
+export interface BenchResult {
+  package: string;
+  name: string;               // Without the -GOMAXPROCS suffix, e.g. BenchmarkParse/small
+  procs: number;              // GOMAXPROCS suffix (1 when absent)
+  iterations: number;
+  ns_per_op: number;
+  bytes_per_op?: number;      // With -benchmem
+  allocs_per_op?: number;     // With -benchmem
+  metrics?: Record<string, number>; // Other units, e.g. MB/s or b.ReportMetric values
+}
+
+export type BenchMetric = 'ns_per_op' | 'bytes_per_op' | 'allocs_per_op';
+
+export interface BenchDelta {
+  package: string;
+  name: string;
+  metric: BenchMetric;
+  base: number;
+  current: number;
+  delta_percent: number | null; // null when the base value is 0
+}
+
+export type Regression = BenchDelta;
+
+export interface BenchmarkComparison {
+  deltas: BenchDelta[];
+  regressions: Regression[];  // Deltas beyond the threshold
+  added: string[];            // "package name" present only in the new run
+  removed: string[];          // "package name" present only in the base run
+  threshold_percent: number;
+}
+
+// Interfaces for golangci-lint
+export type LintSeverity = 'info' | 'warning' | 'error';
+
+export type LogLevel = 'error' | 'warn' | 'info' | 'debug';
+
+export type LogFormat = 'json' | 'text';
+
+// How the workspace reaches sandbox containers
+export type MountStrategy = 'bind' | 'copy';
+
+export type KeepLogs = 'failed' | 'all' | 'none';
+
+// auto colors a TTY unless NO_COLOR is set
+export type ColorMode = 'auto' | 'always' | 'never';
+
+// A dependency container started before a run's tests and removed after them
+export interface ServiceDependency {
+  name: string;               // Network alias and env var prefix: postgres -> POSTGRES_HOST, POSTGRES_PORT
+  image: string;
+  ports?: number[];           // Container ports tests connect to; the first one is <NAME>_PORT
+  env?: Record<string, string>; // Environment of the service container
+  health_cmd?: string[];      // Run in the service container until it exits 0, e.g. ['pg_isready', '-U', 'postgres']
+  health_timeout_seconds?: number; // How long health_cmd may keep failing (default: 60)
+  health_interval_ms?: number; // Pause between health_cmd attempts (default: 1000)
+}
+
+export interface LintFinding {
+  linter: string;             // Reporting linter, e.g. errcheck
+  file: string;               // Relative to the linted directory
+  line: number;
+  col: number;                // 0 when the linter reports no column
+  message: string;
+  severity: LintSeverity;
+}
+
+export interface LintConfig {
+  config_path?: string;       // Passed as --config; otherwise golangci-lint discovers .golangci.yml itself
+  fail_on_severity?: LintSeverity;
+  timeout_seconds?: number;
+}
+
+// Interfaces for Go Coverage Profiles
+export type GoCoverageMode = 'set' | 'count' | 'atomic';
+
+export interface GoCoverageBlock {
+  start_line: number;
+  start_col: number;
+  end_line: number;
+  end_col: number;
+  num_statements: number;
+  count: number;
+}
+
+export interface GoCoverageProfile {
+  mode: GoCoverageMode;
+  files: Record<string, GoCoverageBlock[]>; // Keyed by file path as written in coverage.out
+}
+
+// Added or modified lines from a diff, with their new text
+export interface ChangedLine {
+  line: number;
+  text: string;
+}
+
+export interface LineDiff {
+  files: Record<string, ChangedLine[]>; // Keyed by path relative to the module root
+}
+
+export interface ChangedFileCoverage {
+  file: string;               // Profile file name
+  covered: number;
+  executable: number;
+  uncovered_lines: number[];
+}
+
+export interface ChangedCoverageReport {
+  covered_lines: number;
+  executable_lines: number;   // Changed lines with statements; comments, braces, and declarations are left out
+  percentage: number;         // 100 when no changed line is executable
+  files: ChangedFileCoverage[]; // Files with changed executable lines, by path
+  required?: number;          // changed_coverage_minimum, when set
+}
+
+export interface CoverageGateRule {
+  pattern: string;            // Package path glob, e.g. internal/auth/**
+  minimum: number;            // Minimum coverage percentage (0-100)
+}
+
+export interface CoverageGateConfig {
+  default_minimum: number;    // Applies to packages no rule matches
+  rules?: CoverageGateRule[];
+  exclude?: string[];         // File globs left out of the calculation, e.g. *_gen.go
+}
+
+export interface CoverageViolation {
+  package: string;
+  actual: number;
+  required: number;
+  pattern?: string;           // Rule that set the requirement (undefined for the default)
+}
+
@@ -0,0 +1,20 @@
+/**
+ * Raised when work stops because its AbortSignal fired.
+ */
+export class CancelledError extends Error {
+  constructor(context: string) {
+    super(`${context} was cancelled.`);
+    this.name = 'CancelledError';
+  }
+}
+
+/**
+ * Throws a CancelledError if the signal has already fired.
+ * @param signal The signal to check (a missing signal never fires).
+ * @param context A string describing the operation for the error message.
+ */
+export function throwIfCancelled(signal: AbortSignal | undefined, context: string): void {
+  if (signal?.aborted) {
+    throw new CancelledError(context);
+  }
+}
@@ -0,0 +1,59 @@
+/**
+ * Converts a path glob into a regular expression.
+ * Supports `*` (within a segment), `?`, `**` (any number of segments), and a
+ * trailing `/` for everything under a directory.
+ * Patterns without a leading `/` may match at any directory depth, so
+ * `internal/auth/**` matches `example.com/mod/internal/auth/token`.
+ * @param pattern The glob pattern.
+ * @returns A regular expression anchored to the full path.
+ */
+export function globToRegExp(pattern: string): RegExp {
+  const anchored = pattern.startsWith('/');
+  let body = anchored ? pattern.slice(1) : pattern;
+
+  // A trailing slash means "everything under this directory"
+  if (body.endsWith('/')) {
+    body += '**';
+  }
+
+  let regex = '';
+
+  for (let i = 0; i < body.length; i++) {
+    const ch = body[i];
+
+    if (ch === '*' && body[i + 1] === '*') {
+      const followedBySlash = body[i + 2] === '/';
+      const precededBySlash = i === 0 || body[i - 1] === '/';
+
+      if (precededBySlash && followedBySlash) {
+        regex += '(?:.*/)?';
+        i += 2;
+      } else if (precededBySlash && i + 2 === body.length && i > 0) {
+        // Trailing "/**" also matches the directory itself
+        regex = regex.slice(0, -1) + '(?:/.*)?';
+        i += 1;
+      } else {
+        regex += '.*';
+        i += 1;
+      }
+    } else if (ch === '*') {
+      regex += '[^/]*';
+    } else if (ch === '?') {
+      regex += '[^/]';
+    } else {
+      regex += ch.replace(/[.+^${}()|[\]\\]/g, '\\$&');
+    }
+  }
+
+  return new RegExp(anchored ? `^${regex}$` : `^(?:.*/)?${regex}$`);
+}
+
+/**
+ * Checks whether a path matches any of the given glob patterns.
+ * @param filePath The path to test (forward slashes).
+ * @param patterns The glob patterns.
+ * @returns True if at least one pattern matches.
+ */
+export function matchesAnyGlob(filePath: string, patterns: string[]): boolean {
+  return patterns.some(pattern => globToRegExp(pattern).test(filePath));
+}
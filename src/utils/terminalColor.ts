@@ -0,0 +1,64 @@
+import { ColorMode } from '../types/mcp';
+
+const ANSI_SEQUENCE = /\x1b\[[0-9;]*[A-Za-z]/g;
+
+export interface ColorStream {
+  isTTY?: boolean;
+}
+
+export interface Palette {
+  green(text: string): string;
+  red(text: string): string;
+  yellow(text: string): string;
+  bold(text: string): string;
+  dim(text: string): string;
+}
+
+/**
+ * Decides whether output written to a stream should be colored.
+ * `always` and `never` win over everything; `auto` (the default) colors only
+ * a TTY, and only when NO_COLOR (https://no-color.org) is unset or empty and
+ * TERM is not `dumb`.
+ * @param mode The requested color mode.
+ * @param stream The stream the output goes to.
+ * @param env Environment to read NO_COLOR and TERM from.
+ * @returns True if ANSI colors should be used.
+ */
+export function colorEnabled(
+  mode: ColorMode = 'auto',
+  stream: ColorStream = process.stdout,
+  env: NodeJS.ProcessEnv = process.env
+): boolean {
+  if (mode !== 'auto') {
+    return mode === 'always';
+  }
+  return Boolean(stream.isTTY) && !env.NO_COLOR && env.TERM !== 'dumb';
+}
+
+/**
+ * Builds color helpers that leave text untouched when color is off.
+ * Apply them after padding, so escape sequences do not skew column widths.
+ * @param enabled Whether to emit ANSI colors.
+ * @returns The palette.
+ */
+export function createPalette(enabled: boolean): Palette {
+  const sgr = (open: number, close: number) => (text: string) =>
+    enabled && text ? `\x1b[${open}m${text}\x1b[${close}m` : text;
+
+  return {
+    green: sgr(32, 39),
+    red: sgr(31, 39),
+    yellow: sgr(33, 39),
+    bold: sgr(1, 22),
+    dim: sgr(2, 22),
+  };
+}
+
+/**
+ * Removes ANSI escape sequences, e.g. before text goes to a log file.
+ * @param text The text to clean.
+ * @returns The text without escape sequences.
+ */
+export function stripAnsi(text: string): string {
+  return text.replace(ANSI_SEQUENCE, '');
+}
@@ -0,0 +1,49 @@
+import { CancelledError } from './cancellation';
+
+export type PoolOutcome<T, R> =
+  | { item: T; ok: true; value: R }
+  | { item: T; ok: false; error: Error };
+
+/**
+ * Runs a worker over every item with at most `concurrency` in flight.
+ * Workers pull the next item from a shared queue as soon as they finish, so
+ * one slow item never holds up the rest. A failing item is recorded and does
+ * not stop the pool. Once `signal` fires no new items are started; items
+ * still queued get a CancelledError outcome, and in-flight workers are left
+ * to observe the signal themselves.
+ * @param items The items to process.
+ * @param concurrency Maximum number of concurrent workers (at least 1).
+ * @param worker The async function to run for each item.
+ * @param signal Optional signal that stops the pool from taking new items.
+ * @returns One outcome per item, in input order regardless of completion order.
+ */
+export async function runWorkerPool<T, R>(
+  items: T[],
+  concurrency: number,
+  worker: (item: T, index: number) => Promise<R>,
+  signal?: AbortSignal
+): Promise<PoolOutcome<T, R>[]> {
+  const outcomes: PoolOutcome<T, R>[] = new Array(items.length);
+  let next = 0;
+
+  const runWorker = async () => {
+    while (next < items.length) {
+      const index = next++;
+      const item = items[index];
+      if (signal?.aborted) {
+        outcomes[index] = { item, ok: false, error: new CancelledError('Queued item') };
+        continue;
+      }
+      try {
+        outcomes[index] = { item, ok: true, value: await worker(item, index) };
+      } catch (error: any) {
+        outcomes[index] = { item, ok: false, error: error instanceof Error ? error : new Error(String(error)) };
+      }
+    }
+  };
+
+  const workers = Math.max(1, Math.min(Math.floor(concurrency) || 1, items.length));
+  await Promise.all(Array.from({ length: workers }, runWorker));
+
+  return outcomes;
+}
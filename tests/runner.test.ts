@@ -0,0 +1,115 @@
+/**
+ * Unit Tests for the embeddable Runner
+ */
+
+import { GoTestEvent, Runner, RunSummary } from '../src/runner';
+import * as child_process from 'child_process';
+import * as fs from 'fs/promises';
+import { EventEmitter } from 'events';
+
+jest.mock('child_process');
+jest.mock('fs/promises');
+jest.mock('../src/services/loggerService');
+jest.mock('../src/services/coverageParser');
+
+import { coverageParser } from '../src/services/coverageParser';
+
+function fakeGoProcess(stdout: string, exitCode: number = 0): any {
+  const child: any = new EventEmitter();
+  child.stdout = new EventEmitter();
+  child.stderr = new EventEmitter();
+  child.pid = 4242;
+  child.kill = jest.fn();
+
+  setImmediate(() => {
+    child.stdout.emit('data', Buffer.from(stdout));
+    child.emit('close', exitCode);
+  });
+
+  return child;
+}
+
+describe('Runner', () => {
+  const mockSpawn = child_process.spawn as unknown as jest.Mock;
+  const mockExecFile = child_process.execFile as unknown as jest.Mock;
+
+  const run = [
+    { Action: 'run', Package: 'example.com/api', Test: 'TestGet' },
+    { Action: 'pass', Package: 'example.com/api', Test: 'TestGet', Elapsed: 0.01 },
+    { Action: 'run', Package: 'example.com/api', Test: 'TestPut' },
+    { Action: 'fail', Package: 'example.com/api', Test: 'TestPut', Elapsed: 0.02 },
+    { Action: 'fail', Package: 'example.com/api', Elapsed: 0.03 },
+  ].map(e => JSON.stringify(e)).join('\n') + '\n';
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    (fs.access as jest.Mock).mockResolvedValue(undefined);
+    (coverageParser.parseGoCoverageProfile as jest.Mock).mockResolvedValue({
+      line_coverage: 64,
+      branch_coverage: 64,
+      function_coverage: 64,
+      lines_covered: 16,
+      lines_total: 25,
+      uncovered_lines: [],
+    });
+    mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+      callback(null, { stdout: cmd === 'git' ? 'abc123\n' : 'go1.23.4\n', stderr: '' });
+    });
+  });
+
+  it('should return the run summary and feed injected handlers', async () => {
+    mockSpawn.mockImplementation(() => fakeGoProcess(run, 1));
+    const events: GoTestEvent[] = [];
+    const summaries: RunSummary[] = [];
+
+    const runner = new Runner({
+      timeout_seconds: 60,
+      eventHandlers: [{ handleEvent: event => events.push(event) }],
+      summaryHandlers: [{ handleSummary: summary => { summaries.push(summary); } }],
+    });
+    const summary = await runner.run({ workspacePath: '/src/mod', packages: ['./api/...'] });
+
+    expect(mockSpawn).toHaveBeenCalledWith('go', expect.arrayContaining(['test', './api/...']), expect.objectContaining({ cwd: '/src/mod' }));
+    expect(summary.totals).toEqual({ passed: 1, failed: 1, skipped: 0, cancelled: 0, not_run: 0, panicked: 0, total: 2 });
+    expect(summary.coverage.percentage).toBe(64);
+    expect(summary.exit_status).toBe(1);
+    expect(summary.metadata.git_sha).toBe('abc123');
+    expect(summaries).toEqual([summary]);
+    expect(events.filter(e => e.action === 'fail').map(e => e.test)).toEqual(['TestPut', undefined]);
+    expect(fs.writeFile).not.toHaveBeenCalled();
+  });
+
+  it('should let a request override the runner options', async () => {
+    mockSpawn.mockImplementation(() => fakeGoProcess(run, 1));
+
+    await new Runner({ race: false, packages: ['./...'] }).run({ workspacePath: '/src/mod', options: { packages: ['./store'] } });
+
+    expect(mockSpawn.mock.calls[0][1]).toContain('./store');
+    expect(mockSpawn.mock.calls[0][1]).not.toContain('./...');
+  });
+
+  it('should reject when go test cannot run', async () => {
+    mockSpawn.mockImplementation(() => fakeGoProcess('flag provided but not defined: -bogus\n', 2));
+
+    await expect(new Runner().run({ workspacePath: '/src/mod' })).rejects.toThrow('exited with code 2');
+  });
+
+  it('should reject modes that produce no test run summary', async () => {
+    const runner = new Runner({ bench: '.' });
+
+    await expect(runner.run({ workspacePath: '/src/mod' })).rejects.toThrow('Runner does not support bench');
+    await expect(new Runner().run({ workspacePath: '/src/mod', options: { dry_run: true } })).rejects.toThrow('Runner does not support dry_run');
+    await expect(new Runner().run({ workspacePath: '/src/mod', options: { list: true } })).rejects.toThrow('Runner does not support list');
+    expect(mockSpawn).not.toHaveBeenCalled();
+  });
+
+  it('should reject a Go version matrix instead of returning one version\'s summary', async () => {
+    const summaries: RunSummary[] = [];
+    const runner = new Runner({ summaryHandlers: [{ handleSummary: summary => { summaries.push(summary); } }] });
+
+    await expect(runner.run({ workspacePath: '/src/mod', options: { go_versions: ['1.22', '1.23'] } }))
+      .rejects.toThrow('Runner does not support go_versions');
+    expect(mockSpawn).not.toHaveBeenCalled();
+    expect(summaries).toEqual([]);
+  });
+});
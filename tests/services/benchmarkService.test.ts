@@ -0,0 +1,136 @@
+/**
+ * Unit Tests for Benchmark Service
+ */
+
+import { BenchmarkService } from '../../src/services/benchmarkService';
+import { BenchResult } from '../../src/types/mcp';
+
+describe('BenchmarkService', () => {
+  let service: BenchmarkService;
+
+  const bench = (name: string, ns: number, bytes?: number, allocs?: number): BenchResult => ({
+    package: 'example.com/parse',
+    name,
+    procs: 8,
+    iterations: 1000,
+    ns_per_op: ns,
+    bytes_per_op: bytes,
+    allocs_per_op: allocs,
+  });
+
+  beforeEach(() => {
+    service = new BenchmarkService();
+  });
+
+  describe('parse', () => {
+    it('should parse ns/op, B/op, and allocs/op', () => {
+      const output = [
+        'goos: linux',
+        'goarch: amd64',
+        'pkg: example.com/parse',
+        'BenchmarkParse/small-8   \t  500000\t      2345 ns/op\t     512 B/op\t       7 allocs/op',
+        'BenchmarkParse/large-8   \t    1200\t   1034567 ns/op\t  204800 B/op\t    1500 allocs/op',
+        'PASS',
+        'ok  \texample.com/parse\t3.210s',
+      ].join('\n');
+
+      expect(service.parse(output, 'example.com/parse')).toEqual([
+        {
+          package: 'example.com/parse',
+          name: 'BenchmarkParse/small',
+          procs: 8,
+          iterations: 500000,
+          ns_per_op: 2345,
+          bytes_per_op: 512,
+          allocs_per_op: 7,
+        },
+        {
+          package: 'example.com/parse',
+          name: 'BenchmarkParse/large',
+          procs: 8,
+          iterations: 1200,
+          ns_per_op: 1034567,
+          bytes_per_op: 204800,
+          allocs_per_op: 1500,
+        },
+      ]);
+    });
+
+    it('should accept the b/op alias and keep other units as metrics', () => {
+      const [result] = service.parse('BenchmarkHash 200 0.52 ns/op 0 b/op 0 allocs/op 1923.08 MB/s');
+
+      expect(result.name).toBe('BenchmarkHash');
+      expect(result.procs).toBe(1);
+      expect(result.ns_per_op).toBe(0.52);
+      expect(result.bytes_per_op).toBe(0);
+      expect(result.allocs_per_op).toBe(0);
+      expect(result.metrics).toEqual({ 'MB/s': 1923.08 });
+    });
+
+    it('should ignore benchmark failure and log lines', () => {
+      const output = [
+        'BenchmarkBroken-8   \t--- FAIL: BenchmarkBroken-8',
+        '    parse_test.go:40: unexpected error',
+        'Benchmarks are fun',
+      ].join('\n');
+
+      expect(service.parse(output)).toEqual([]);
+    });
+  });
+
+  describe('compare', () => {
+    it('should report metrics that regressed beyond the threshold', () => {
+      const base = [bench('BenchmarkA', 1000, 100, 2), bench('BenchmarkB', 500, 64, 1)];
+      const current = [bench('BenchmarkA', 1150, 100, 2), bench('BenchmarkB', 520, 64, 1)];
+
+      expect(service.compare(base, current, 10)).toEqual([
+        {
+          package: 'example.com/parse',
+          name: 'BenchmarkA',
+          metric: 'ns_per_op',
+          base: 1000,
+          current: 1150,
+          delta_percent: 15,
+        },
+      ]);
+    });
+
+    it('should treat new allocations on a zero-alloc benchmark as a regression', () => {
+      const regressions = service.compare([bench('BenchmarkA', 10, 0, 0)], [bench('BenchmarkA', 10, 16, 1)], 50);
+
+      expect(regressions.map(r => [r.metric, r.delta_percent])).toEqual([
+        ['bytes_per_op', null],
+        ['allocs_per_op', null],
+      ]);
+    });
+
+    it('should not count improvements as regressions', () => {
+      expect(service.compare([bench('BenchmarkA', 1000)], [bench('BenchmarkA', 400)], 5)).toEqual([]);
+    });
+  });
+
+  describe('diff', () => {
+    it('should list added and removed benchmarks without failing on them', () => {
+      const comparison = service.diff(
+        [bench('BenchmarkA', 100), bench('BenchmarkOld', 100)],
+        [bench('BenchmarkA', 100), bench('BenchmarkNew', 999999)],
+        10
+      );
+
+      expect(comparison.added).toEqual(['example.com/parse BenchmarkNew']);
+      expect(comparison.removed).toEqual(['example.com/parse BenchmarkOld']);
+      expect(comparison.regressions).toEqual([]);
+      expect(comparison.deltas).toEqual([expect.objectContaining({ name: 'BenchmarkA', delta_percent: 0 })]);
+    });
+
+    it('should average repeated runs from -count', () => {
+      const comparison = service.diff(
+        [bench('BenchmarkA', 100), bench('BenchmarkA', 120)],
+        [bench('BenchmarkA', 110), bench('BenchmarkA', 154)],
+        10
+      );
+
+      expect(comparison.deltas[0]).toEqual(expect.objectContaining({ base: 110, current: 132, delta_percent: 20 }));
+    });
+  });
+});
@@ -0,0 +1,63 @@
+/**
+ * Unit Tests for Build Error Parser
+ */
+
+import { BuildErrorParser } from '../../src/services/buildErrorParser';
+
+describe('BuildErrorParser', () => {
+  let parser: BuildErrorParser;
+
+  beforeEach(() => {
+    parser = new BuildErrorParser();
+  });
+
+  describe('parse', () => {
+    it('should parse file, line, column, and message', () => {
+      const output = [
+        '# example.com/mod/calc [example.com/mod/calc.test]',
+        './calc.go:12:9: undefined: Sum',
+        'calc/format.go:7: missing return',
+        '',
+      ].join('\n');
+
+      expect(parser.parse(output)).toEqual([
+        { file: './calc.go', line: 12, column: 9, message: 'undefined: Sum' },
+        { file: 'calc/format.go', line: 7, column: undefined, message: 'missing return' },
+      ]);
+    });
+
+    it('should join tab-indented continuation lines onto the preceding error', () => {
+      const output = [
+        './calc.go:20:9: cannot use x (variable of type int) as string value in return statement',
+        '\thave (int)',
+        '\twant (string)',
+        './calc.go:30:2: too many errors',
+      ].join('\n');
+
+      const errors = parser.parse(output);
+
+      expect(errors[0].message).toBe(
+        'cannot use x (variable of type int) as string value in return statement\nhave (int)\nwant (string)'
+      );
+      expect(errors).toHaveLength(2);
+    });
+
+    it('should drop repeated diagnostics', () => {
+      const block = '# example.com/mod/dep\n./dep.go:3:1: syntax error: unexpected }\n';
+
+      expect(parser.parse(block + block)).toHaveLength(1);
+    });
+
+    it('should ignore output that is not a compiler error', () => {
+      expect(parser.parse('go: downloading example.com/lib v1.2.0\nFAIL\texample.com/mod [build failed]\n')).toEqual([]);
+    });
+  });
+
+  describe('format', () => {
+    it('should render the first line of an error as the compiler printed it', () => {
+      expect(parser.format({ file: './calc.go', line: 20, column: 9, message: 'cannot use x\nhave (int)' }))
+        .toBe('./calc.go:20:9: cannot use x');
+      expect(parser.format({ file: 'calc.go', line: 7, message: 'missing return' })).toBe('calc.go:7: missing return');
+    });
+  });
+});
@@ -0,0 +1,128 @@
+/**
+ * Unit Tests for Changed Line Coverage
+ */
+
+import { ChangedLineCoverageService } from '../../src/services/changedLineCoverage';
+import { GoCoverageProfile, LineDiff } from '../../src/types/mcp';
+
+describe('ChangedLineCoverageService', () => {
+  let service: ChangedLineCoverageService;
+
+  beforeEach(() => {
+    service = new ChangedLineCoverageService();
+  });
+
+  describe('parseDiff', () => {
+    it('should collect added lines with their new line numbers', () => {
+      const diff = [
+        'diff --git a/calc/calc.go b/calc/calc.go',
+        'index 3b18e51..a0f7b2c 100644',
+        '--- a/calc/calc.go',
+        '+++ b/calc/calc.go',
+        '@@ -4,3 +4,4 @@ package calc',
+        ' func Add(a, b int) int {',
+        '-\treturn a - b',
+        '+\tsum := a + b',
+        '+\treturn sum',
+        ' }',
+        '@@ -20,0 +21 @@ func Sub(a, b int) int {',
+        '+// Mul multiplies.',
+        'diff --git a/old.go b/old.go',
+        'deleted file mode 100644',
+        '--- a/old.go',
+        '+++ /dev/null',
+        '@@ -1,2 +0,0 @@',
+        '-package calc',
+        '-',
+        'diff --git a/run.sh b/run.sh',
+        'old mode 100644',
+        'new mode 100755',
+        '',
+      ].join('\n');
+
+      expect(service.parseDiff(diff)).toEqual({
+        files: {
+          'calc/calc.go': [
+            { line: 5, text: '\tsum := a + b' },
+            { line: 6, text: '\treturn sum' },
+            { line: 21, text: '// Mul multiplies.' },
+          ],
+        },
+      });
+    });
+  });
+
+  describe('changedLineCoverage', () => {
+    const source = [
+      'package calc',                   // 1
+      '',                               // 2
+      '// Add returns a + b.',          // 3
+      'func Add(a, b int) int {',       // 4
+      '\treturn a + b',                 // 5
+      '}',                              // 6
+      '',                               // 7
+      'func Div(a, b int) int {',       // 8
+      '\tif b == 0 {',                  // 9
+      '\t\treturn 0',                   // 10
+      '\t}',                            // 11
+      '\treturn a / b',                 // 12
+      '}',                              // 13
+    ];
+
+    const profile: GoCoverageProfile = {
+      mode: 'set',
+      files: {
+        'example.com/mod/calc/calc.go': [
+          { start_line: 4, start_col: 24, end_line: 6, end_col: 2, num_statements: 1, count: 1 },
+          { start_line: 8, start_col: 24, end_line: 9, end_col: 12, num_statements: 1, count: 1 },
+          { start_line: 9, start_col: 12, end_line: 11, end_col: 3, num_statements: 1, count: 0 },
+          { start_line: 12, start_col: 2, end_line: 12, end_col: 14, num_statements: 1, count: 1 },
+        ],
+      },
+    };
+
+    const changed = (...lines: number[]): LineDiff => ({
+      files: { 'calc/calc.go': lines.map(line => ({ line, text: source[line - 1] })) },
+    });
+
+    it('should count only changed lines that hold statements', () => {
+      const report = service.changedLineCoverage(profile, changed(3, 4, 5, 6, 9, 10, 11, 12, 13));
+
+      expect(report).toEqual({
+        covered_lines: 3,
+        executable_lines: 4,
+        percentage: 75,
+        files: [{ file: 'example.com/mod/calc/calc.go', covered: 3, executable: 4, uncovered_lines: [10] }],
+      });
+    });
+
+    it('should report full coverage when no changed line is executable', () => {
+      const report = service.changedLineCoverage(profile, {
+        files: {
+          ...changed(3, 4).files,
+          'calc/calc_test.go': [{ line: 10, text: '\tif Add(1, 2) != 3 {' }],
+          'README.md': [{ line: 1, text: '# calc' }],
+        },
+      });
+
+      expect(report).toEqual({ covered_lines: 0, executable_lines: 0, percentage: 100, files: [] });
+    });
+
+    it('should treat a line split between a hit and a missed block as uncovered', () => {
+      const split: GoCoverageProfile = {
+        mode: 'set',
+        files: {
+          'example.com/mod/calc/calc.go': [
+            { start_line: 5, start_col: 2, end_line: 5, end_col: 8, num_statements: 1, count: 1 },
+            { start_line: 5, start_col: 8, end_line: 5, end_col: 14, num_statements: 1, count: 0 },
+          ],
+        },
+      };
+
+      const report = service.changedLineCoverage(split, changed(5));
+
+      expect(report.executable_lines).toBe(1);
+      expect(report.files[0].uncovered_lines).toEqual([5]);
+    });
+  });
+});
@@ -0,0 +1,81 @@
+/**
+ * Unit Tests for Coverage Gate
+ */
+
+import * as fs from 'fs/promises';
+import { CoverageGate } from '../../src/services/coverageGate';
+import { CoverageProfileService } from '../../src/services/coverageProfileService';
+
+jest.mock('fs/promises');
+jest.mock('../../src/services/loggerService');
+
+describe('CoverageGate', () => {
+  const profiles = new CoverageProfileService();
+
+  // auth: 9/10 = 90%, store: 6/10 = 60%, store/mocks excluded, api: 7/10 = 70%
+  const profile = profiles.parseProfile(`mode: set
+example.com/mod/internal/auth/token.go:1.1,5.2 9 1
+example.com/mod/internal/auth/token.go:6.1,8.2 1 0
+example.com/mod/store/db.go:1.1,5.2 6 1
+example.com/mod/store/db.go:6.1,8.2 4 0
+example.com/mod/store/db_gen.go:1.1,9.2 50 0
+example.com/mod/store/mocks/db.go:1.1,9.2 20 0
+example.com/mod/api/server.go:1.1,5.2 7 1
+example.com/mod/api/server.go:6.1,8.2 3 0`);
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  it('should report packages below the default minimum', () => {
+    const gate = new CoverageGate({ default_minimum: 70, exclude: ['*_gen.go', 'mocks/'] });
+
+    const violations = gate.evaluate(profile);
+
+    expect(violations).toEqual([
+      { package: 'example.com/mod/store', actual: 60, required: 70, pattern: undefined },
+    ]);
+  });
+
+  it('should apply the most specific matching rule', () => {
+    const gate = new CoverageGate({
+      default_minimum: 50,
+      rules: [
+        { pattern: 'internal/**', minimum: 80 },
+        { pattern: 'internal/auth/**', minimum: 95 },
+      ],
+      exclude: ['*_gen.go', 'mocks/'],
+    });
+
+    const violations = gate.evaluate(profile);
+
+    expect(violations).toEqual([
+      { package: 'example.com/mod/internal/auth', actual: 90, required: 95, pattern: 'internal/auth/**' },
+    ]);
+  });
+
+  it('should count generated files when they are not excluded', () => {
+    const gate = new CoverageGate({ default_minimum: 50 });
+
+    const violations = gate.evaluate(profile);
+
+    expect(violations.map(v => v.package)).toEqual([
+      'example.com/mod/store',
+      'example.com/mod/store/mocks',
+    ]);
+  });
+
+  it('should load config from a JSON file', async () => {
+    (fs.readFile as jest.Mock).mockResolvedValue(JSON.stringify({ default_minimum: 95 }));
+
+    const gate = await CoverageGate.fromFile('/repo/coverage.json');
+
+    expect(gate.evaluate(profile)).toHaveLength(4);
+  });
+
+  it('should reject config without a default minimum', async () => {
+    (fs.readFile as jest.Mock).mockResolvedValue(JSON.stringify({ rules: [] }));
+
+    await expect(CoverageGate.fromFile('/repo/coverage.json')).rejects.toThrow(/default_minimum/);
+  });
+});
@@ -0,0 +1,180 @@
+/**
+ * Unit Tests for Coverage Profile Service
+ */
+
+import { CoverageProfileService, CoverageMergeError } from '../../src/services/coverageProfileService';
+
+jest.mock('../../src/services/loggerService');
+
+describe('CoverageProfileService', () => {
+  let service: CoverageProfileService;
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    service = new CoverageProfileService();
+  });
+
+  describe('parseProfile', () => {
+    it('should parse blocks grouped by file', () => {
+      const profile = service.parseProfile(`mode: count
+example.com/pkg/a.go:10.2,12.16 2 1
+example.com/pkg/a.go:15.2,17.10 3 0
+example.com/pkg/b.go:3.1,4.5 1 4`);
+
+      expect(profile.mode).toBe('count');
+      expect(Object.keys(profile.files)).toEqual(['example.com/pkg/a.go', 'example.com/pkg/b.go']);
+      expect(profile.files['example.com/pkg/a.go']).toHaveLength(2);
+      expect(profile.files['example.com/pkg/b.go'][0]).toEqual({
+        start_line: 3,
+        start_col: 1,
+        end_line: 4,
+        end_col: 5,
+        num_statements: 1,
+        count: 4,
+      });
+    });
+
+    it('should skip malformed lines', () => {
+      const profile = service.parseProfile(`mode: set
+not a coverage line
+a.go:1.1,2.2 1 1`);
+
+      expect(profile.files['a.go']).toHaveLength(1);
+    });
+  });
+
+  describe('formatProfile', () => {
+    it('should round-trip through parseProfile', () => {
+      const content = `mode: count
+a.go:1.1,2.2 1 3
+a.go:4.1,6.2 2 0
+b.go:1.1,1.20 1 1
+`;
+
+      expect(service.formatProfile(service.parseProfile(content))).toBe(content);
+    });
+  });
+
+  describe('filter', () => {
+    const profile = () => service.parseProfile(`mode: set
+example.com/mod/api/handler.go:10.2,12.16 2 1
+example.com/mod/api/v1/user.pb.go:3.1,4.5 8 0
+example.com/mod/vendor/github.com/pkg/errors/errors.go:1.1,2.2 5 0
+example.com/mod/internal/mocks/store.go:1.1,2.2 3 0
+example.com/mod/internal/mockserver/server.go:1.1,2.2 1 1`);
+
+    it('should drop files matching the exclude patterns', () => {
+      const filtered = service.filter(profile(), ['*.pb.go', 'vendor/', 'mocks/']);
+
+      expect(Object.keys(filtered.files)).toEqual([
+        'example.com/mod/api/handler.go',
+        'example.com/mod/internal/mockserver/server.go',
+      ]);
+      expect(filtered.mode).toBe('set');
+    });
+
+    it('should match against the import path form of file names', () => {
+      expect(Object.keys(service.filter(profile(), ['/api/**']).files)).toHaveLength(5);
+      expect(Object.keys(service.filter(profile(), ['/example.com/mod/api/**']).files)).toHaveLength(3);
+    });
+
+    it('should not modify the input profile', () => {
+      const input = profile();
+      service.filter(input, ['*.go']);
+
+      expect(Object.keys(input.files)).toHaveLength(5);
+    });
+  });
+
+  describe('mergeProfiles', () => {
+    it('should sum hit counts for identical blocks', () => {
+      const shard1 = service.parseProfile(`mode: count
+a.go:1.1,2.2 1 3
+a.go:4.1,6.2 2 0`);
+      const shard2 = service.parseProfile(`mode: count
+a.go:1.1,2.2 1 2
+a.go:4.1,6.2 2 5
+b.go:1.1,1.20 1 1`);
+
+      const merged = service.mergeProfiles(shard1, shard2);
+
+      expect(merged.files['a.go'].map(b => b.count)).toEqual([5, 5]);
+      expect(merged.files['b.go']).toHaveLength(1);
+    });
+
+    it('should keep set mode counts at most 1', () => {
+      const shard1 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1');
+      const shard2 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1');
+
+      const merged = service.mergeProfiles(shard1, shard2);
+
+      expect(merged.files['a.go'][0].count).toBe(1);
+    });
+
+    it('should throw when blocks disagree on end offsets', () => {
+      const shard1 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1');
+      const shard2 = service.parseProfile('mode: set\na.go:1.1,3.2 1 1');
+
+      expect(() => service.mergeProfiles(shard1, shard2)).toThrow(CoverageMergeError);
+    });
+
+    it('should throw when blocks overlap', () => {
+      const shard1 = service.parseProfile('mode: set\na.go:1.1,5.2 2 1');
+      const shard2 = service.parseProfile('mode: set\na.go:3.1,7.2 2 1');
+
+      expect(() => service.mergeProfiles(shard1, shard2)).toThrow(/Overlapping coverage blocks/);
+    });
+
+    it('should throw when modes differ', () => {
+      const shard1 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1');
+      const shard2 = service.parseProfile('mode: count\na.go:1.1,2.2 1 1');
+
+      expect(() => service.mergeProfiles(shard1, shard2)).toThrow(/different modes/);
+    });
+  });
+
+  describe('unionProfiles', () => {
+    it('should count a block covered in any profile as covered', () => {
+      const go121 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1\na.go:4.1,6.2 2 0');
+      const go122 = service.parseProfile('mode: set\na.go:1.1,2.2 1 0\na.go:4.1,6.2 2 1');
+
+      const { profile, conflicts } = service.unionProfiles(go121, go122);
+
+      expect(profile.files['a.go'].map(b => b.count)).toEqual([1, 1]);
+      expect(conflicts).toEqual([]);
+    });
+
+    it('should keep files that only some profiles have', () => {
+      const go121 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1');
+      const go122 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1\niter_go122.go:3.1,4.2 2 0');
+
+      const { profile } = service.unionProfiles(go121, go122);
+
+      expect(Object.keys(profile.files)).toEqual(['a.go', 'iter_go122.go']);
+      const coverage = service.statementCoverage(profile);
+      expect([coverage.covered, coverage.total]).toEqual([1, 3]);
+      expect(coverage.percentage).toBeCloseTo(33.33, 2);
+    });
+
+    it('should keep the most covered profile of a file whose blocks conflict', () => {
+      const go121 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1\nb.go:1.1,5.2 3 0');
+      const go122 = service.parseProfile('mode: set\na.go:1.1,2.2 1 1\nb.go:1.1,3.2 2 1\nb.go:4.1,5.2 1 0');
+
+      const { profile, conflicts } = service.unionProfiles(go121, go122);
+
+      expect(conflicts).toEqual(['b.go']);
+      expect(profile.files['b.go']).toEqual(go122.files['b.go']);
+      expect(profile.files['a.go']).toHaveLength(1);
+    });
+
+    it('should union profiles with different modes in set mode', () => {
+      const go121 = service.parseProfile('mode: count\na.go:1.1,2.2 1 7');
+      const go122 = service.parseProfile('mode: set\na.go:1.1,2.2 1 0');
+
+      const { profile } = service.unionProfiles(go121, go122);
+
+      expect(profile.mode).toBe('set');
+      expect(profile.files['a.go'][0].count).toBe(1);
+    });
+  });
+});
@@ -0,0 +1,105 @@
+/**
+ * Unit Tests for Go Framework Detector
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { GoFrameworkDetector } from '../../src/services/goFrameworkDetector';
+import { GoFramework } from '../../src/types/mcp';
+
+describe('GoFrameworkDetector', () => {
+  let detector: GoFrameworkDetector;
+  let root: string;
+
+  const writePackage = async (name: string, files: Record<string, string>): Promise<string> => {
+    const dir = path.join(root, name);
+    await fs.mkdir(dir, { recursive: true });
+    for (const [file, content] of Object.entries(files)) {
+      await fs.writeFile(path.join(dir, file), content);
+    }
+    return dir;
+  };
+
+  beforeEach(async () => {
+    detector = new GoFrameworkDetector();
+    root = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-detect-'));
+  });
+
+  afterEach(async () => {
+    await fs.rm(root, { recursive: true, force: true });
+  });
+
+  describe('detect', () => {
+    it('should detect Ginkgo suites from their imports', async () => {
+      const dir = await writePackage('api', {
+        'api.go': 'package api\n',
+        'api_suite_test.go': [
+          'package api_test',
+          '',
+          'import (',
+          '\t"testing"',
+          '',
+          '\t. "github.com/onsi/ginkgo/v2"',
+          '\t. "github.com/onsi/gomega"',
+          ')',
+          '',
+          'func TestAPI(t *testing.T) {',
+          '\tRegisterFailHandler(Fail)',
+          '\tRunSpecs(t, "API Suite")',
+          '}',
+        ].join('\n'),
+      });
+
+      await expect(detector.detect(dir)).resolves.toBe(GoFramework.Ginkgo);
+    });
+
+    it('should treat testify suites and plain tests as standard', async () => {
+      const dir = await writePackage('store', {
+        'store.go': 'package store\n',
+        'store_test.go': [
+          'package store',
+          '',
+          'import (',
+          '\t"testing"',
+          '',
+          '\t"github.com/stretchr/testify/suite"',
+          ')',
+          '',
+          'type StoreSuite struct{ suite.Suite }',
+          '',
+          'func TestStore(t *testing.T) { suite.Run(t, new(StoreSuite)) }',
+        ].join('\n'),
+      });
+
+      await expect(detector.detect(dir)).resolves.toBe(GoFramework.Standard);
+    });
+
+    it('should ignore Ginkgo references outside test files', async () => {
+      const dir = await writePackage('docs', {
+        'docs.go': 'package docs\n\n// Use RunSpecs( in suites built on "github.com/onsi/ginkgo/v2"\n',
+      });
+
+      await expect(detector.detect(dir)).resolves.toBe(GoFramework.Standard);
+    });
+
+    it('should fail for a missing directory', async () => {
+      await expect(detector.detect(path.join(root, 'missing'))).rejects.toThrow();
+    });
+  });
+
+  describe('resolve', () => {
+    it('should prefer the most specific override over detection', async () => {
+      const dir = await writePackage('e2e', {
+        'e2e_test.go': 'package e2e\n\nimport "github.com/onsi/ginkgo"\n',
+      });
+      const overrides = {
+        'example.com/**': GoFramework.Ginkgo,
+        'example.com/mod/e2e': GoFramework.Standard,
+      };
+
+      await expect(detector.resolve('example.com/mod/e2e', dir, overrides)).resolves.toBe(GoFramework.Standard);
+      await expect(detector.resolve('example.com/mod/e2e', dir)).resolves.toBe(GoFramework.Ginkgo);
+    });
+  });
+});
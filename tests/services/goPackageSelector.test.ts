@@ -0,0 +1,134 @@
+/**
+ * Unit Tests for Go Package Selector
+ */
+
+import { GoPackageSelector, GoPackageInfo } from '../../src/services/goPackageSelector';
+import * as child_process from 'child_process';
+
+jest.mock('child_process');
+jest.mock('../../src/services/loggerService');
+
+describe('GoPackageSelector', () => {
+  let selector: GoPackageSelector;
+  const mockExecFile = child_process.execFile as unknown as jest.Mock;
+  const moduleRoot = '/src/mod';
+
+  // util <- store <- api <- cmd; report imports util only from its tests
+  const packages: GoPackageInfo[] = [
+    { ImportPath: 'example.com/mod/util', Dir: '/src/mod/util' },
+    { ImportPath: 'example.com/mod/store', Dir: '/src/mod/store', Imports: ['example.com/mod/util', 'fmt'] },
+    { ImportPath: 'example.com/mod/api', Dir: '/src/mod/api', Imports: ['example.com/mod/store'] },
+    { ImportPath: 'example.com/mod/cmd', Dir: '/src/mod/cmd', Imports: ['example.com/mod/api'] },
+    { ImportPath: 'example.com/mod/report', Dir: '/src/mod/report', TestImports: ['example.com/mod/util'] },
+    { ImportPath: 'example.com/mod/report/html', Dir: '/src/mod/report/html', Imports: ['example.com/mod/report'] },
+  ];
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    selector = new GoPackageSelector();
+  });
+
+  describe('resolveAffected', () => {
+    it('should fan out to every transitive dependent', () => {
+      const affected = selector.resolveAffected(packages, ['util/strings.go'], moduleRoot);
+
+      expect(affected).toEqual([
+        'example.com/mod/api',
+        'example.com/mod/cmd',
+        'example.com/mod/report',
+        'example.com/mod/store',
+        'example.com/mod/util',
+      ]);
+    });
+
+    it('should not propagate through test-only imports', () => {
+      const affected = selector.resolveAffected(packages, ['util/strings.go'], moduleRoot);
+
+      expect(affected).not.toContain('example.com/mod/report/html');
+    });
+
+    it('should attribute non-Go files to the owning package', () => {
+      const affected = selector.resolveAffected(
+        packages,
+        ['api/testdata/fixture.json', 'report/templates/index.tmpl'],
+        moduleRoot
+      );
+
+      expect(affected).toEqual([
+        'example.com/mod/api',
+        'example.com/mod/cmd',
+        'example.com/mod/report',
+        'example.com/mod/report/html',
+      ]);
+    });
+
+    it('should prefer the most specific package directory', () => {
+      const affected = selector.resolveAffected(packages, ['report/html/page.go'], moduleRoot);
+
+      expect(affected).toEqual(['example.com/mod/report/html']);
+    });
+
+    it('should ignore files outside any package', () => {
+      const affected = selector.resolveAffected(packages, ['README.md', 'docs/design.txt'], moduleRoot);
+
+      expect(affected).toEqual([]);
+    });
+
+    it('should affect every package when a module or workspace file changes', () => {
+      const all = packages.map(p => p.ImportPath).sort();
+
+      expect(selector.resolveAffected(packages, ['README.md', 'go.mod'], moduleRoot)).toEqual(all);
+      expect(selector.resolveAffected(packages, ['go.sum'], moduleRoot)).toEqual(all);
+      expect(selector.resolveAffected(packages, ['../go.work'], moduleRoot)).toEqual(all);
+    });
+  });
+
+  describe('affectedPackages', () => {
+    it('should build the graph from go list output', async () => {
+      const stdout = packages.map(p => JSON.stringify(p, null, '\t')).join('\n');
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        callback(null, { stdout, stderr: '' });
+      });
+
+      const affected = await selector.affectedPackages(moduleRoot, ['store/db.go']);
+
+      expect(mockExecFile).toHaveBeenCalledWith(
+        'go',
+        ['list', '-e', '-json', './...'],
+        expect.objectContaining({ cwd: moduleRoot }),
+        expect.any(Function)
+      );
+      expect(affected).toEqual(['example.com/mod/api', 'example.com/mod/cmd', 'example.com/mod/store']);
+    });
+
+    it('should list packages with the build tags of the run', async () => {
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        callback(null, { stdout: '', stderr: '' });
+      });
+
+      await selector.affectedPackages(moduleRoot, ['store/db.go'], ['integration', 'e2e']);
+
+      expect(mockExecFile).toHaveBeenCalledWith(
+        'go',
+        ['list', '-e', '-json', '-tags=integration,e2e', './...'],
+        expect.objectContaining({ cwd: moduleRoot }),
+        expect.any(Function)
+      );
+    });
+
+    it('should list only the packages the patterns name', async () => {
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        callback(null, { stdout: '', stderr: '' });
+      });
+
+      await selector.listPackages(moduleRoot, [], ['./api/...', './store']);
+
+      expect(mockExecFile).toHaveBeenCalledWith(
+        'go',
+        ['list', '-e', '-json', './api/...', './store'],
+        expect.objectContaining({ cwd: moduleRoot }),
+        expect.any(Function)
+      );
+    });
+  });
+});
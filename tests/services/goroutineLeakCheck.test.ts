@@ -0,0 +1,93 @@
+/**
+ * Unit Tests for Goroutine Leak Check
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { GoroutineLeakCheck, LEAK_CHECK_FILE, renderLeakCheck } from '../../src/services/goroutineLeakCheck';
+import { logger } from '../../src/services/loggerService';
+
+jest.mock('../../src/services/loggerService');
+
+describe('GoroutineLeakCheck', () => {
+  const check = new GoroutineLeakCheck();
+
+  describe('prepare', () => {
+    let dir: string;
+
+    beforeEach(async () => {
+      jest.clearAllMocks();
+      dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-leaks-'));
+      for (const [pkg, source] of Object.entries({
+        worker: 'package worker\n\nfunc TestStart(t *testing.T) {}\n',
+        db: 'package db\n\nfunc TestMain(m *testing.M) { os.Exit(m.Run()) }\n',
+        api: 'package api\n\nfunc TestGet(t *testing.T) {}\n',
+      })) {
+        await fs.mkdir(path.join(dir, pkg));
+        await fs.writeFile(path.join(dir, pkg, `${pkg}_test.go`), source);
+      }
+    });
+
+    afterEach(async () => {
+      await fs.rm(dir, { recursive: true, force: true });
+    });
+
+    const info = (pkg: string) => ({
+      ImportPath: `example.com/mod/${pkg}`,
+      Name: pkg,
+      Dir: path.join(dir, pkg),
+      TestGoFiles: [`${pkg}_test.go`],
+    });
+
+    it('should overlay a generated TestMain on matching packages', async () => {
+      const outDir = path.join(dir, 'reports', 'leakcheck');
+      const overlay = await check.prepare([info('worker'), info('api')], ['**/worker'], outDir);
+
+      const { Replace } = JSON.parse(await fs.readFile(overlay!, 'utf-8'));
+      expect(Object.keys(Replace)).toEqual([path.join(dir, 'worker', LEAK_CHECK_FILE)]);
+      expect(await fs.readFile(Replace[path.join(dir, 'worker', LEAK_CHECK_FILE)], 'utf-8')).toBe(renderLeakCheck('worker'));
+    });
+
+    it('should skip packages with their own TestMain', async () => {
+      const overlay = await check.prepare([info('db')], ['**'], path.join(dir, 'reports', 'leakcheck'));
+
+      expect(overlay).toBeUndefined();
+      expect(logger.warn).toHaveBeenCalledWith(expect.stringContaining('example.com/mod/db defines its own TestMain'));
+    });
+  });
+
+  describe('parse', () => {
+    it('should parse each leaked goroutine of the report', () => {
+      const output = [
+        'PASS',
+        'alcs: goroutine leak check: 2 goroutines leaked',
+        'goroutine 21 [chan receive]:',
+        'example.com/mod/worker.(*Pool).loop(0xc000120000)',
+        '\t/src/worker/pool.go:42 +0x5d',
+        'created by example.com/mod/worker.Start in goroutine 7',
+        '\t/src/worker/pool.go:18 +0x8a',
+        '',
+        'goroutine 9 [select (no cases)]:',
+        'example.com/mod/worker.Start.func1()',
+        '\t/src/worker/pool.go:20 +0xf',
+        '',
+        'alcs: end goroutine leak check',
+        'FAIL\texample.com/mod/worker\t1.005s',
+      ].join('\n');
+
+      const leaks = check.parse(output);
+
+      expect(leaks.map(l => [l.goroutine, l.state, l.function, l.created_by])).toEqual([
+        [9, 'select (no cases)', 'example.com/mod/worker.Start.func1', undefined],
+        [21, 'chan receive', 'example.com/mod/worker.(*Pool).loop', 'example.com/mod/worker.Start'],
+      ]);
+      expect(leaks[1].stack).toContain('/src/worker/pool.go:42');
+      expect(leaks[1].stack).not.toContain('alcs:');
+    });
+
+    it('should find nothing in output without a report', () => {
+      expect(check.parse('PASS\nok  \texample.com/mod/worker\t0.01s\n')).toEqual([]);
+    });
+  });
+});
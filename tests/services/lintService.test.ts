@@ -0,0 +1,140 @@
+/**
+ * Unit Tests for Lint Service
+ */
+
+import { LintService } from '../../src/services/lintService';
+import * as child_process from 'child_process';
+
+jest.mock('child_process');
+jest.mock('../../src/services/loggerService');
+
+describe('LintService', () => {
+  let service: LintService;
+  const mockExecFile = child_process.execFile as unknown as jest.Mock;
+
+  const report = JSON.stringify({
+    Issues: [
+      {
+        FromLinter: 'errcheck',
+        Text: 'Error return value of `f.Close` is not checked',
+        Severity: '',
+        Pos: { Filename: 'store/db.go', Line: 42, Column: 12 },
+      },
+      {
+        FromLinter: 'gocritic',
+        Text: 'ifElseChain: rewrite if-else to switch statement',
+        Severity: 'warning',
+        Pos: { Filename: 'api/handler.go', Line: 7, Column: 2 },
+      },
+      {
+        FromLinter: 'godot',
+        Text: 'Comment should end in a period',
+        Severity: 'info',
+        Pos: { Filename: 'api/handler.go', Line: 3 },
+      },
+    ],
+    Report: { Linters: [] },
+  });
+
+  const exitWith = (code: number, stdout: string, stderr: string = '') => {
+    mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+      if (code === 0) {
+        callback(null, { stdout, stderr });
+        return;
+      }
+      const error: any = new Error(`Command failed: golangci-lint`);
+      error.code = code;
+      error.stdout = stdout;
+      error.stderr = stderr;
+      callback(error);
+    });
+  };
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    service = new LintService();
+  });
+
+  describe('run', () => {
+    it('should parse findings when golangci-lint exits 1 for issues', async () => {
+      exitWith(1, report);
+
+      const findings = await service.run('/src/mod');
+
+      expect(findings).toEqual([
+        {
+          linter: 'errcheck',
+          file: 'store/db.go',
+          line: 42,
+          col: 12,
+          message: 'Error return value of `f.Close` is not checked',
+          severity: 'error',
+        },
+        {
+          linter: 'gocritic',
+          file: 'api/handler.go',
+          line: 7,
+          col: 2,
+          message: 'ifElseChain: rewrite if-else to switch statement',
+          severity: 'warning',
+        },
+        {
+          linter: 'godot',
+          file: 'api/handler.go',
+          line: 3,
+          col: 0,
+          message: 'Comment should end in a period',
+          severity: 'info',
+        },
+      ]);
+    });
+
+    it('should return no findings for a clean run', async () => {
+      exitWith(0, JSON.stringify({ Issues: null, Report: {} }));
+
+      await expect(service.run('/src/mod')).resolves.toEqual([]);
+    });
+
+    it('should pass through the config file', async () => {
+      exitWith(0, JSON.stringify({ Issues: [] }));
+
+      await service.run('/src/mod', { config_path: '/src/mod/.golangci.yml', timeout_seconds: 120 });
+
+      expect(mockExecFile).toHaveBeenCalledWith(
+        'golangci-lint',
+        ['run', '--out-format', 'json', '--config', '/src/mod/.golangci.yml', '--timeout=120s', './...'],
+        expect.objectContaining({ cwd: '/src/mod' }),
+        expect.any(Function)
+      );
+    });
+
+    it('should treat other exit codes as a tool failure', async () => {
+      exitWith(3, '', 'level=error msg="Timeout exceeded: try increasing it"');
+
+      await expect(service.run('/src/mod')).rejects.toThrow(/exit 3.*Timeout exceeded/);
+    });
+
+    it('should treat exit 1 without a report as a tool failure', async () => {
+      exitWith(1, '', 'panic: runtime error: invalid memory address');
+
+      await expect(service.run('/src/mod')).rejects.toThrow(/panic: runtime error/);
+    });
+
+    it('should reject output that is not a JSON report', async () => {
+      exitWith(1, 'level=error msg="can\'t load config"');
+
+      await expect(service.run('/src/mod')).rejects.toThrow('Unreadable golangci-lint report');
+    });
+  });
+
+  describe('atOrAbove', () => {
+    it('should keep findings at or above the threshold', async () => {
+      exitWith(1, report);
+      const findings = await service.run('/src/mod');
+
+      expect(service.atOrAbove(findings, 'warning').map(f => f.linter)).toEqual(['errcheck', 'gocritic']);
+      expect(service.atOrAbove(findings, 'error').map(f => f.linter)).toEqual(['errcheck']);
+      expect(service.atOrAbove(findings, 'info')).toHaveLength(3);
+    });
+  });
+});
@@ -0,0 +1,69 @@
+/**
+ * Unit Tests for Metrics Service
+ */
+
+import { Registry } from 'prom-client';
+import { MetricsService } from '../../src/services/metricsService';
+
+jest.mock('../../src/services/loggerService');
+
+describe('MetricsService', () => {
+  let registry: Registry;
+  let metrics: MetricsService;
+
+  beforeEach(() => {
+    registry = new Registry();
+    metrics = new MetricsService(registry);
+  });
+
+  /**
+   * Read the current value of a metric from the isolated registry
+   */
+  async function valueOf(name: string, labels: Record<string, string> = {}): Promise<number | undefined> {
+    const metric = await registry.getSingleMetric(name)!.get();
+    const sample = metric.values.find(v =>
+      Object.entries(labels).every(([key, value]) => v.labels[key] === value)
+    );
+    return sample?.value;
+  }
+
+  describe('test executions', () => {
+    it('should track executions in flight', async () => {
+      metrics.recordTestExecutionStart();
+      metrics.recordTestExecutionStart();
+      metrics.recordTestExecutionEnd();
+
+      expect(await valueOf('alcs_test_executions_in_flight')).toBe(1);
+    });
+
+    it('should record totals, failures, and last run coverage', async () => {
+      metrics.recordTestExecution('go_testing', 'go', 12, 75, true);
+      metrics.recordTestExecution('go_testing', 'go', 8, 62.5, false, 'test_failed');
+
+      expect(await valueOf('alcs_test_executions_total', { framework: 'go_testing' })).toBe(2);
+      expect(await valueOf('alcs_test_executions_failed_total', { reason: 'test_failed' })).toBe(1);
+      expect(await valueOf('alcs_test_last_run_coverage_percentage', { framework: 'go_testing' })).toBe(62.5);
+    });
+  });
+
+  describe('docker containers', () => {
+    it('should track active containers and spawn failures', async () => {
+      metrics.recordContainerStarted();
+      metrics.recordContainerStarted();
+      metrics.recordContainerRemoved();
+      metrics.recordDockerFailure('spawn_failed');
+
+      expect(await valueOf('alcs_docker_containers_active')).toBe(1);
+      expect(await valueOf('alcs_docker_container_failures_total', { reason: 'spawn_failed' })).toBe(1);
+    });
+  });
+
+  it('should expose metrics in Prometheus text format', async () => {
+    metrics.recordTestExecutionStart();
+
+    const output = await metrics.getMetrics();
+
+    expect(output).toContain('# TYPE alcs_test_executions_in_flight gauge');
+    expect(output).toContain('alcs_test_executions_in_flight 1');
+  });
+});
@@ -0,0 +1,84 @@
+/**
+ * Unit Tests for Race Report Parser
+ */
+
+import { RaceReportParser } from '../../src/services/raceReportParser';
+
+describe('RaceReportParser', () => {
+  let parser: RaceReportParser;
+
+  const raceReport = [
+    '=== RUN   TestCounter',
+    '==================',
+    'WARNING: DATA RACE',
+    'Write at 0x00c0000a4010 by goroutine 8:',
+    '  example.com/calc.(*Counter).Inc()',
+    '      /src/calc/counter.go:12 +0x44',
+    '',
+    'Previous read at 0x00c0000a4010 by goroutine 7:',
+    '  example.com/calc.(*Counter).Value()',
+    '      /src/calc/counter.go:18 +0x3a',
+    '',
+    'Goroutine 8 (running) created at:',
+    '  example.com/calc.TestCounter()',
+    '      /src/calc/counter_test.go:10 +0x88',
+    '==================',
+    '    counter_test.go:22: expected 2, got 1',
+    '    testing.go:1398: race detected during execution of test',
+    '--- FAIL: TestCounter (0.00s)',
+  ].join('\n');
+
+  beforeEach(() => {
+    parser = new RaceReportParser();
+  });
+
+  it('should extract the address and both conflicting stacks', () => {
+    const races = parser.parse(raceReport);
+
+    expect(races).toHaveLength(1);
+    expect(races[0].address).toBe('0x00c0000a4010');
+    expect(races[0].current).toEqual({
+      operation: 'write',
+      goroutine: '8',
+      stack: 'example.com/calc.(*Counter).Inc()\n    /src/calc/counter.go:12 +0x44',
+    });
+    expect(races[0].previous).toEqual({
+      operation: 'read',
+      goroutine: '7',
+      stack: 'example.com/calc.(*Counter).Value()\n    /src/calc/counter.go:18 +0x3a',
+    });
+  });
+
+  it('should keep the raw block without surrounding test output', () => {
+    const [race] = parser.parse(raceReport);
+
+    expect(race.raw.startsWith('WARNING: DATA RACE')).toBe(true);
+    expect(race.raw).toContain('Goroutine 8 (running) created at:');
+    expect(race.raw).not.toContain('expected 2, got 1');
+  });
+
+  it('should parse multiple reports and main goroutine accesses', () => {
+    const second = [
+      '==================',
+      'WARNING: DATA RACE',
+      'Read at 0x00c000014088 by main goroutine:',
+      '  main.main()',
+      '      /src/main.go:9 +0x1c',
+      '',
+      'Previous atomic write at 0x00c000014088 by goroutine 6:',
+      '  sync/atomic.AddInt64()',
+      '      /usr/local/go/src/runtime/race_amd64.s:289 +0xb',
+      '==================',
+    ].join('\n');
+
+    const races = parser.parse(raceReport + '\n' + second);
+
+    expect(races).toHaveLength(2);
+    expect(races[1].current.goroutine).toBe('main');
+    expect(races[1].previous.operation).toBe('atomic write');
+  });
+
+  it('should return nothing for output without races', () => {
+    expect(parser.parse('--- PASS: TestAdd (0.00s)\n')).toEqual([]);
+  });
+});
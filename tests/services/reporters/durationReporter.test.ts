@@ -0,0 +1,80 @@
+/**
+ * Unit Tests for Duration Reporter
+ */
+
+import { buildDurationReport, DurationReporter } from '../../../src/services/reporters/durationReporter';
+import { TestCaseResult } from '../../../src/types/mcp';
+
+describe('DurationReporter', () => {
+  const test = (pkg: string, name: string, duration_ms: number): TestCaseResult => ({
+    package: `example.com/${pkg}`,
+    name,
+    status: 'passed',
+    duration_ms,
+    output: '',
+  });
+
+  const tests: TestCaseResult[] = [
+    test('store', 'TestCheckout', 4000),
+    test('store', 'TestCheckout/eu', 3000),
+    test('store', 'TestRefund', 2500),
+    test('calc', 'TestAdd', 500),
+    test('calc', 'TestDivide', 2500),
+    { ...test('api', '[build failed]', 0), status: 'error' },
+  ];
+
+  describe('buildDurationReport', () => {
+    it('should list the slowest top-level tests, longest first', () => {
+      const report = buildDurationReport(tests, 3);
+
+      expect(report.total_ms).toBe(9500);
+      expect(report.slowest).toEqual([
+        { package: 'example.com/store', name: 'TestCheckout', status: 'passed', duration_ms: 4000 },
+        { package: 'example.com/calc', name: 'TestDivide', status: 'passed', duration_ms: 2500 },
+        { package: 'example.com/store', name: 'TestRefund', status: 'passed', duration_ms: 2500 },
+      ]);
+    });
+
+    it('should total test time per package', () => {
+      const report = buildDurationReport(tests);
+
+      expect(report.slowest).toHaveLength(4);
+      expect(report.packages).toEqual([
+        { package: 'example.com/store', duration_ms: 6500, tests: 2, percentage: (6500 / 9500) * 100 },
+        { package: 'example.com/calc', duration_ms: 3000, tests: 2, percentage: (3000 / 9500) * 100 },
+      ]);
+    });
+  });
+
+  describe('renderText', () => {
+    it('should render the slowest tests and package totals as tables', () => {
+      const text = new DurationReporter().renderText(buildDurationReport(tests, 2));
+
+      expect(text).toBe([
+        'Slowest 2 tests:',
+        'Duration  Package            Test',
+        '   4.00s  example.com/store  TestCheckout',
+        '   2.50s  example.com/calc   TestDivide',
+        '',
+        'Test time by package (9.50s total):',
+        'Duration  Share  Tests  Package',
+        '   6.50s  68.4%      2  example.com/store',
+        '   3.00s  31.6%      2  example.com/calc',
+        '',
+      ].join('\n'));
+    });
+
+    it('should color headers and failed tests without changing the layout', () => {
+      const report = buildDurationReport([...tests, { ...test('calc', 'TestFlaky', 3000), status: 'failed' as const }], 2);
+      const colored = new DurationReporter().renderText(report, { color: true });
+
+      expect(colored.split('\n')[1]).toBe('\x1b[1mDuration  Package            Test\x1b[22m');
+      expect(colored.split('\n')[3]).toBe('\x1b[31m   3.00s  example.com/calc   TestFlaky\x1b[39m');
+      expect(colored.replace(/\x1b\[\d+m/g, '')).toBe(new DurationReporter().renderText(report));
+    });
+
+    it('should note a run without durations', () => {
+      expect(new DurationReporter().renderText(buildDurationReport([]))).toBe('No test durations recorded\n');
+    });
+  });
+});
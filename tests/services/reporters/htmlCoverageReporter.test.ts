@@ -0,0 +1,136 @@
+/**
+ * Unit Tests for HTML Coverage Reporter
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { HtmlCoverageReporter, SourceFS } from '../../../src/services/reporters/htmlCoverageReporter';
+import { createRunSummary } from '../../../src/services/reporters/jsonSummaryReporter';
+import { GoCoverageProfile } from '../../../src/types/mcp';
+
+jest.mock('../../../src/services/loggerService');
+
+describe('HtmlCoverageReporter', () => {
+  let reporter: HtmlCoverageReporter;
+
+  const source = [
+    'package calc',
+    '',
+    'func Abs(x int) int {',
+    '\tif x < 0 { return -x }',
+    '\treturn x',
+    '}',
+  ].join('\n') + '\n';
+
+  // Line 4 holds two blocks: the condition (hit) and the early return (missed)
+  const profile: GoCoverageProfile = {
+    mode: 'set',
+    files: {
+      'example.com/calc/abs.go': [
+        { start_line: 3, start_col: 21, end_line: 4, end_col: 11, num_statements: 1, count: 1 },
+        { start_line: 4, start_col: 11, end_line: 4, end_col: 24, num_statements: 1, count: 0 },
+        { start_line: 5, start_col: 2, end_line: 6, end_col: 2, num_statements: 1, count: 1 },
+      ],
+      'example.com/util/strings.go': [
+        { start_line: 3, start_col: 29, end_line: 5, end_col: 2, num_statements: 2, count: 0 },
+      ],
+    },
+  };
+
+  beforeEach(() => {
+    reporter = new HtmlCoverageReporter();
+  });
+
+  describe('classifyLines', () => {
+    it('should mark lines split between hit and missed blocks as partial', () => {
+      const lines = source.replace(/\n$/, '').split('\n');
+
+      expect(reporter.classifyLines(lines, profile.files['example.com/calc/abs.go'])).toEqual([
+        'none',
+        'none',
+        'covered',
+        'partial',
+        'covered',
+        'covered',
+      ]);
+    });
+
+    it('should mark lines whose only block was missed as uncovered', () => {
+      const lines = ['package util', '', 'func Upper(s string) string {', '\treturn s', '}'];
+
+      expect(reporter.classifyLines(lines, profile.files['example.com/util/strings.go'])).toEqual([
+        'none',
+        'none',
+        'uncovered',
+        'uncovered',
+        'uncovered',
+      ]);
+    });
+  });
+
+  describe('renderFile', () => {
+    it('should highlight covered and uncovered spans within a line', () => {
+      const html = reporter.renderFile('example.com/calc/abs.go', profile.files['example.com/calc/abs.go'], source);
+
+      expect(html).toContain(
+        '<span class="line partial" data-n="4"><span class="cov">\tif x &lt; 0 </span>' +
+        '<span class="uncov">{ return -x }</span>\n</span>'
+      );
+      expect(html).toContain('abs.go: 66.7%');
+    });
+
+    it('should note missing sources instead of failing', () => {
+      const html = reporter.renderFile('example.com/gone.go', [], undefined);
+
+      expect(html).toContain('Source not available.');
+    });
+  });
+
+  describe('writeHtmlCoverage', () => {
+    let dir: string;
+
+    beforeEach(async () => {
+      dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-html-'));
+    });
+
+    afterEach(async () => {
+      await fs.rm(dir, { recursive: true, force: true });
+    });
+
+    it('should write an index sorted by package coverage and one page per file', async () => {
+      const sources: SourceFS = {
+        readFile: async name => {
+          if (name === 'example.com/calc/abs.go') {
+            return source;
+          }
+          throw new Error('not found');
+        },
+      };
+
+      const summary = createRunSummary({
+        framework: 'go_testing',
+        startedAt: new Date('2026-01-05T10:00:00.000Z'),
+        finishedAt: new Date('2026-01-05T10:00:02.000Z'),
+        gitSha: '0123456789abcdef0123456789abcdef01234567',
+        tests: [{ package: 'example.com/calc', name: 'TestAbs', status: 'passed', duration_ms: 1, output: '' }],
+        coveragePercentage: 40,
+        coverageProfile: profile,
+        success: true,
+      });
+
+      const indexPath = await reporter.writeHtmlCoverage(dir, summary, sources);
+      const index = await fs.readFile(indexPath, 'utf-8');
+
+      expect(index).toContain('<h1>Coverage: 40.0%</h1>');
+      expect(index).toContain('Run passed: 1/1 tests passed at 0123456789ab');
+      expect(index.indexOf('example.com/util')).toBeLessThan(index.indexOf('example.com/calc'));
+      expect(index).toContain('href="example.com_calc_abs.go.html"');
+      expect((await fs.readdir(dir)).sort()).toEqual([
+        'example.com_calc_abs.go.html',
+        'example.com_util_strings.go.html',
+        'index.html',
+      ]);
+    });
+  });
+});
@@ -0,0 +1,90 @@
+/**
+ * Unit Tests for JSON Summary Reporter
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import {
+  JsonSummaryReporter,
+  RUN_SUMMARY_SCHEMA_VERSION,
+  createRunSummary,
+} from '../../../src/services/reporters/jsonSummaryReporter';
+import { TestCaseResult } from '../../../src/types/mcp';
+
+jest.mock('../../../src/services/loggerService');
+
+describe('JsonSummaryReporter', () => {
+  let reporter: JsonSummaryReporter;
+
+  const tests: TestCaseResult[] = [
+    { package: 'example.com/calc', name: 'TestAdd', status: 'passed', duration_ms: 12, output: '' },
+    { package: 'example.com/calc', name: 'TestDivide', status: 'failed', duration_ms: 3, output: 'boom\n' },
+    { package: 'example.com/calc', name: 'TestHang', status: 'timed_out', duration_ms: 60000, output: '' },
+    { package: 'example.com/calc', name: 'TestSlow', status: 'skipped', duration_ms: 0, output: '' },
+  ];
+
+  const summary = createRunSummary({
+    framework: 'go_testing',
+    startedAt: new Date('2026-01-05T10:00:00.000Z'),
+    finishedAt: new Date('2026-01-05T10:01:30.500Z'),
+    goVersion: 'go1.23.4',
+    gitSha: '0123456789abcdef0123456789abcdef01234567',
+    tests,
+    coveragePercentage: 72.5,
+    lintFindings: 3,
+    success: false,
+  });
+
+  beforeEach(() => {
+    reporter = new JsonSummaryReporter();
+  });
+
+  describe('createRunSummary', () => {
+    it('should stamp the schema version and run metadata', () => {
+      expect(summary.schemaVersion).toBe(RUN_SUMMARY_SCHEMA_VERSION);
+      expect(summary.metadata).toEqual({
+        framework: 'go_testing',
+        started_at: '2026-01-05T10:00:00.000Z',
+        finished_at: '2026-01-05T10:01:30.500Z',
+        duration_ms: 90500,
+        go_version: 'go1.23.4',
+        git_sha: '0123456789abcdef0123456789abcdef01234567',
+      });
+    });
+
+    it('should total tests, coverage, lint findings, and exit status', () => {
+      expect(summary.tests).toHaveLength(4);
+      expect(summary.totals).toEqual({ passed: 1, failed: 2, skipped: 1, cancelled: 0, not_run: 0, panicked: 0, total: 4 });
+      expect(summary.coverage.percentage).toBe(72.5);
+      expect(summary.lint.findings_count).toBe(3);
+      expect(summary.exit_status).toBe(1);
+    });
+  });
+
+  describe('writeJson', () => {
+    it('should write the summary as a single JSON document', () => {
+      let written = '';
+      reporter.writeJson({ write: (text: string) => { written += text; } }, summary);
+
+      const parsed = JSON.parse(written);
+      expect(parsed.schemaVersion).toBe(RUN_SUMMARY_SCHEMA_VERSION);
+      expect(parsed.tests[1]).toEqual(tests[1]);
+      expect(written.endsWith('}\n')).toBe(true);
+    });
+  });
+
+  describe('writeReport', () => {
+    it('should create the parent directory and write the file', async () => {
+      const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-summary-'));
+      try {
+        const outputPath = path.join(dir, 'reports', 'summary.json');
+        await reporter.writeReport(outputPath, summary);
+
+        expect(JSON.parse(await fs.readFile(outputPath, 'utf-8'))).toEqual(JSON.parse(JSON.stringify(summary)));
+      } finally {
+        await fs.rm(dir, { recursive: true, force: true });
+      }
+    });
+  });
+});
@@ -0,0 +1,134 @@
+/**
+ * Unit Tests for JUnit Reporter
+ */
+
+import { JUnitReporter } from '../../../src/services/reporters/junitReporter';
+import { createRunSummary } from '../../../src/services/reporters/jsonSummaryReporter';
+import { RunSummary, TestCaseResult } from '../../../src/types/mcp';
+
+jest.mock('../../../src/services/loggerService');
+
+describe('JUnitReporter', () => {
+  let reporter: JUnitReporter;
+
+  const results: TestCaseResult[] = [
+    { package: 'example.com/calc', name: 'TestAdd', status: 'passed', duration_ms: 12, output: '' },
+    {
+      package: 'example.com/calc',
+      name: 'TestDivide/by_zero',
+      status: 'failed',
+      duration_ms: 3,
+      output: 'calc_test.go:20: expected <error>\n',
+      failure_message: 'expected "error"',
+    },
+    {
+      package: 'example.com/calc',
+      name: 'TestSlow',
+      status: 'skipped',
+      duration_ms: 0,
+      output: '',
+      failure_message: 'short mode',
+    },
+    {
+      package: 'example.com/broken',
+      name: '[build failed]',
+      status: 'error',
+      duration_ms: 0,
+      output: './broken.go:3:1: syntax error',
+      failure_message: 'Build failed',
+    },
+  ];
+
+  const summarize = (tests: TestCaseResult[]): RunSummary => createRunSummary({
+    framework: 'go_testing',
+    startedAt: new Date('2026-01-05T10:00:00.000Z'),
+    finishedAt: new Date('2026-01-05T10:00:02.000Z'),
+    tests,
+    coveragePercentage: 0,
+    success: false,
+  });
+
+  beforeEach(() => {
+    reporter = new JUnitReporter();
+  });
+
+  it('should emit aggregate counts on the testsuite', () => {
+    const xml = reporter.generate(summarize(results));
+
+    expect(xml).toContain('<testsuite name="alcs" tests="4" failures="1" errors="1" skipped="1" time="0.015"');
+  });
+
+  it('should stamp the suite with the run start time', () => {
+    const xml = reporter.generate(summarize(results));
+
+    expect(xml).toContain('timestamp="2026-01-05T10:00:00.000Z"');
+  });
+
+  it('should use the package as classname and include durations', () => {
+    const xml = reporter.generate(summarize(results));
+
+    expect(xml).toContain('<testcase classname="example.com/calc" name="TestAdd" time="0.012"/>');
+  });
+
+  it('should map failures, skips, and errors to their elements', () => {
+    const xml = reporter.generate(summarize(results));
+
+    expect(xml).toContain('<failure message="expected &quot;error&quot;" type="failure">calc_test.go:20: expected &lt;error&gt;\n</failure>');
+    expect(xml).toContain('<skipped message="short mode"/>');
+    expect(xml).toContain('<error message="Build failed" type="build">./broken.go:3:1: syntax error</error>');
+    expect(xml).not.toMatch(/<failure[^>]*>\.\/broken\.go/);
+  });
+
+  it('should report data races alongside assertion failures', () => {
+    const xml = reporter.generate(summarize([{
+      package: 'example.com/calc',
+      name: 'TestCounter',
+      status: 'failed',
+      duration_ms: 5,
+      output: '',
+      failure_message: 'expected 2, got 1',
+      data_races: [{
+        address: '0x00c0000a4010',
+        current: { operation: 'write', goroutine: '8', stack: '' },
+        previous: { operation: 'read', goroutine: '7', stack: '' },
+        raw: 'WARNING: DATA RACE',
+      }],
+    }]));
+
+    expect(xml).toContain('<failure message="expected 2, got 1" type="failure"></failure>');
+    expect(xml).toContain('<error message="Data race at 0x00c0000a4010" type="data_race">WARNING: DATA RACE</error>');
+  });
+
+  it('should report panics as their own error type and unrun tests as skipped', () => {
+    const xml = reporter.generate(summarize([
+      {
+        package: 'example.com/calc',
+        name: 'TestIndex',
+        status: 'error',
+        duration_ms: 0,
+        output: 'panic: runtime error: index out of range [3] with length 3\n\ngoroutine 7 [running]:\n',
+        failure_message: 'panic: runtime error: index out of range [3] with length 3',
+        panic: { message: 'runtime error: index out of range [3] with length 3', stack: 'goroutine 7 [running]:\n' },
+      },
+      {
+        package: 'example.com/calc',
+        name: 'TestAfter',
+        status: 'not_run',
+        duration_ms: 0,
+        output: '',
+        failure_message: 'Not run: TestIndex panicked',
+      },
+    ]));
+
+    expect(xml).toContain('errors="1" skipped="1"');
+    expect(xml).toContain('<error message="panic: runtime error: index out of range [3] with length 3" type="panic">');
+    expect(xml).toContain('<skipped message="Not run: TestIndex panicked"/>');
+  });
+
+  it('should produce an empty suite for no results', () => {
+    const xml = reporter.generate(summarize([]));
+
+    expect(xml).toContain('tests="0" failures="0" errors="0" skipped="0"');
+    expect(xml.trim().endsWith('</testsuite>')).toBe(true);
+  });
+});
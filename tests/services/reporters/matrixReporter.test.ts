@@ -0,0 +1,125 @@
+/**
+ * Unit Tests for Matrix Reporter
+ */
+
+import { buildMatrixReport, MatrixReporter, MatrixRunResult, unifyCoverage } from '../../../src/services/reporters/matrixReporter';
+import { coverageProfileService } from '../../../src/services/coverageProfileService';
+import { TestCaseResult, TestCaseStatus, TestExecutionResult } from '../../../src/types/mcp';
+
+jest.mock('../../../src/services/loggerService');
+
+describe('MatrixReporter', () => {
+  const run = (goVersion: string, statuses: Record<string, TestCaseStatus>): MatrixRunResult => {
+    const testCases: TestCaseResult[] = Object.entries(statuses).map(([name, status]) => ({
+      package: 'example.com/calc',
+      name,
+      status,
+      duration_ms: 1,
+      output: '',
+    }));
+    const failed = testCases.filter(c => c.status === 'failed').length;
+    const result: TestExecutionResult = {
+      success: failed === 0,
+      passed_tests: testCases.length - failed,
+      failed_tests: failed,
+      total_tests: testCases.length,
+      coverage_percentage: 0,
+      duration_ms: 0,
+      failures: [],
+      stdout: '',
+      stderr: '',
+      test_cases: testCases,
+    };
+    return { goVersion, image: `golang:${goVersion}-alpine`, result };
+  };
+
+  const report = buildMatrixReport([
+    run('1.21', { TestAdd: 'passed', TestIter: 'passed' }),
+    run('1.22', { TestAdd: 'passed', TestIter: 'passed', TestRange: 'passed' }),
+    run('1.23', { TestAdd: 'passed', TestIter: 'failed', TestRange: 'passed' }),
+  ]);
+
+  describe('buildMatrixReport', () => {
+    it('should record each test outcome per version', () => {
+      expect(report.go_versions).toEqual(['1.21', '1.22', '1.23']);
+      expect(report.runs.map(r => `${r.image} ${r.success}`)).toEqual([
+        'golang:1.21-alpine true',
+        'golang:1.22-alpine true',
+        'golang:1.23-alpine false',
+      ]);
+      expect(report.tests.map(t => t.name)).toEqual(['TestAdd', 'TestIter', 'TestRange']);
+      expect(report.tests[1].statuses).toEqual({ '1.21': 'passed', '1.22': 'passed', '1.23': 'failed' });
+    });
+
+    it('should flag tests whose outcome differs or that are missing on a version', () => {
+      expect(report.tests.map(t => t.version_specific)).toEqual([false, true, true]);
+    });
+  });
+
+  describe('unifyCoverage', () => {
+    const withProfile = (goVersion: string, profile?: string): MatrixRunResult => ({
+      ...run(goVersion, { TestAdd: 'passed' }),
+      coverageProfile: profile ? coverageProfileService.parseProfile(profile) : undefined,
+    });
+
+    it('should union the versions that produced a profile', () => {
+      const unified = unifyCoverage([
+        withProfile('1.21', 'mode: set\nexample.com/calc/calc.go:3.1,5.2 2 1\nexample.com/calc/calc.go:7.1,9.2 2 0'),
+        withProfile('1.22', 'mode: set\nexample.com/calc/calc.go:3.1,5.2 2 0\nexample.com/calc/calc.go:7.1,9.2 2 1\nexample.com/calc/iter.go:1.1,2.2 1 0'),
+        withProfile('1.23'),
+      ])!;
+
+      expect(unified.coverage).toEqual({
+        percentage: 80,
+        statements_covered: 4,
+        statements_total: 5,
+        missing_versions: ['1.23'],
+        version_specific_files: ['example.com/calc/iter.go'],
+        conflicting_files: [],
+      });
+      expect(Object.keys(unified.profile.files)).toEqual(['example.com/calc/calc.go', 'example.com/calc/iter.go']);
+    });
+
+    it('should return undefined when no version produced a profile', () => {
+      expect(unifyCoverage([withProfile('1.22'), withProfile('1.23')])).toBeUndefined();
+    });
+  });
+
+  describe('renderText', () => {
+    it('should list only version-specific tests', () => {
+      const text = new MatrixReporter().renderText(report);
+
+      expect(text.split('\n')).toEqual([
+        'Test                        go1.21  go1.22  go1.23',
+        'example.com/calc TestIter   passed  passed  failed',
+        'example.com/calc TestRange  -       passed  passed',
+        '',
+      ]);
+    });
+
+    it('should say so when every version agrees', () => {
+      const agreeing = buildMatrixReport([run('1.22', { TestAdd: 'passed' }), run('1.23', { TestAdd: 'passed' })]);
+
+      expect(new MatrixReporter().renderText(agreeing)).toBe('All tests behave the same on Go 1.22, 1.23\n');
+    });
+
+    it('should end with the unified and per-version coverage', () => {
+      const runs = [run('1.22', { TestAdd: 'passed' }), run('1.23', { TestAdd: 'passed' })];
+      runs[0].result.coverage_percentage = 60;
+      const covered = buildMatrixReport(runs, {
+        percentage: 80,
+        statements_covered: 4,
+        statements_total: 5,
+        missing_versions: ['1.23'],
+        version_specific_files: [],
+        conflicting_files: [],
+      });
+
+      expect(new MatrixReporter().renderText(covered).split('\n')).toEqual([
+        'All tests behave the same on Go 1.22, 1.23',
+        'Unified coverage: 80.0% of 5 statements (go1.22 60.0%, go1.23 0.0%; no profile from go1.23)',
+        '',
+      ]);
+    });
+  });
+});
@@ -0,0 +1,43 @@
+/**
+ * Unit Tests for Progress Reporter
+ */
+
+import { ProgressReporter } from '../../../src/services/reporters/progressReporter';
+
+describe('ProgressReporter', () => {
+  const output = (isTTY: boolean) => {
+    const writes: string[] = [];
+    return { writes, out: { isTTY, write: (text: string) => writes.push(text) } };
+  };
+
+  const run = (reporter: ProgressReporter) => {
+    reporter.handleEvent({ action: 'run', package: 'example.com/calc', test: 'TestAdd', stream: 'stdout' });
+    reporter.handleEvent({ action: 'pass', package: 'example.com/calc', test: 'TestAdd', stream: 'stdout' });
+    reporter.handleEvent({ action: 'fail', package: 'example.com/calc', elapsed_ms: 20, stream: 'stdout' });
+  };
+
+  it('should print plain package lines when output is not a terminal', () => {
+    const { writes, out } = output(false);
+
+    run(new ProgressReporter(out));
+
+    expect(writes).toEqual(['FAIL example.com/calc 0.02s\n']);
+  });
+
+  it('should color verdicts and totals on a terminal', () => {
+    const { writes, out } = output(true);
+
+    run(new ProgressReporter(out, 'always'));
+
+    expect(writes).toContain('\x1b[31mFAIL\x1b[39m example.com/calc 0.02s\n');
+    expect(writes[writes.length - 1]).toBe('\r\x1b[K\x1b[32m1 passed\x1b[39m, 0 failed, 0 skipped');
+  });
+
+  it('should not color with color set to never', () => {
+    const { writes, out } = output(true);
+
+    run(new ProgressReporter(out, 'never'));
+
+    expect(writes).toContain('FAIL example.com/calc 0.02s\n');
+  });
+});
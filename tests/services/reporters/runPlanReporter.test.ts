@@ -0,0 +1,52 @@
+/**
+ * Unit Tests for Run Plan Reporter
+ */
+
+import { RunPlanReporter } from '../../../src/services/reporters/runPlanReporter';
+import { RunPlan } from '../../../src/types/mcp';
+
+jest.mock('../../../src/services/loggerService');
+
+describe('RunPlanReporter', () => {
+  const plan: RunPlan = {
+    since_ref: 'origin/main',
+    packages: ['example.com/calc', 'example.com/store'],
+    cached: ['example.com/calc'],
+    ginkgo_suites: [],
+    processes: [
+      { packages: ['example.com/store'], tests: ['TestMigrate'], estimated_ms: 9000 },
+      { packages: ['example.com/store'], tests: ['TestQuery', 'TestScan'] },
+    ],
+    concurrency: 2,
+    go_versions: ['1.22', '1.23'],
+    images: ['golang:1.22-alpine', 'golang:1.23-alpine'],
+    containers: 4,
+    estimated_duration_ms: 18000,
+    unestimated_processes: 1,
+  };
+
+  describe('renderText', () => {
+    it('should render the selection, processes, containers, and estimate', () => {
+      expect(new RunPlanReporter().renderText(plan)).toBe([
+        '2 packages selected (affected since origin/main):',
+        '  example.com/calc',
+        '  example.com/store',
+        '1 unchanged, reusing cached results:',
+        '  example.com/calc',
+        '2 go test processes, up to 2 at once:',
+        '  example.com/store -run TestMigrate         ~9.00s',
+        '  example.com/store -run TestQuery,TestScan  no history',
+        '4 containers from golang:1.22-alpine, golang:1.23-alpine',
+        'Repeated on Go 1.22, 1.23',
+        'Estimated duration: 18.00s (1 processes without history not included)',
+        '',
+      ].join('\n'));
+    });
+
+    it('should say when there is nothing to estimate from', () => {
+      const text = new RunPlanReporter().renderText({ ...plan, estimated_duration_ms: undefined });
+
+      expect(text).toContain('Estimated duration: unknown (no timings_path)');
+    });
+  });
+});
@@ -0,0 +1,103 @@
+/**
+ * Unit Tests for Run Summary Diff
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { createRunSummary } from '../../../src/services/reporters/jsonSummaryReporter';
+import { diffRunSummaries, readRunSummary } from '../../../src/services/reporters/runSummaryDiff';
+import { RunSummary, TestCaseResult, TestCaseStatus } from '../../../src/types/mcp';
+
+describe('runSummaryDiff', () => {
+  const testCase = (name: string, status: TestCaseStatus, pkg: string = 'example.com/calc'): TestCaseResult => ({
+    package: pkg,
+    name,
+    status,
+    duration_ms: 1,
+    output: '',
+  });
+
+  const summaryOf = (tests: TestCaseResult[]): RunSummary => createRunSummary({
+    framework: 'go_testing',
+    startedAt: new Date('2026-01-05T10:00:00.000Z'),
+    finishedAt: new Date('2026-01-05T10:00:01.000Z'),
+    tests,
+    coveragePercentage: 0,
+    success: !tests.some(t => t.status !== 'passed' && t.status !== 'skipped'),
+  });
+
+  describe('diffRunSummaries', () => {
+    const baseline = summaryOf([
+      testCase('TestAdd', 'passed'),
+      testCase('TestDivide', 'passed'),
+      testCase('TestFlaky', 'failed'),
+      testCase('TestBroken', 'error'),
+      testCase('TestSkipped', 'skipped'),
+      testCase('TestOldName', 'passed'),
+    ]);
+
+    const current = summaryOf([
+      testCase('TestAdd', 'passed'),
+      testCase('TestDivide', 'failed'),
+      testCase('TestFlaky', 'passed'),
+      testCase('TestBroken', 'timed_out'),
+      testCase('TestSkipped', 'failed'),
+      testCase('TestNewName', 'passed'),
+    ]);
+
+    const names = (entries: { name: string }[]) => entries.map(e => e.name);
+
+    it('should classify newly failing, fixed, and still failing tests', () => {
+      const diff = diffRunSummaries(baseline, current);
+
+      expect(names(diff.newly_failing)).toEqual(['TestDivide', 'TestSkipped']);
+      expect(names(diff.newly_passing)).toEqual(['TestFlaky']);
+      expect(diff.still_failing).toEqual([{
+        package: 'example.com/calc',
+        name: 'TestBroken',
+        baseline_status: 'error',
+        current_status: 'timed_out',
+      }]);
+    });
+
+    it('should report a renamed test as removed and added', () => {
+      const diff = diffRunSummaries(baseline, current);
+
+      expect(diff.removed).toEqual([{ package: 'example.com/calc', name: 'TestOldName', baseline_status: 'passed' }]);
+      expect(diff.added).toEqual([{ package: 'example.com/calc', name: 'TestNewName', current_status: 'passed' }]);
+    });
+
+    it('should match tests by package as well as name', () => {
+      const diff = diffRunSummaries(
+        summaryOf([testCase('TestAdd', 'passed', 'example.com/a')]),
+        summaryOf([testCase('TestAdd', 'failed', 'example.com/b')])
+      );
+
+      expect(diff.newly_failing).toEqual([]);
+      expect(diff.added.map(e => e.package)).toEqual(['example.com/b']);
+      expect(diff.removed.map(e => e.package)).toEqual(['example.com/a']);
+    });
+  });
+
+  describe('readRunSummary', () => {
+    it('should read a stored summary and reject other JSON', async () => {
+      const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-diff-'));
+      try {
+        const summaryPath = path.join(dir, 'summary.json');
+        await fs.writeFile(summaryPath, JSON.stringify(storedSummary()), 'utf-8');
+        await expect(readRunSummary(summaryPath)).resolves.toEqual(storedSummary());
+
+        const otherPath = path.join(dir, 'other.json');
+        await fs.writeFile(otherPath, JSON.stringify({ Issues: [] }), 'utf-8');
+        await expect(readRunSummary(otherPath)).rejects.toThrow('is not a run summary');
+      } finally {
+        await fs.rm(dir, { recursive: true, force: true });
+      }
+    });
+
+    function storedSummary(): RunSummary {
+      return JSON.parse(JSON.stringify(summaryOf([testCase('TestAdd', 'passed')])));
+    }
+  });
+});
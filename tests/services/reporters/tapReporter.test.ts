@@ -0,0 +1,188 @@
+/**
+ * Unit Tests for TAP Reporter
+ */
+
+import { TapReporter } from '../../../src/services/reporters/tapReporter';
+import { createRunSummary } from '../../../src/services/reporters/jsonSummaryReporter';
+import { RunSummary, TestCaseResult } from '../../../src/types/mcp';
+
+jest.mock('../../../src/services/loggerService');
+
+interface ParsedTestPoint {
+  ok: boolean;
+  number: number;
+  description: string;
+  directive?: 'SKIP' | 'TODO';
+  reason?: string;
+  diagnostics?: string[];
+}
+
+/**
+ * Strict reader for the TAP version 13 grammar, failing on any line a
+ * consumer would treat as garbage: version line, plan, numbered test points,
+ * and YAML blocks indented under the test point they belong to.
+ */
+function parseTap(tap: string): { plan: number; points: ParsedTestPoint[] } {
+  expect(tap.endsWith('\n')).toBe(true);
+  const lines = tap.slice(0, -1).split('\n');
+
+  expect(lines[0]).toBe('TAP version 13');
+  const plan = lines[1].match(/^1\.\.(\d+)$/);
+  expect(plan).not.toBeNull();
+
+  const points: ParsedTestPoint[] = [];
+  for (let i = 2; i < lines.length; i++) {
+    const line = lines[i];
+    if (line === '  ---') {
+      const point = points[points.length - 1];
+      expect(point).toBeDefined();
+      expect(point.diagnostics).toBeUndefined();
+      point.diagnostics = [];
+      for (i++; lines[i] !== '  ...'; i++) {
+        expect(i).toBeLessThan(lines.length);
+        expect(lines[i]).toMatch(/^ {2}\S|^ {4}|^$/);
+        point.diagnostics.push(lines[i]);
+      }
+      continue;
+    }
+
+    const match = line.match(/^(not )?ok (\d+)(?: - ((?:[^\\#]|\\.)*?))?(?: # (SKIP|TODO)(?: (.*))?)?$/);
+    if (!match) {
+      throw new Error(`Not a TAP line: ${JSON.stringify(line)}`);
+    }
+    points.push({
+      ok: !match[1],
+      number: Number(match[2]),
+      description: (match[3] || '').replace(/\\(.)/g, '$1'),
+      directive: match[4] as ParsedTestPoint['directive'],
+      reason: match[5],
+    });
+  }
+
+  expect(points.map(p => p.number)).toEqual(points.map((_, i) => i + 1));
+  expect(points).toHaveLength(Number(plan![1]));
+  return { plan: Number(plan![1]), points };
+}
+
+describe('TapReporter', () => {
+  let reporter: TapReporter;
+
+  const results: TestCaseResult[] = [
+    { package: 'example.com/calc', name: 'TestAdd', status: 'passed', duration_ms: 12, output: '' },
+    {
+      package: 'example.com/calc',
+      name: 'TestDivide/by_zero',
+      status: 'failed',
+      duration_ms: 3,
+      output: '    calc_test.go:20: expected <error>\n    calc_test.go:21: got "nil"\n',
+      failure_message: 'expected "error"',
+      attempts: 2,
+    },
+    {
+      package: 'example.com/calc',
+      name: 'TestSlow',
+      status: 'skipped',
+      duration_ms: 0,
+      output: '',
+      failure_message: 'short mode',
+    },
+    { package: 'example.com/calc', name: 'TestLater', status: 'not_run', duration_ms: 0, output: '' },
+    {
+      package: 'example.com/calc',
+      name: 'TestHang',
+      status: 'timed_out',
+      duration_ms: 30000,
+      output: 'panic: test timed out',
+      goroutine_dump: 'goroutine 7 [chan receive]:\nexample.com/calc.TestHang()',
+    },
+  ];
+
+  const summarize = (tests: TestCaseResult[]): RunSummary => createRunSummary({
+    framework: 'go_testing',
+    startedAt: new Date('2026-01-05T10:00:00.000Z'),
+    finishedAt: new Date('2026-01-05T10:00:02.000Z'),
+    tests,
+    coveragePercentage: 0,
+    success: false,
+  });
+
+  beforeEach(() => {
+    reporter = new TapReporter();
+  });
+
+  it('should emit a valid TAP version 13 stream', () => {
+    const { plan, points } = parseTap(reporter.generate(summarize(results)));
+
+    expect(plan).toBe(5);
+    expect(points.map(p => [p.ok, p.description, p.directive])).toEqual([
+      [true, 'example.com/calc TestAdd', undefined],
+      [false, 'example.com/calc TestDivide/by_zero', undefined],
+      [true, 'example.com/calc TestSlow', 'SKIP'],
+      [false, 'example.com/calc TestLater', 'TODO'],
+      [false, 'example.com/calc TestHang', undefined],
+    ]);
+    expect(points[2].reason).toBe('short mode');
+    expect(points[3].reason).toBe('not run');
+  });
+
+  it('should attach failure diagnostics as YAML', () => {
+    const tap = reporter.generate(summarize(results));
+
+    expect(tap).toContain([
+      'not ok 2 - example.com/calc TestDivide/by_zero',
+      '  ---',
+      '  message: "expected \\"error\\""',
+      '  severity: fail',
+      '  package: "example.com/calc"',
+      '  duration_ms: 3',
+      '  attempts: 2',
+      '  output: |2-',
+      '        calc_test.go:20: expected <error>',
+      '        calc_test.go:21: got "nil"',
+      '  ...',
+    ].join('\n'));
+  });
+
+  it('should report timeouts with the goroutine dump', () => {
+    const { points } = parseTap(reporter.generate(summarize(results)));
+
+    expect(points[4].diagnostics).toEqual([
+      '  message: "timed_out"',
+      '  severity: timeout',
+      '  package: "example.com/calc"',
+      '  duration_ms: 30000',
+      '  output: |2-',
+      '    goroutine 7 [chan receive]:',
+      '    example.com/calc.TestHang()',
+    ]);
+  });
+
+  it('should escape descriptions so they cannot start a directive', () => {
+    const { points } = parseTap(reporter.generate(summarize([
+      { package: 'example.com/calc', name: 'TestParse/#00\\n', status: 'passed', duration_ms: 1, output: '' },
+      { package: 'example.com/calc', name: 'TestParse/line\nbreak', status: 'passed', duration_ms: 1, output: '' },
+    ])));
+
+    expect(points[0].description).toBe('example.com/calc TestParse/#00\\n');
+    expect(points[0].directive).toBeUndefined();
+    expect(points[1].description).toBe('example.com/calc TestParse/line break');
+  });
+
+  it('should keep control characters out of the YAML', () => {
+    const { points } = parseTap(reporter.generate(summarize([{
+      package: 'example.com/calc',
+      name: 'TestColor',
+      status: 'failed',
+      duration_ms: 1,
+      output: '\x1b[31mFAIL\x1b[0m\r\nbell\x07\n',
+      failure_message: 'failed',
+    }])));
+
+    expect(points[0].diagnostics).toContain('    FAIL');
+    expect(points[0].diagnostics).toContain('    bell');
+  });
+
+  it('should emit an empty plan for a run without tests', () => {
+    expect(reporter.generate(summarize([]))).toBe('TAP version 13\n1..0\n');
+  });
+});
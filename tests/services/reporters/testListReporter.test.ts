@@ -0,0 +1,85 @@
+/**
+ * Unit Tests for Test List Reporter
+ */
+
+import { collapseSubtests, TestListReporter } from '../../../src/services/reporters/testListReporter';
+import { TestCaseResult, TestCaseStatus } from '../../../src/types/mcp';
+
+describe('TestListReporter', () => {
+  const test = (name: string, status: TestCaseStatus = 'passed', duration_ms: number = 10): TestCaseResult => ({
+    package: 'example.com/calc',
+    name,
+    status,
+    duration_ms,
+    output: '',
+  });
+
+  const cases = (parent: string, count: number): TestCaseResult[] =>
+    Array.from({ length: count }, (_, i) => test(`${parent}/case_${i}`));
+
+  describe('collapseSubtests', () => {
+    it('should fold subtests that all passed into their parent', () => {
+      const entries = collapseSubtests([test('TestAdd', 'passed', 1200), ...cases('TestAdd', 142), test('TestSub')]);
+
+      expect(entries).toEqual([
+        { package: 'example.com/calc', name: 'TestAdd', status: 'passed', duration_ms: 1200, passed_subtests: 142, skipped_subtests: undefined },
+        { package: 'example.com/calc', name: 'TestSub', status: 'passed', duration_ms: 10, passed_subtests: undefined, skipped_subtests: undefined },
+      ]);
+    });
+
+    it('should list only the innermost failing subtests of a failing parent', () => {
+      const entries = collapseSubtests([
+        test('TestParse', 'failed'),
+        ...cases('TestParse', 3),
+        test('TestParse/errors', 'failed'),
+        test('TestParse/errors/empty', 'failed'),
+        test('TestParse/errors/nil'),
+        test('TestParse/unicode', 'failed'),
+      ]);
+
+      expect(entries.map(e => e.name)).toEqual(['TestParse/errors/empty', 'TestParse/unicode']);
+    });
+
+    it('should keep a parent that failed outside its subtests', () => {
+      const entries = collapseSubtests([test('TestSetup', 'failed'), ...cases('TestSetup', 2)]);
+
+      expect(entries).toEqual([{ package: 'example.com/calc', name: 'TestSetup', status: 'failed', duration_ms: 10 }]);
+    });
+  });
+
+  describe('renderText', () => {
+    const tests = [
+      test('TestAdd', 'passed', 1200),
+      ...cases('TestAdd', 142),
+      test('TestAdd/skipped', 'skipped', 0),
+      test('TestDiv', 'failed', 30),
+      test('TestDiv/by_zero', 'failed', 20),
+      test('TestDiv/ok', 'passed'),
+      { ...test('TestServe'), package: 'example.com/api' },
+    ];
+
+    it('should render collapsed results grouped by package', () => {
+      expect(new TestListReporter().renderText(tests, { collapseSubtests: true })).toBe([
+        'example.com/api',
+        '  ok      TestServe (0.01s)',
+        'example.com/calc',
+        '  ok      TestAdd: 142 subtests passed, 1 skipped (1.20s)',
+        '  FAIL    TestDiv/by_zero (0.02s)',
+        '',
+      ].join('\n'));
+    });
+
+    it('should color status labels without shifting the columns', () => {
+      const text = new TestListReporter().renderText(tests, { collapseSubtests: true, color: true });
+
+      expect(text).toContain('  \x1b[31mFAIL\x1b[39m    TestDiv/by_zero (0.02s)');
+      expect(text.replace(/\x1b\[\d+m/g, '')).toBe(new TestListReporter().renderText(tests, { collapseSubtests: true }));
+    });
+
+    it('should list every subtest without collapsing', () => {
+      const text = new TestListReporter().renderText(tests);
+
+      expect(text.split('\n').filter(line => line.includes('TestAdd/case_'))).toHaveLength(142);
+    });
+  });
+});
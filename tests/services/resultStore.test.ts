@@ -0,0 +1,64 @@
+/**
+ * Unit Tests for Result Store
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { FileResultStore, validateRunId } from '../../src/services/resultStore';
+import { createRunSummary } from '../../src/services/reporters/jsonSummaryReporter';
+
+jest.mock('../../src/services/loggerService');
+
+describe('FileResultStore', () => {
+  let dir: string;
+  let store: FileResultStore;
+
+  const summary = createRunSummary({
+    framework: 'go_testing',
+    startedAt: new Date('2026-01-05T10:00:00.000Z'),
+    finishedAt: new Date('2026-01-05T10:00:02.000Z'),
+    runId: 'run-1',
+    tests: [{ package: 'example.com/mod/api', name: 'TestGet', status: 'passed', duration_ms: 10, output: '' }],
+    coveragePercentage: 80,
+    success: true,
+  });
+
+  beforeEach(async () => {
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-results-'));
+    store = new FileResultStore(path.join(dir, 'runs'));
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  it('should read back a stored summary', async () => {
+    await store.put('run-1', summary);
+
+    await expect(store.get('run-1')).resolves.toEqual(summary);
+  });
+
+  it('should list runs oldest first', async () => {
+    await store.put('b', summary);
+    await store.put('a', summary);
+    await fs.utimes(path.join(dir, 'runs', 'b.json'), new Date(1000), new Date(1000));
+    await fs.utimes(path.join(dir, 'runs', 'a.json'), new Date(2000), new Date(2000));
+
+    await expect(store.list()).resolves.toEqual(['b', 'a']);
+  });
+
+  it('should list nothing before the first run is stored', async () => {
+    await expect(store.list()).resolves.toEqual([]);
+  });
+
+  it('should reject a missing run', async () => {
+    await expect(store.get('run-404')).rejects.toThrow('No run run-404');
+  });
+
+  it('should reject run IDs that could leave the directory', () => {
+    expect(() => validateRunId('../etc/passwd')).toThrow('Invalid run ID');
+    expect(() => validateRunId('..')).toThrow('Invalid run ID');
+    expect(() => validateRunId('2026-01-05T10-00-00_abc123')).not.toThrow();
+  });
+});
@@ -0,0 +1,140 @@
+/**
+ * Unit Tests for Run Checkpoint
+ */
+
+import {
+  checkpointSelection,
+  isUnfinishedCheckpoint,
+  resumableCheckpoint,
+  RunCheckpointer,
+} from '../../src/services/runCheckpoint';
+import { ResultStore } from '../../src/services/resultStore';
+import { createRunSummary } from '../../src/services/reporters/jsonSummaryReporter';
+import { coverageProfileService } from '../../src/services/coverageProfileService';
+import { RunSummary, TestCaseResult } from '../../src/types/mcp';
+
+jest.mock('../../src/services/loggerService');
+
+describe('RunCheckpoint', () => {
+  let stored: { runId: string; summary: RunSummary }[];
+  let store: ResultStore;
+
+  const render = (tests: TestCaseResult[]) => createRunSummary({
+    framework: 'go_testing',
+    startedAt: new Date('2026-01-05T10:00:00.000Z'),
+    finishedAt: new Date('2026-01-05T10:00:02.000Z'),
+    tests,
+    coveragePercentage: 0,
+    success: false,
+  });
+
+  const passed = (pkg: string, name: string): TestCaseResult => ({ package: pkg, name, status: 'passed', duration_ms: 1, output: '' });
+  const failed = (pkg: string, name: string): TestCaseResult => ({ ...passed(pkg, name), status: 'failed' });
+
+  const checkpointed = (runs: any[], tests: TestCaseResult[] = []): RunSummary => ({
+    ...render(tests),
+    checkpoint: { selection: { packages: ['./...'], tags: ['integration'] }, runs },
+  });
+
+  beforeEach(() => {
+    stored = [];
+    store = {
+      // Copied, as a real store serializes on put
+      put: jest.fn(async (runId: string, summary: RunSummary) => { stored.push({ runId, summary: JSON.parse(JSON.stringify(summary)) }); }),
+      get: jest.fn(),
+      list: jest.fn(),
+    };
+    jest.spyOn(coverageProfileService, 'readProfile').mockRejectedValue(new Error('ENOENT'));
+  });
+
+  afterEach(() => {
+    jest.restoreAllMocks();
+  });
+
+  describe('RunCheckpointer', () => {
+    it('should store the schedule before anything runs, then every finished process', async () => {
+      const checkpointer = new RunCheckpointer(store, 'run-1', checkpointSelection(['./...'], {}), render);
+
+      const runs = await checkpointer.schedule(async () => [{ pkg: 'example.com/a' }, { pkg: 'example.com/b', tests: ['TestX'] }]);
+      await checkpointer.complete(runs[1], [failed('example.com/b', 'TestX')], [], '/tmp/coverage-1.out');
+      await checkpointer.complete(runs[0], [passed('example.com/a', 'TestA')], [], '/tmp/coverage-0.out');
+
+      expect(stored.map(s => s.runId)).toEqual(['run-1', 'run-1', 'run-1']);
+      expect(stored.map(s => s.summary.checkpoint!.runs!.map(r => r.status))).toEqual([
+        ['pending', 'pending'],
+        ['pending', 'failed'],
+        ['passed', 'failed'],
+      ]);
+      expect(stored[0].summary.checkpoint!.runs![1]).toEqual({ package: 'example.com/b', tests: ['TestX'], status: 'pending' });
+      expect(stored[2].summary.tests.map(t => t.name)).toEqual(['TestX', 'TestA']);
+      expect(checkpointer.finished).toBe(true);
+    });
+
+    it('should not track processes scheduled after the first call', async () => {
+      const checkpointer = new RunCheckpointer(store, 'run-1', checkpointSelection(['./...'], {}), render);
+      await checkpointer.schedule(async () => [{ pkg: 'example.com/a' }]);
+
+      const rerun = await checkpointer.schedule(async () => [{ pkg: 'example.com/a' }]);
+      await checkpointer.complete(rerun[0], [passed('example.com/a', 'TestA')], [], '/tmp/coverage-0.out');
+
+      expect(stored).toHaveLength(1);
+      expect(checkpointer.finished).toBe(false);
+    });
+
+    it('should resume with the pending part of the stored schedule', async () => {
+      const resumed = checkpointed([
+        { package: 'example.com/a', status: 'passed' },
+        { package: 'example.com/b', tests: ['TestX'], status: 'pending' },
+        { package: 'example.com/b', tests: ['TestY'], status: 'failed' },
+      ], [passed('example.com/a', 'TestA'), failed('example.com/b', 'TestY')]);
+      const plan = jest.fn();
+      const checkpointer = new RunCheckpointer(store, 'run-1', resumed.checkpoint!.selection, render, resumed);
+
+      const runs = await checkpointer.schedule(plan);
+
+      expect(plan).not.toHaveBeenCalled();
+      expect(runs).toEqual([{ pkg: 'example.com/b', tests: ['TestX'] }]);
+      expect(checkpointer.restoredTests.map(t => [t.name, t.resumed])).toEqual([['TestA', true], ['TestY', true]]);
+
+      await checkpointer.complete(runs[0], [passed('example.com/b', 'TestX')], [], '/tmp/coverage-0.out');
+      expect(stored[0].summary.tests.map(t => t.name)).toEqual(['TestA', 'TestY', 'TestX']);
+      expect(checkpointer.finished).toBe(true);
+    });
+
+    it('should keep running when the store cannot be reached', async () => {
+      (store.put as jest.Mock).mockRejectedValue(new Error('connect ECONNREFUSED'));
+      const checkpointer = new RunCheckpointer(store, 'run-1', checkpointSelection(['./...'], {}), render);
+
+      await expect(checkpointer.schedule(async () => [{ pkg: 'example.com/a' }])).resolves.toHaveLength(1);
+    });
+  });
+
+  describe('resumableCheckpoint', () => {
+    it('should accept the options the run was checkpointed with', () => {
+      const summary = checkpointed([]);
+
+      expect(resumableCheckpoint(summary, 'run-1', { tags: ['integration'] })).toBe(summary.checkpoint);
+    });
+
+    it('should refuse options that select other tests', () => {
+      expect(() => resumableCheckpoint(checkpointed([]), 'run-1', {})).toThrow(
+        'Cannot resume run run-1: tags differs from its checkpoint'
+      );
+      expect(() => resumableCheckpoint(checkpointed([]), 'run-1', { tags: ['integration'], shuffle: 'on' })).toThrow(
+        'Cannot resume run run-1: shuffle differs from its checkpoint'
+      );
+    });
+
+    it('should refuse a run stored without a checkpoint', () => {
+      expect(() => resumableCheckpoint(render([]), 'run-1', {})).toThrow('Run run-1 has no checkpoint to resume');
+    });
+  });
+
+  describe('isUnfinishedCheckpoint', () => {
+    it('should tell unfinished checkpoints from finished and plain runs', () => {
+      expect(isUnfinishedCheckpoint(checkpointed([{ package: 'example.com/a', status: 'pending' }]))).toBe(true);
+      expect(isUnfinishedCheckpoint(checkpointed([{ package: 'example.com/a', status: 'failed' }]))).toBe(false);
+      expect(isUnfinishedCheckpoint(render([]))).toBe(false);
+    });
+  });
+});
@@ -0,0 +1,64 @@
+/**
+ * Unit Tests for Run Log
+ */
+
+import { RunLog, currentRunLog, createRunLog } from '../../src/services/runLog';
+import { logger } from '../../src/services/loggerService';
+
+jest.mock('../../src/services/loggerService');
+
+describe('RunLog', () => {
+  const sink = { log: jest.fn() };
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  it('should stamp every entry with the run fields', () => {
+    const log = new RunLog({ run_id: 'run-1' }, sink as any);
+
+    log.debug('Cache miss', { package: 'example.com/mod/a' });
+
+    expect(sink.log).toHaveBeenCalledWith('debug', 'Cache miss', { run_id: 'run-1', package: 'example.com/mod/a' });
+  });
+
+  it('should add fields with with() without changing the parent', () => {
+    const log = new RunLog({ run_id: 'run-1' }, sink as any);
+    const pkgLog = log.with({ package: 'example.com/mod/a' });
+
+    pkgLog.info('Package started');
+    log.info('Run finished');
+
+    expect(sink.log).toHaveBeenNthCalledWith(1, 'info', 'Package started', { run_id: 'run-1', package: 'example.com/mod/a' });
+    expect(sink.log).toHaveBeenNthCalledWith(2, 'info', 'Run finished', { run_id: 'run-1' });
+    expect(pkgLog.runId).toBe('run-1');
+  });
+
+  it('should follow the active log across awaits', async () => {
+    const log = new RunLog({ run_id: 'run-1', package: 'example.com/mod/a' }, sink as any);
+
+    await log.run(async () => {
+      await new Promise(resolve => setImmediate(resolve));
+      currentRunLog().debug('Container created', { container_id: 'alcs-a' });
+    });
+
+    expect(sink.log).toHaveBeenCalledWith('debug', 'Container created', {
+      run_id: 'run-1',
+      package: 'example.com/mod/a',
+      container_id: 'alcs-a',
+    });
+  });
+
+  it('should fall back to the service logger outside a run', () => {
+    currentRunLog().info('Container removed', { container_id: 'alcs-a' });
+
+    expect(currentRunLog().runId).toBeUndefined();
+    expect(logger.log).toHaveBeenCalledWith('info', 'Container removed', { container_id: 'alcs-a' });
+  });
+
+  it('should use the service logger when no level or format is set', () => {
+    createRunLog('run-2').warn('Run failed');
+
+    expect(logger.log).toHaveBeenCalledWith('warn', 'Run failed', { run_id: 'run-2' });
+  });
+});
@@ -0,0 +1,610 @@
+/**
+ * Unit Tests for Sandbox Service
+ */
+
+import {
+  SandboxService,
+  SandboxConfig,
+  DockerClient,
+  RetryPolicy,
+  isNetworkPullError,
+  isTransient,
+  mirrorImage,
+  parseMemoryLimit,
+  parseMirrorRule,
+} from '../../src/services/sandboxService';
+import * as child_process from 'child_process';
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { EventEmitter } from 'events';
+import { logger } from '../../src/services/loggerService';
+
+jest.mock('child_process');
+jest.mock('../../src/services/loggerService');
+jest.mock('../../src/services/metricsService');
+
+describe('SandboxService', () => {
+  let sandbox: SandboxService;
+  const mockExecFile = child_process.execFile as unknown as jest.Mock;
+
+  const config: SandboxConfig = {
+    image: 'golang:1.21-alpine',
+    timeout_seconds: 60,
+    memory_limit_mb: 2048,
+    cpu_limit: 1.5,
+    pids_limit: 256,
+    network_mode: 'none',
+    readonly_rootfs: false,
+    tmpfs_size_mb: 100,
+  };
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    sandbox = new SandboxService();
+  });
+
+  describe('parseMemoryLimit', () => {
+    it('should parse Docker-style sizes into megabytes', () => {
+      expect(parseMemoryLimit('512m')).toBe(512);
+      expect(parseMemoryLimit('2g')).toBe(2048);
+      expect(parseMemoryLimit('1.5G')).toBe(1536);
+      expect(parseMemoryLimit('256')).toBe(256);
+    });
+
+    it('should reject invalid sizes', () => {
+      expect(() => parseMemoryLimit('lots')).toThrow('Invalid memory limit');
+    });
+  });
+
+  describe('buildDockerArgs', () => {
+    it('should apply memory, CPU, and PID limits', () => {
+      const args = sandbox.buildDockerArgs(config, 'alcs-test-1', '/work', '/work');
+
+      expect(args).toEqual(expect.arrayContaining([
+        '--memory=2048m',
+        '--memory-swap=2048m',
+        '--cpus=1.5',
+        '--pids-limit=256',
+      ]));
+      expect(args).not.toContain('--rm');
+    });
+
+    it('should take limits from execution options', () => {
+      const defaults = sandbox.getDefaultConfig({ memory: '1g', cpu_limit: 2, pids_limit: 64 });
+
+      expect(defaults.memory_limit_mb).toBe(1024);
+      expect(defaults.cpu_limit).toBe(2);
+      expect(defaults.pids_limit).toBe(64);
+    });
+  });
+
+  describe('executeInSandbox', () => {
+    it('should detect an OOM kill from exit code 137 and container state', async () => {
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        const done = typeof opts === 'function' ? opts : callback;
+        if (args[0] === 'start') {
+          const error: any = new Error('exit status 137');
+          error.code = 137;
+          error.stdout = '';
+          error.stderr = '';
+          return done(error);
+        }
+        if (args[0] === 'inspect') {
+          return done(null, { stdout: 'true\n', stderr: '' });
+        }
+        return done(null, { stdout: '', stderr: '' });
+      });
+
+      const result = await sandbox.executeInSandbox(config, ['go', 'test', './...'], '/work');
+
+      expect(result.exitCode).toBe(137);
+      expect(result.oomKilled).toBe(true);
+      const inspectCall = mockExecFile.mock.calls.findIndex(c => c[1][0] === 'inspect');
+      const removeCall = mockExecFile.mock.calls.findIndex(c => c[1][0] === 'rm');
+      expect(inspectCall).toBeLessThan(removeCall);
+    });
+
+    it('should not report OOM for other failures', async () => {
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        const done = typeof opts === 'function' ? opts : callback;
+        if (args[0] === 'start') {
+          const error: any = new Error('exit status 1');
+          error.code = 1;
+          return done(error);
+        }
+        return done(null, { stdout: args[0] === 'inspect' ? 'false\n' : '', stderr: '' });
+      });
+
+      const result = await sandbox.executeInSandbox(config, ['go', 'test', './...'], '/work');
+
+      expect(result.exitCode).toBe(1);
+      expect(result.oomKilled).toBe(false);
+    });
+  });
+
+  describe('isTransient', () => {
+    const dockerError = (stderr: string, code: any = 125) => Object.assign(new Error('Command failed: docker create'), { code, stderr });
+
+    it('should retry daemon availability problems and name conflicts', () => {
+      expect(isTransient(dockerError(
+        'Error response from daemon: Conflict. The container name "/alcs-test-1" is already in use by container "3f2a"'
+      ))).toBe(true);
+      expect(isTransient(dockerError(
+        'Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?'
+      ))).toBe(true);
+      expect(isTransient(dockerError('Error response from daemon: context deadline exceeded'))).toBe(true);
+      expect(isTransient({ killed: true, signal: 'SIGTERM', message: 'Command failed' })).toBe(true);
+    });
+
+    it('should not retry missing images or invalid configuration', () => {
+      expect(isTransient(dockerError(
+        "Unable to find image 'golang:9.99' locally\nError response from daemon: manifest for golang:9.99 not found"
+      ))).toBe(false);
+      expect(isTransient(dockerError('invalid argument "abc" for "--memory" flag: invalid size'))).toBe(false);
+      expect(isTransient(dockerError('', 'ENOENT'))).toBe(false);
+    });
+  });
+
+  describe('container creation retries', () => {
+    const transientError = () => Object.assign(new Error('Command failed: docker create'), {
+      code: 125,
+      stderr: 'Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?',
+    });
+
+    let sleeps: number[];
+    let policy: RetryPolicy;
+    let createFailures: Error[];
+    let docker: DockerClient & { run: jest.Mock };
+
+    beforeEach(() => {
+      sleeps = [];
+      policy = { maxRetries: 3, baseDelayMs: 100, maxDelayMs: 1000, sleep: async ms => { sleeps.push(ms); } };
+      createFailures = [];
+      docker = {
+        run: jest.fn(async (args: string[]) => {
+          if (args[0] === 'create' && createFailures.length > 0) {
+            throw createFailures.shift();
+          }
+          return { stdout: args[0] === 'start' ? 'ok\n' : '', stderr: '' };
+        }),
+        spawn: jest.fn(),
+      };
+    });
+
+    const createCalls = () => docker.run.mock.calls.filter(([args]) => args[0] === 'create');
+
+    it('should back off exponentially and succeed after transient errors', async () => {
+      createFailures = [transientError(), transientError()];
+      const service = new SandboxService(docker, policy);
+
+      const result = await service.executeInSandbox(config, ['go', 'test', './...'], '/work');
+
+      expect(result.exitCode).toBe(0);
+      expect(result.stdout).toBe('ok\n');
+      expect(createCalls()).toHaveLength(3);
+      expect(sleeps).toEqual([100, 200]);
+
+      // Every attempt uses a fresh container name
+      const names = createCalls().map(([args]) => args[args.indexOf('--name') + 1]);
+      expect(new Set(names).size).toBe(3);
+      expect(docker.run).toHaveBeenCalledWith(['start', '--attach', names[2]], expect.anything());
+    });
+
+    it('should fail immediately on a permanent error', async () => {
+      createFailures = [Object.assign(new Error('Command failed: docker create'), {
+        code: 125,
+        stderr: 'Error response from daemon: No such image: golang:9.99',
+      })];
+      const service = new SandboxService(docker, policy);
+
+      const result = await service.executeInSandbox(config, ['go', 'test', './...'], '/work');
+
+      expect(result.exitCode).toBe(125);
+      expect(result.stderr).toContain('No such image');
+      expect(createCalls()).toHaveLength(1);
+      expect(sleeps).toEqual([]);
+      expect(docker.run.mock.calls.some(([args]) => args[0] === 'start')).toBe(false);
+    });
+
+    it('should give up after the configured number of retries', async () => {
+      createFailures = [transientError(), transientError(), transientError()];
+      const service = new SandboxService(docker, policy);
+
+      await expect(
+        service.spawnInSandbox({ ...config, create_retries: 1 }, ['go', 'test', './...'], '/work')
+      ).rejects.toThrow('docker create');
+
+      expect(createCalls()).toHaveLength(2);
+      expect(sleeps).toEqual([100]);
+      expect(docker.spawn).not.toHaveBeenCalled();
+    });
+
+    it('should cap the backoff delay', async () => {
+      createFailures = Array.from({ length: 5 }, transientError);
+      const service = new SandboxService(docker, { ...policy, maxRetries: 5, baseDelayMs: 300 });
+
+      await service.spawnInSandbox(config, ['go', 'test', './...'], '/work');
+
+      expect(sleeps).toEqual([300, 600, 1000, 1000, 1000]);
+      expect(docker.spawn).toHaveBeenCalledWith(['start', '--attach', expect.stringMatching(/^alcs-test-/)]);
+    });
+  });
+
+  describe('secret environment', () => {
+    it('should pass secrets through a private env file and keep them out of argv and logs', async () => {
+      let envFile = '';
+      let envFileContent = '';
+      let envFileMode = 0;
+      const docker: DockerClient & { run: jest.Mock } = {
+        run: jest.fn(async (args: string[]) => {
+          if (args[0] === 'create') {
+            envFile = args[args.indexOf('--env-file') + 1];
+            envFileContent = await fs.readFile(envFile, 'utf-8');
+            envFileMode = (await fs.stat(envFile)).mode & 0o777;
+          }
+          return { stdout: '', stderr: '' };
+        }),
+        spawn: jest.fn(),
+      };
+      const service = new SandboxService(docker);
+
+      await service.executeInSandbox(
+        { ...config, env: { DATABASE_URL: 'postgres://db:5432/app' }, secret_env: { API_TOKEN: 's3cr3t-token' } },
+        ['go', 'test', './...'],
+        '/work'
+      );
+
+      const createArgs: string[] = docker.run.mock.calls.find(([args]) => args[0] === 'create')![0];
+      expect(createArgs).toContain('DATABASE_URL=postgres://db:5432/app');
+      expect(createArgs.join(' ')).not.toContain('s3cr3t-token');
+      expect(envFileContent).toBe('API_TOKEN=s3cr3t-token\n');
+      expect(envFileMode).toBe(0o600);
+      await expect(fs.access(envFile)).rejects.toThrow();
+
+      const logged = JSON.stringify((logger.debug as jest.Mock).mock.calls);
+      expect(logged).toContain('Creating container');
+      expect(logged).not.toContain('s3cr3t-token');
+    });
+
+    it('should add no env file without secrets', () => {
+      expect(sandbox.buildDockerArgs(config, 'alcs-test-1', '/work', '/workspace')).not.toContain('--env-file');
+    });
+  });
+
+  describe('ensureImage', () => {
+    const fakePull = (stdout: string, exitCode: number, stderr: string = '') => {
+      const child: any = new EventEmitter();
+      child.stdout = new EventEmitter();
+      child.stderr = new EventEmitter();
+      child.kill = jest.fn();
+      setImmediate(() => {
+        child.stdout.emit('data', Buffer.from(stdout));
+        child.stderr.emit('data', Buffer.from(stderr));
+        child.emit('close', exitCode);
+      });
+      return child;
+    };
+
+    it('should pull a missing image and report progress', async () => {
+      const docker: DockerClient & { run: jest.Mock; spawn: jest.Mock } = {
+        run: jest.fn(async () => { throw new Error('Error: No such image: golang:1.22-alpine'); }),
+        spawn: jest.fn(() => fakePull('1.22-alpine: Pulling from library/golang\n4abcf2066143: Pull complete\nStatus: Downloaded', 0)),
+      };
+      const progress: string[] = [];
+
+      await new SandboxService(docker).ensureImage('golang:1.22-alpine', line => progress.push(line));
+
+      expect(docker.spawn).toHaveBeenCalledWith(['pull', 'golang:1.22-alpine']);
+      expect(progress).toEqual([
+        '1.22-alpine: Pulling from library/golang',
+        '4abcf2066143: Pull complete',
+        'Status: Downloaded',
+      ]);
+    });
+
+    it('should not pull an image that exists', async () => {
+      const docker: DockerClient & { run: jest.Mock; spawn: jest.Mock } = {
+        run: jest.fn(async () => ({ stdout: '[]', stderr: '' })),
+        spawn: jest.fn(),
+      };
+
+      await new SandboxService(docker).ensureImage('golang:1.22-alpine');
+
+      expect(docker.run).toHaveBeenCalledWith(['image', 'inspect', 'golang:1.22-alpine']);
+      expect(docker.spawn).not.toHaveBeenCalled();
+    });
+
+    it('should fail when the image cannot be pulled', async () => {
+      const docker: DockerClient & { run: jest.Mock; spawn: jest.Mock } = {
+        run: jest.fn(async () => { throw new Error('No such image'); }),
+        spawn: jest.fn(() => fakePull('', 1, 'Error response from daemon: manifest for golang:9.99-alpine not found\n')),
+      };
+
+      await expect(new SandboxService(docker).ensureImage('golang:9.99-alpine')).rejects.toThrow('manifest for golang:9.99-alpine not found');
+    });
+
+    it('should fall back to the registry mirror on a network error and tag the original name', async () => {
+      const docker: DockerClient & { run: jest.Mock; spawn: jest.Mock } = {
+        run: jest.fn(async (args: string[]) => {
+          if (args[0] === 'image') {
+            throw new Error('No such image');
+          }
+          return { stdout: '', stderr: '' };
+        }),
+        spawn: jest.fn((args: string[]) => args[1] === 'golang:1.21-alpine'
+          ? fakePull('', 1, 'Error response from daemon: Get "https://registry-1.docker.io/v2/": dial tcp: lookup registry-1.docker.io: no such host\n')
+          : fakePull('Status: Downloaded', 0)),
+      };
+
+      await new SandboxService(docker).ensureImage('golang:1.21-alpine', () => {}, { prefix: 'registry.internal/' });
+
+      expect(docker.spawn.mock.calls.map(([args]) => args)).toEqual([
+        ['pull', 'golang:1.21-alpine'],
+        ['pull', 'registry.internal/golang:1.21-alpine'],
+      ]);
+      expect(docker.run).toHaveBeenLastCalledWith(['tag', 'registry.internal/golang:1.21-alpine', 'golang:1.21-alpine']);
+    });
+
+    it('should not fall back to the mirror when the registry refuses the pull', async () => {
+      const docker: DockerClient & { run: jest.Mock; spawn: jest.Mock } = {
+        run: jest.fn(async () => { throw new Error('No such image'); }),
+        spawn: jest.fn(() => fakePull('', 1, 'Error response from daemon: pull access denied for acme/private, repository does not exist or may require \'docker login\'\n')),
+      };
+
+      await expect(new SandboxService(docker).ensureImage('acme/private:1', () => {}, { prefix: 'registry.internal/' }))
+        .rejects.toThrow('pull access denied');
+      expect(docker.spawn).toHaveBeenCalledTimes(1);
+    });
+  });
+
+  describe('registry mirror', () => {
+    it('should classify only network failures as worth a mirror retry', () => {
+      expect(isNetworkPullError(new Error('dial tcp 54.1.2.3:443: i/o timeout'))).toBe(true);
+      expect(isNetworkPullError(new Error('docker pull timed out after 300s'))).toBe(true);
+      expect(isNetworkPullError(new Error('Head "https://registry-1.docker.io/v2/library/golang/manifests/1.21": unauthorized: incorrect username or password'))).toBe(false);
+      expect(isNetworkPullError(new Error('manifest for golang:9.99-alpine not found: manifest unknown'))).toBe(false);
+      expect(isNetworkPullError(new Error('invalid reference format'))).toBe(false);
+    });
+
+    it('should rewrite image names by the first matching rule, then the prefix', () => {
+      const mirror = {
+        prefix: 'registry.internal',
+        rules: [parseMirrorRule('ghcr.io/=registry.internal/ghcr/'), parseMirrorRule('golang:=registry.internal/library/golang:')],
+      };
+
+      expect(mirrorImage('golang:1.21-alpine', mirror)).toBe('registry.internal/library/golang:1.21-alpine');
+      expect(mirrorImage('ghcr.io/acme/tools:2', mirror)).toBe('registry.internal/ghcr/acme/tools:2');
+      expect(mirrorImage('node:20-alpine', mirror)).toBe('registry.internal/node:20-alpine');
+      expect(mirrorImage('node:20-alpine', { rules: mirror.rules })).toBeUndefined();
+    });
+
+    it('should reject malformed rewrite rules', () => {
+      expect(() => parseMirrorRule('registry.internal/')).toThrow('Invalid registry mirror rule');
+      expect(() => sandbox.getDefaultConfig({ registry_mirror_rules: ['golang:='] })).toThrow('expected from=to');
+    });
+  });
+
+  describe('cancellation', () => {
+    it('should remove a container whose create raced with cancellation', async () => {
+      const controller = new AbortController();
+      const removed: string[] = [];
+      const docker: DockerClient & { run: jest.Mock } = {
+        run: jest.fn(async (args: string[]) => {
+          if (args[0] === 'create') {
+            controller.abort(); // Cancelled while the daemon was still creating the container
+          }
+          if (args[0] === 'rm') {
+            removed.push(args[2]);
+          }
+          return { stdout: '', stderr: '' };
+        }),
+        spawn: jest.fn(),
+      };
+      const service = new SandboxService(docker);
+
+      await expect(
+        service.spawnInSandbox(config, ['go', 'test', './...'], '/work', '/work', controller.signal)
+      ).rejects.toThrow('was cancelled');
+
+      const created = docker.run.mock.calls.find(([args]) => args[0] === 'create')![0];
+      expect(removed).toEqual([created[created.indexOf('--name') + 1]]);
+      expect(docker.spawn).not.toHaveBeenCalled();
+    });
+
+    it('should not create a container once cancelled', async () => {
+      const controller = new AbortController();
+      controller.abort();
+      const docker: DockerClient & { run: jest.Mock } = { run: jest.fn(), spawn: jest.fn() };
+      const service = new SandboxService(docker);
+
+      await expect(
+        service.executeInSandbox(config, ['go', 'test', './...'], '/work', '/work', controller.signal)
+      ).rejects.toThrow('was cancelled');
+
+      expect(docker.run).not.toHaveBeenCalled();
+    });
+  });
+
+  describe('copy mounts', () => {
+    let workspace: string;
+    let copied: string[];
+    let docker: DockerClient & { run: jest.Mock };
+
+    beforeEach(async () => {
+      workspace = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-snapshot-'));
+      copied = [];
+      docker = {
+        run: jest.fn(async (args: string[]) => {
+          if (args[0] === 'cp') {
+            const stage = args[1].replace(/\/\.$/, '');
+            const walk = async (dir: string): Promise<void> => {
+              for (const entry of await fs.readdir(dir, { withFileTypes: true })) {
+                const full = path.join(dir, entry.name);
+                if (entry.isDirectory()) {
+                  await walk(full);
+                } else {
+                  copied.push(path.relative(stage, full).split(path.sep).join('/'));
+                }
+              }
+            };
+            await walk(stage);
+          }
+          return { stdout: '', stderr: '' };
+        }),
+        spawn: jest.fn(),
+      };
+
+      const files: Record<string, string> = {
+        'go.mod': 'module example.com/mod\n',
+        'calc/calc.go': 'package calc\n',
+        'calc/debug.log': 'noise\n',
+        'vendor/dep/dep.go': 'package dep\n',
+        'testdata/big.bin': 'blob\n',
+        '.git/HEAD': 'ref: refs/heads/main\n',
+        'reports/old.xml': '<testsuites/>\n',
+        '.gitignore': '*.log\n!keep.log\n',
+        '.dockerignore': 'vendor\n',
+      };
+      for (const [file, content] of Object.entries(files)) {
+        await fs.mkdir(path.dirname(path.join(workspace, file)), { recursive: true });
+        await fs.writeFile(path.join(workspace, file), content);
+      }
+    });
+
+    afterEach(async () => {
+      await fs.rm(workspace, { recursive: true, force: true });
+    });
+
+    it('should mount the snapshot volume with output paths bound over it', () => {
+      const args = sandbox.buildDockerArgs(
+        { ...config, mount: 'copy', source_volume: 'alcs-src-1', output_paths: ['/work/reports', '/work/.cache'] },
+        'alcs-test-1', '/work', '/workspace'
+      );
+
+      expect(args).toEqual(expect.arrayContaining([
+        '--volume=alcs-src-1:/workspace:rw',
+        '--volume=/work/reports:/workspace/reports:rw',
+        '--volume=/work/.cache:/workspace/.cache:rw',
+      ]));
+      expect(args).not.toContain('--volume=/work:/workspace:rw');
+      expect(sandbox.buildDockerArgs(config, 'alcs-test-1', '/work', '/workspace')).toContain('--volume=/work:/workspace:rw');
+    });
+
+    it('should refuse a copy mount without a snapshot', () => {
+      expect(() => sandbox.buildDockerArgs({ ...config, mount: 'copy' }, 'alcs-test-1', '/work', '/workspace'))
+        .toThrow('without a workspace snapshot');
+    });
+
+    it('should snapshot the workspace without ignored files or output paths', async () => {
+      const service = new SandboxService(docker);
+      const outputs = [path.join(workspace, 'reports'), path.join(workspace, '.cache')];
+
+      const volume = await service.snapshotWorkspace(
+        { ...config, mount: 'copy', output_paths: outputs }, workspace, '/workspace', ['testdata/']
+      );
+
+      expect(volume).toMatch(/^alcs-src-/);
+      expect(copied.sort()).toEqual(['.dockerignore', '.gitignore', 'calc/calc.go', 'go.mod']);
+      await expect(fs.access(path.join(workspace, '.cache'))).resolves.toBeUndefined();
+
+      const calls: string[][] = docker.run.mock.calls.map(([args]) => args);
+      expect(calls.map(args => args.slice(0, 2).join(' '))).toEqual([
+        `volume create`,
+        `create --name`,
+        `cp ${calls[2][1]}`,
+        `start --attach`,
+        `rm -f`,
+      ]);
+      expect(calls[1]).toEqual(expect.arrayContaining([`--volume=${volume}:/workspace`, 'chown']));
+      await expect(fs.access(calls[2][1].replace(/\/\.$/, ''))).rejects.toThrow();
+    });
+
+    it('should remove the volume when the snapshot fails', async () => {
+      docker.run.mockImplementation(async (args: string[]) => {
+        if (args[0] === 'cp') {
+          throw Object.assign(new Error('Command failed: docker cp'), { stderr: 'no space left on device' });
+        }
+        return { stdout: '', stderr: '' };
+      });
+      const service = new SandboxService(docker);
+
+      await expect(service.snapshotWorkspace(config, workspace, '/workspace'))
+        .rejects.toThrow('no space left on device');
+
+      const calls: string[][] = docker.run.mock.calls.map(([args]) => args);
+      expect(calls.some(args => args[0] === 'rm')).toBe(true);
+      expect(calls[calls.length - 1].slice(0, 3)).toEqual(['volume', 'rm', '-f']);
+    });
+  });
+
+  describe('copyArtifacts', () => {
+    let hostDir: string;
+    let docker: DockerClient & { run: jest.Mock };
+
+    beforeEach(async () => {
+      hostDir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-artifacts-'));
+      docker = {
+        run: jest.fn(async (args: string[]) => {
+          const source = args[1] || '';
+          if (args[0] === 'cp' && source.endsWith(':/go/missing')) {
+            throw Object.assign(new Error('Command failed: docker cp'), {
+              code: 1,
+              stderr: 'Error response from daemon: Could not find the file /go/missing in container alcs-test-1',
+            });
+          }
+          if (args[0] === 'cp' && source.endsWith(':/go/locked')) {
+            throw Object.assign(new Error('Command failed: docker cp'), {
+              code: 1,
+              stderr: 'open /go/locked/trace.out: permission denied',
+            });
+          }
+          if (args[0] === 'cp') {
+            await fs.mkdir(args[2], { recursive: true });
+            await fs.writeFile(path.join(args[2], 'app.log'), 'log line\n');
+          }
+          return { stdout: '', stderr: '' };
+        }),
+        spawn: jest.fn(),
+      };
+    });
+
+    afterEach(async () => {
+      await fs.rm(hostDir, { recursive: true, force: true });
+    });
+
+    it('should copy artifacts into a per-container directory before removal', async () => {
+      const service = new SandboxService(docker);
+      const artifactConfig = { ...config, artifact_paths: ['/go/test-output', '/go/missing'], artifacts_dir: hostDir };
+
+      const result = await service.finishContainer('alcs-test-1', 1, artifactConfig);
+
+      expect(result.artifacts).toEqual({ dir: path.join(hostDir, 'alcs-test-1'), warnings: [] });
+      const log = await fs.readFile(path.join(hostDir, 'alcs-test-1', 'go', 'test-output', 'app.log'), 'utf-8');
+      expect(log).toBe('log line\n');
+
+      const calls = docker.run.mock.calls.map(([args]) => args[0]);
+      expect(calls.lastIndexOf('cp')).toBeLessThan(calls.indexOf('rm'));
+    });
+
+    it('should warn about failed copies without failing', async () => {
+      const service = new SandboxService(docker);
+      const artifactConfig = { ...config, artifact_paths: ['/go/locked', '/go/missing'], artifacts_dir: hostDir };
+
+      const artifacts = await service.copyArtifacts('alcs-test-2', artifactConfig);
+
+      expect(artifacts!.dir).toBeUndefined();
+      expect(artifacts!.warnings).toEqual([
+        'Failed to copy artifact /go/locked from alcs-test-2: open /go/locked/trace.out: permission denied',
+      ]);
+      await expect(fs.access(path.join(hostDir, 'alcs-test-2'))).rejects.toThrow();
+    });
+
+    it('should do nothing when no artifacts are declared', async () => {
+      const service = new SandboxService(docker);
+
+      await expect(service.copyArtifacts('alcs-test-3', config)).resolves.toBeUndefined();
+      expect(docker.run).not.toHaveBeenCalled();
+    });
+  });
+});
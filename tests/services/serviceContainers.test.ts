@@ -0,0 +1,162 @@
+/**
+ * Unit Tests for Service Containers
+ */
+
+import { DependencyUnhealthyError, ServiceContainers, serviceEnvPrefix } from '../../src/services/serviceContainers';
+import { DockerClient } from '../../src/services/sandboxService';
+import { ServiceDependency } from '../../src/types/mcp';
+
+jest.mock('../../src/services/loggerService');
+
+describe('ServiceContainers', () => {
+  let docker: DockerClient & { run: jest.Mock };
+  let clock: number;
+  let sleeps: number[];
+  let healthFailures: number;
+  let healthError: any;
+
+  const postgres: ServiceDependency = {
+    name: 'postgres',
+    image: 'postgres:16',
+    ports: [5432],
+    env: { POSTGRES_PASSWORD: 'test' },
+    health_cmd: ['pg_isready', '-U', 'postgres'],
+    health_timeout_seconds: 5,
+    health_interval_ms: 1000,
+  };
+
+  const calls = (command: string) => docker.run.mock.calls.map(([args]) => args as string[]).filter(args => args[0] === command);
+
+  const newServices = () => new ServiceContainers(docker, async ms => { sleeps.push(ms); clock += ms; }, () => clock);
+
+  beforeEach(() => {
+    clock = 0;
+    sleeps = [];
+    healthFailures = 0;
+    healthError = Object.assign(new Error('Command failed: docker exec'), { stderr: 'no response\n' });
+    docker = {
+      run: jest.fn(async (args: string[]) => {
+        if (args[0] === 'exec' && healthFailures > 0) {
+          healthFailures--;
+          throw healthError;
+        }
+        if (args[0] === 'port') {
+          return { stdout: '127.0.0.1:49153\n', stderr: '' };
+        }
+        return { stdout: '', stderr: '' };
+      }),
+      spawn: jest.fn(),
+    };
+  });
+
+  describe('serviceEnvPrefix', () => {
+    it('should upper-case the name and replace separators', () => {
+      expect(serviceEnvPrefix('redis-cache.v2')).toBe('REDIS_CACHE_V2');
+    });
+  });
+
+  describe('start', () => {
+    it('should run sandboxed services on an internal network and expose them by name', async () => {
+      const running = await newServices().start([postgres], { sandbox: true });
+
+      const [create] = calls('network');
+      expect(create.slice(0, 4)).toEqual(['network', 'create', '--label', 'alcs.services=true']);
+      expect(create).toContain('--internal');
+      expect(running.network).toBe(create[create.length - 1]);
+
+      const [run] = calls('run');
+      expect(run).toEqual(expect.arrayContaining([
+        `--network=${running.network}`, '--network-alias=postgres', '--env', 'POSTGRES_PASSWORD=test',
+      ]));
+      expect(run[run.length - 1]).toBe('postgres:16');
+      expect(run).not.toContain('--publish');
+
+      expect(running.env).toEqual({
+        POSTGRES_HOST: 'postgres',
+        POSTGRES_PORT: '5432',
+        POSTGRES_PORT_5432: '5432',
+        POSTGRES_ADDR: 'postgres:5432',
+      });
+    });
+
+    it('should leave the network open when the run enables network access', async () => {
+      await newServices().start([postgres], { sandbox: true, enableNetwork: true });
+
+      expect(calls('network')[0]).not.toContain('--internal');
+    });
+
+    it('should publish ports on localhost for tests on the host', async () => {
+      const running = await newServices().start([postgres], { sandbox: false });
+
+      expect(calls('network')).toHaveLength(0);
+      expect(running.network).toBeUndefined();
+      expect(calls('run')[0]).toEqual(expect.arrayContaining(['--publish', '127.0.0.1::5432']));
+      expect(calls('port')[0]).toEqual(['port', running.containers[0], '5432/tcp']);
+      expect(running.env.POSTGRES_ADDR).toBe('127.0.0.1:49153');
+    });
+
+    it('should poll the health command until it passes', async () => {
+      healthFailures = 2;
+
+      const running = await newServices().start([postgres], { sandbox: true });
+
+      expect(calls('exec')).toHaveLength(3);
+      expect(calls('exec')[0]).toEqual(['exec', running.containers[0], 'pg_isready', '-U', 'postgres']);
+      expect(sleeps).toEqual([1000, 1000]);
+    });
+
+    it('should fail as unhealthy at the deadline and remove everything it started', async () => {
+      healthFailures = Infinity;
+
+      const start = newServices().start([postgres], { sandbox: true });
+
+      await expect(start).rejects.toThrow(DependencyUnhealthyError);
+      await expect(start).rejects.toThrow(
+        'Dependency postgres unhealthy: pg_isready -U postgres still failing after 5s (5 checks): no response'
+      );
+      expect(calls('rm')).toHaveLength(1);
+      expect(calls('network').map(args => args[1])).toEqual(['create', 'rm']);
+    });
+
+    it('should fail at once when the service container exits', async () => {
+      healthFailures = Infinity;
+      healthError = Object.assign(new Error('Command failed: docker exec'), {
+        stderr: 'Error response from daemon: container abc is not running',
+      });
+      docker.run.mockImplementation(async (args: string[]) => {
+        if (args[0] === 'exec') {
+          throw healthError;
+        }
+        if (args[0] === 'logs') {
+          return { stdout: '', stderr: 'FATAL: password authentication failed\n' };
+        }
+        return { stdout: '', stderr: '' };
+      });
+
+      await expect(newServices().start([postgres], { sandbox: true })).rejects.toThrow(
+        'Dependency postgres unhealthy: container exited before pg_isready -U postgres passed; last output:\n' +
+        'FATAL: password authentication failed'
+      );
+      expect(sleeps).toEqual([]);
+    });
+
+    it('should reject services whose variables would collide', async () => {
+      await expect(newServices().start([
+        { name: 'redis-cache', image: 'redis:7' },
+        { name: 'redis_cache', image: 'redis:7' },
+      ], { sandbox: true })).rejects.toThrow('would share the REDIS_CACHE_* variables');
+      expect(docker.run).not.toHaveBeenCalled();
+    });
+  });
+
+  describe('stop', () => {
+    it('should keep removing after a failure', async () => {
+      docker.run.mockRejectedValueOnce(new Error('No such container'));
+
+      await newServices().stop({ network: 'alcs-svc-1', containers: ['a', 'b'], env: {} });
+
+      expect(calls('rm')).toEqual([['rm', '-f', '-v', 'a'], ['rm', '-f', '-v', 'b']]);
+      expect(calls('network')).toEqual([['network', 'rm', 'alcs-svc-1']]);
+    });
+  });
+});
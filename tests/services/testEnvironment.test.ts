@@ -0,0 +1,76 @@
+/**
+ * Unit Tests for Test Environment
+ */
+
+import { TestEnvironmentLoader, redactSecrets } from '../../src/services/testEnvironment';
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+
+describe('TestEnvironmentLoader', () => {
+  let loader: TestEnvironmentLoader;
+  let dir: string;
+
+  beforeEach(async () => {
+    loader = new TestEnvironmentLoader();
+    dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-env-test-'));
+  });
+
+  afterEach(async () => {
+    await fs.rm(dir, { recursive: true, force: true });
+  });
+
+  describe('load', () => {
+    it('should layer env entries over the env file', async () => {
+      const envFile = path.join(dir, 'test.env');
+      await fs.writeFile(envFile, '# integration\nexport DATABASE_URL="postgres://file/app"\nREGION=eu\n\n');
+
+      const env = await loader.load({ env_file: envFile, env: ['DATABASE_URL=postgres://db:5432/app?sslmode=disable'] });
+
+      expect(env).toEqual({
+        env: { DATABASE_URL: 'postgres://db:5432/app?sslmode=disable', REGION: 'eu' },
+        secrets: {},
+      });
+    });
+
+    it('should read secrets from files and keep them out of plain env', async () => {
+      const tokenFile = path.join(dir, 'api_token');
+      await fs.writeFile(tokenFile, 'tok-123\n');
+
+      const env = await loader.load({ env: ['API_TOKEN=placeholder'], secret_files: [`API_TOKEN=${tokenFile}`] });
+
+      expect(env).toEqual({ env: {}, secrets: { API_TOKEN: 'tok-123' } });
+    });
+
+    it('should reject malformed entries', async () => {
+      await expect(loader.load({ env: ['DATABASE_URL'] })).rejects.toThrow('Invalid env entry');
+      await expect(loader.load({ env: ['1BAD=x'] })).rejects.toThrow('Invalid env entry');
+    });
+
+    it('should reject unreadable and multi-line secrets', async () => {
+      const certFile = path.join(dir, 'cert.pem');
+      await fs.writeFile(certFile, '-----BEGIN-----\nabc\n-----END-----\n');
+
+      await expect(loader.load({ secret_files: [`TOKEN=${path.join(dir, 'missing')}`] }))
+        .rejects.toThrow('Cannot read secret file for TOKEN');
+      await expect(loader.load({ secret_files: [`CERT=${certFile}`] })).rejects.toThrow('spans several lines');
+    });
+  });
+
+  describe('parseEnvFile', () => {
+    it('should point at the offending line', () => {
+      expect(() => loader.parseEnvFile('A=1\nnot a pair\n', 'ci.env')).toThrow('Invalid ci.env:2 entry');
+    });
+  });
+
+  describe('redactSecrets', () => {
+    it('should replace every occurrence of each secret value', () => {
+      const text = 'docker create --env TOKEN=abc123 --env OTHER=abc123456 image';
+
+      expect(redactSecrets(text, { TOKEN: 'abc123', OTHER: 'abc123456' })).toBe(
+        'docker create --env TOKEN=*** --env OTHER=*** image'
+      );
+      expect(redactSecrets(text)).toBe(text);
+    });
+  });
+});
@@ -0,0 +1,102 @@
+/**
+ * Unit Tests for Test Label Scanner
+ */
+
+import { TestLabelScanner } from '../../src/services/testLabelScanner';
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+
+describe('TestLabelScanner', () => {
+  let scanner: TestLabelScanner;
+
+  const source = `package store
+
+import "testing"
+
+// TestCheckout talks to a real database.
+// +alcs:integration +alcs:slow
+func TestCheckout(t *testing.T) {
+	// +alcs:ignored inside a body
+}
+
+// +alcs:slow,flaky
+
+func TestDetached(t *testing.T) {}
+
+func TestUnit(t *testing.T) {}
+
+//+alcs:integration
+func ExampleStore() {}
+
+// +alcs:slow
+func (s *suite) TestMethod(t *testing.T) {}
+
+// +alcs:helper
+func testHelper(t *testing.T) {}
+`;
+
+  beforeEach(() => {
+    scanner = new TestLabelScanner();
+  });
+
+  describe('parse', () => {
+    it('should attach labels from the comment block directly above each test', () => {
+      expect(scanner.parse(source)).toEqual({
+        TestCheckout: ['integration', 'slow'],
+        TestDetached: [],
+        TestUnit: [],
+        ExampleStore: ['integration'],
+      });
+    });
+  });
+
+  describe('scan', () => {
+    let dir: string;
+
+    beforeEach(async () => {
+      dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-labels-'));
+    });
+
+    afterEach(async () => {
+      await fs.rm(dir, { recursive: true, force: true });
+    });
+
+    it('should read every _test.go file in the package directory', async () => {
+      await fs.writeFile(path.join(dir, 'store_test.go'), source);
+      await fs.writeFile(path.join(dir, 'export_test.go'), 'package store\n\n// +alcs:fuzz\nfunc FuzzParse(f *testing.F) {}\n');
+      await fs.writeFile(path.join(dir, 'store.go'), 'package store\n\n// +alcs:slow\nfunc TestNotATest() {}\n');
+
+      const tests = await scanner.scan(dir);
+
+      expect(Object.keys(tests).sort()).toEqual(['ExampleStore', 'FuzzParse', 'TestCheckout', 'TestDetached', 'TestUnit']);
+      expect(tests.FuzzParse).toEqual(['fuzz']);
+    });
+
+    it('should read only the given test files', async () => {
+      await fs.writeFile(path.join(dir, 'store_test.go'), source);
+      await fs.writeFile(path.join(dir, 'export_test.go'), 'package store\n\n// +alcs:fuzz\nfunc FuzzParse(f *testing.F) {}\n');
+
+      const tests = await scanner.scan(dir, ['export_test.go']);
+
+      expect(Object.keys(tests)).toEqual(['FuzzParse']);
+    });
+  });
+
+  describe('select', () => {
+    const tests = {
+      TestCheckout: ['integration', 'slow'],
+      TestRefund: ['integration'],
+      TestUnit: [],
+    };
+
+    it('should keep tests with any included label', () => {
+      expect(scanner.select(tests, { labels: ['integration'] })).toEqual(['TestCheckout', 'TestRefund']);
+    });
+
+    it('should drop tests with any excluded label', () => {
+      expect(scanner.select(tests, { exclude_labels: ['slow'] })).toEqual(['TestRefund', 'TestUnit']);
+      expect(scanner.select(tests, { labels: ['integration'], exclude_labels: ['slow'] })).toEqual(['TestRefund']);
+    });
+  });
+});
@@ -0,0 +1,162 @@
+/**
+ * Unit Tests for Test Result Cache
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { TestResultCache, CachedPackageResult, CacheKeyInputs } from '../../src/services/testResultCache';
+import { GoPackageInfo } from '../../src/services/goPackageSelector';
+
+jest.mock('../../src/services/loggerService');
+
+describe('TestResultCache', () => {
+  let root: string;
+  let cache: TestResultCache;
+  let packages: GoPackageInfo[];
+  const inputs: CacheKeyInputs = { goVersion: 'go1.22.5', flags: ['-v', '-json', '-cover'] };
+
+  const passing: CachedPackageResult = {
+    package: 'example.com/mod/store',
+    test_cases: [
+      { package: 'example.com/mod/store', name: 'TestGet', status: 'passed', duration_ms: 4, output: '' },
+      { package: 'example.com/mod/store', name: 'TestSlow', status: 'skipped', duration_ms: 0, output: '' },
+    ],
+    stored_at: '2024-01-01T00:00:00.000Z',
+  };
+
+  async function writeFile(rel: string, content: string): Promise<void> {
+    await fs.mkdir(path.dirname(path.join(root, rel)), { recursive: true });
+    await fs.writeFile(path.join(root, rel), content);
+  }
+
+  beforeEach(async () => {
+    root = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-cache-'));
+    cache = new TestResultCache(path.join(root, '.cache'));
+
+    await writeFile('go.mod', 'module example.com/mod\n');
+    await writeFile('util/util.go', 'package util\n');
+    await writeFile('store/store.go', 'package store\n');
+    await writeFile('store/store_test.go', 'package store\n');
+    await writeFile('report/report.go', 'package report\n');
+    await writeFile('report/report_test.go', 'package report\n');
+
+    // store imports util; report imports util only from its tests
+    packages = [
+      { ImportPath: 'example.com/mod/util', Dir: path.join(root, 'util'), GoFiles: ['util.go'] },
+      {
+        ImportPath: 'example.com/mod/store',
+        Dir: path.join(root, 'store'),
+        GoFiles: ['store.go'],
+        TestGoFiles: ['store_test.go'],
+        Imports: ['example.com/mod/util', 'fmt'],
+      },
+      {
+        ImportPath: 'example.com/mod/report',
+        Dir: path.join(root, 'report'),
+        GoFiles: ['report.go'],
+        TestGoFiles: ['report_test.go'],
+        TestImports: ['example.com/mod/util'],
+      },
+    ];
+  });
+
+  afterEach(async () => {
+    await fs.rm(root, { recursive: true, force: true });
+  });
+
+  describe('get/put', () => {
+    it('should round-trip a passing result', async () => {
+      expect(await cache.put('abc123', passing)).toBe(true);
+      expect(await cache.get('abc123')).toEqual(passing);
+    });
+
+    it('should miss on unknown keys', async () => {
+      expect(await cache.get('missing')).toBeUndefined();
+    });
+
+    it('should never store failures or flaky passes', async () => {
+      const failed = {
+        ...passing,
+        test_cases: [{ ...passing.test_cases[0], status: 'failed' as const }],
+      };
+      const flaky = {
+        ...passing,
+        test_cases: [{ ...passing.test_cases[0], flaky: true }],
+      };
+
+      expect(await cache.put('failed', failed)).toBe(false);
+      expect(await cache.put('flaky', flaky)).toBe(false);
+      expect(await cache.get('failed')).toBeUndefined();
+      expect(await cache.get('flaky')).toBeUndefined();
+    });
+
+    it('should treat corrupt entries as misses', async () => {
+      await writeFile('.cache/co/corrupt.json', '{not json');
+
+      expect(await cache.get('corrupt')).toBeUndefined();
+    });
+  });
+
+  describe('computeKeys', () => {
+    it('should be stable when nothing changes', async () => {
+      const first = await cache.computeKeys(root, packages, inputs);
+      const second = await cache.computeKeys(root, packages, inputs);
+
+      expect(second).toEqual(first);
+      expect(new Set(first.values()).size).toBe(3);
+    });
+
+    it('should invalidate dependents when a dependency changes', async () => {
+      const before = await cache.computeKeys(root, packages, inputs);
+      await writeFile('util/util.go', 'package util\n\nfunc Changed() {}\n');
+      const after = await cache.computeKeys(root, packages, inputs);
+
+      expect(after.get('example.com/mod/util')).not.toBe(before.get('example.com/mod/util'));
+      expect(after.get('example.com/mod/store')).not.toBe(before.get('example.com/mod/store'));
+      expect(after.get('example.com/mod/report')).not.toBe(before.get('example.com/mod/report'));
+    });
+
+    it('should only invalidate the package whose tests changed', async () => {
+      const before = await cache.computeKeys(root, packages, inputs);
+      await writeFile('store/store_test.go', 'package store\n\n// new case\n');
+      const after = await cache.computeKeys(root, packages, inputs);
+
+      expect(after.get('example.com/mod/store')).not.toBe(before.get('example.com/mod/store'));
+      expect(after.get('example.com/mod/util')).toBe(before.get('example.com/mod/util'));
+      expect(after.get('example.com/mod/report')).toBe(before.get('example.com/mod/report'));
+    });
+
+    it('should invalidate the package whose testdata changed', async () => {
+      await writeFile('store/testdata/golden/get.json', '{"id": 1}\n');
+      const before = await cache.computeKeys(root, packages, inputs);
+      await writeFile('store/testdata/golden/get.json', '{"id": 2}\n');
+      const edited = await cache.computeKeys(root, packages, inputs);
+      await writeFile('store/testdata/fixture.sql', 'SELECT 1;\n');
+      const added = await cache.computeKeys(root, packages, inputs);
+
+      expect(edited.get('example.com/mod/store')).not.toBe(before.get('example.com/mod/store'));
+      expect(added.get('example.com/mod/store')).not.toBe(edited.get('example.com/mod/store'));
+      expect(added.get('example.com/mod/util')).toBe(before.get('example.com/mod/util'));
+      expect(added.get('example.com/mod/report')).toBe(before.get('example.com/mod/report'));
+    });
+
+    it('should invalidate every key when flags, tags, or the toolchain change', async () => {
+      const before = await cache.computeKeys(root, packages, inputs);
+      const variants = [
+        { ...inputs, flags: [...inputs.flags, '-race'] },
+        { ...inputs, flags: [...inputs.flags, '-tags=integration'] },
+        { ...inputs, goVersion: 'go1.23.0' },
+        { ...inputs, env: { CGO_ENABLED: '0' } },
+      ];
+
+      for (const variant of variants) {
+        const after = await cache.computeKeys(root, packages, variant);
+        for (const [pkg, key] of after) {
+          expect(key).not.toBe(before.get(pkg));
+        }
+      }
+    });
+
+  });
+});
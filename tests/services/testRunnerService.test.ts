@@ -144,7 +144,8 @@ describe('TestRunnerService', () => {
           memory_limit_mb: 512,
           cpu_limit: 1.0,
           enable_network: false,
-        })
+        }),
+        expect.any(AbortSignal)
       );
     });
 
@@ -163,9 +164,34 @@ describe('TestRunnerService', () => {
         expect.objectContaining({
           timeout_seconds: 60,
           memory_limit_mb: 256,
-        })
+        }),
+        expect.any(AbortSignal)
       );
     });
+
+    it('should pass other options through to the runner', async () => {
+      const executeSpy = jest.spyOn(mockRunner, 'execute');
+
+      await service.executeTests(codeArtifact, testArtifact, 'pytest', { parallel: 4, since_ref: 'origin/main' });
+
+      expect(executeSpy.mock.calls[0][3]).toEqual(expect.objectContaining({ parallel: 4, since_ref: 'origin/main' }));
+    });
+
+    it('should abort in-flight runs on cancelAll', async () => {
+      const executeSpy = jest.spyOn(mockRunner, 'execute');
+
+      await service.executeTests(codeArtifact, testArtifact, 'pytest');
+      const signalOf = (call: number) => (executeSpy.mock.calls[call] as unknown[])[4] as AbortSignal;
+      const signal = signalOf(0);
+      expect(signal.aborted).toBe(false);
+
+      service.cancelAll();
+      expect(signal.aborted).toBe(true);
+
+      // Later runs get a fresh signal
+      await service.executeTests(codeArtifact, testArtifact, 'pytest');
+      expect(signalOf(1).aborted).toBe(false);
+    });
   });
 
   describe('mapFailuresToDefects', () => {
@@ -0,0 +1,175 @@
+/**
+ * Unit Tests for Ginkgo Runner
+ */
+
+import { GinkgoRunner } from '../../../src/services/testRunners/ginkgoRunner';
+import * as child_process from 'child_process';
+import * as fs from 'fs/promises';
+
+jest.mock('child_process');
+jest.mock('fs/promises');
+jest.mock('../../../src/services/loggerService');
+jest.mock('../../../src/services/coverageParser');
+
+// Import mocked coverageParser
+import { coverageParser } from '../../../src/services/coverageParser';
+
+// Trimmed from a real `ginkgo --json-report` run
+const sampleReport = JSON.stringify([
+  {
+    SuitePath: '/workspace/calc',
+    SuiteDescription: 'Calc Suite',
+    SuiteSucceeded: false,
+    SpecReports: [
+      {
+        LeafNodeType: 'BeforeSuite',
+        LeafNodeText: '',
+        State: 'passed',
+        RunTime: 1000000,
+        NumAttempts: 1,
+      },
+      {
+        ContainerHierarchyTexts: ['Calculator', 'when adding'],
+        LeafNodeType: 'It',
+        LeafNodeText: 'sums two numbers',
+        State: 'passed',
+        RunTime: 2500000,
+        NumAttempts: 1,
+      },
+      {
+        ContainerHierarchyTexts: ['Calculator', 'when dividing'],
+        LeafNodeType: 'It',
+        LeafNodeText: 'rejects zero',
+        State: 'failed',
+        RunTime: 4000000,
+        NumAttempts: 1,
+        CapturedGinkgoWriterOutput: 'dividing by zero\n',
+        Failure: {
+          Message: 'Expected an error',
+          Location: { FileName: '/workspace/calc/calc_test.go', LineNumber: 42 },
+        },
+      },
+      {
+        ContainerHierarchyTexts: ['Calculator'],
+        LeafNodeType: 'It',
+        LeafNodeText: 'handles overflow',
+        State: 'panicked',
+        RunTime: 1000000,
+        NumAttempts: 1,
+        Failure: { Message: 'Test Panicked', ForwardedPanic: 'runtime error: integer overflow' },
+      },
+      {
+        ContainerHierarchyTexts: ['Calculator'],
+        LeafNodeType: 'It',
+        LeafNodeText: 'supports complex numbers',
+        State: 'pending',
+        RunTime: 0,
+        NumAttempts: 0,
+      },
+      {
+        ContainerHierarchyTexts: ['Calculator', 'under load'],
+        LeafNodeType: 'It',
+        LeafNodeText: 'stays responsive',
+        State: 'passed',
+        RunTime: 9000000,
+        NumAttempts: 3,
+      },
+    ],
+  },
+]);
+
+describe('GinkgoRunner', () => {
+  let runner: GinkgoRunner;
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    runner = new GinkgoRunner();
+  });
+
+  describe('parseReport', () => {
+    it('should preserve the container hierarchy', () => {
+      const cases = runner.parseReport(sampleReport);
+
+      expect(cases[0]).toEqual(expect.objectContaining({
+        package: '/workspace/calc',
+        name: 'Calculator when adding sums two numbers',
+        status: 'passed',
+        duration_ms: 3,
+        hierarchy: ['Calculator', 'when adding'],
+      }));
+    });
+
+    it('should skip passing setup nodes', () => {
+      const cases = runner.parseReport(sampleReport);
+
+      expect(cases).toHaveLength(5);
+      expect(cases.some(c => c.name.includes('BeforeSuite'))).toBe(false);
+    });
+
+    it('should map spec states', () => {
+      const cases = runner.parseReport(sampleReport);
+      const byLeaf = (leaf: string) => cases.find(c => c.name.endsWith(leaf))!;
+
+      expect(byLeaf('rejects zero')).toEqual(expect.objectContaining({
+        status: 'failed',
+        failure_message: 'Expected an error',
+        output: 'dividing by zero\n',
+      }));
+      expect(byLeaf('handles overflow')).toEqual(expect.objectContaining({
+        status: 'error',
+        failure_message: 'panic: runtime error: integer overflow',
+      }));
+      expect(byLeaf('supports complex numbers')).toEqual(expect.objectContaining({
+        status: 'skipped',
+        failure_message: 'pending',
+      }));
+    });
+
+    it('should mark retried specs that eventually passed as flaky', () => {
+      const cases = runner.parseReport(sampleReport);
+      const flaky = cases.find(c => c.name.endsWith('stays responsive'))!;
+
+      expect(flaky.attempts).toBe(3);
+      expect(flaky.flaky).toBe(true);
+    });
+
+    it('should throw on invalid JSON', () => {
+      expect(() => runner.parseReport('not json')).toThrow();
+    });
+  });
+
+  describe('execute', () => {
+    it('should run ginkgo and summarize the report', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      const error = new Error('exit status 1');
+      (error as any).code = 1;
+      (error as any).stdout = '';
+      (error as any).stderr = '';
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(error));
+
+      (fs.readFile as jest.Mock).mockResolvedValue(sampleReport);
+      (fs.access as jest.Mock).mockResolvedValue(undefined);
+      (coverageParser.parseGoCoverageProfile as jest.Mock).mockResolvedValue({
+        line_coverage: 72,
+        branch_coverage: 72,
+        function_coverage: 72,
+        lines_covered: 72,
+        lines_total: 100,
+        uncovered_lines: [],
+      });
+
+      const result = await runner.execute('/workspace', '/workspace/calc/calc.go', '/workspace/calc/calc_test.go', {});
+
+      expect(mockExecFile.mock.calls[0][0]).toBe('ginkgo');
+      expect(mockExecFile.mock.calls[0][1]).toEqual(expect.arrayContaining(['-r', '--json-report=/workspace/reports/ginkgo-report.json']));
+      expect(result.success).toBe(false);
+      expect(result.passed_tests).toBe(2);
+      expect(result.failed_tests).toBe(2);
+      expect(result.coverage_percentage).toBe(72);
+      expect(result.failures.map(f => f.error_message)).toEqual([
+        'Expected an error',
+        'panic: runtime error: integer overflow',
+      ]);
+    });
+  });
+});
@@ -0,0 +1,82 @@
+/**
+ * Unit Tests for Go Test Event Stream
+ */
+
+import { GoTestEvent, GoTestEventStream } from '../../../src/services/testRunners/goTestEventStream';
+
+jest.mock('../../../src/services/loggerService');
+
+describe('GoTestEventStream', () => {
+  let events: GoTestEvent[];
+  let stream: GoTestEventStream;
+
+  beforeEach(() => {
+    events = [];
+    stream = new GoTestEventStream([{ handleEvent: event => events.push(event) }]);
+  });
+
+  it('should emit events for lines split across chunks', () => {
+    const line = JSON.stringify({ Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.25 });
+
+    stream.writeStdout(line.slice(0, 20));
+    expect(events).toHaveLength(0);
+
+    stream.writeStdout(line.slice(20) + '\n');
+    expect(events).toEqual([{
+      package: 'example.com/calc',
+      test: 'TestAdd',
+      action: 'pass',
+      elapsed_ms: 250,
+      output: undefined,
+      stream: 'stdout',
+    }]);
+  });
+
+  it('should interleave stderr build output in arrival order', () => {
+    stream.writeStdout(JSON.stringify({ Action: 'start', Package: 'example.com/calc' }) + '\n');
+    stream.writeStderr('# example.com/calc_test [example.com/calc.test]\n./calc_test.go:9:2: undefined: Sub\n');
+    stream.writeStdout(JSON.stringify({ Action: 'fail', Package: 'example.com/calc', Elapsed: 0 }) + '\n');
+
+    expect(events.map(e => `${e.stream} ${e.action} ${e.package}`)).toEqual([
+      'stdout start example.com/calc',
+      'stderr output example.com/calc',
+      'stderr output example.com/calc',
+      'stdout fail example.com/calc',
+    ]);
+    expect(events[2]).toEqual(expect.objectContaining({ output: './calc_test.go:9:2: undefined: Sub\n', build: true }));
+  });
+
+  it('should map Go 1.24 build-output events to their package', () => {
+    stream.writeStdout([
+      JSON.stringify({ ImportPath: 'example.com/calc [example.com/calc.test]', Action: 'build-output', Output: './calc.go:3:1: syntax error\n' }),
+      JSON.stringify({ ImportPath: 'example.com/calc [example.com/calc.test]', Action: 'build-fail' }),
+    ].join('\n') + '\n');
+
+    expect(events).toEqual([{
+      package: 'example.com/calc',
+      action: 'output',
+      output: './calc.go:3:1: syntax error\n',
+      stream: 'stdout',
+      build: true,
+    }]);
+  });
+
+  it('should pass non-JSON lines through as output and flush on end', () => {
+    stream.writeStdout('go: downloading example.com/dep v1.0.0\npartial');
+    stream.end();
+
+    expect(events.map(e => e.output)).toEqual(['go: downloading example.com/dep v1.0.0\n', 'partial\n']);
+  });
+
+  it('should keep dispatching when a handler throws', () => {
+    const received: GoTestEvent[] = [];
+    stream = new GoTestEventStream([
+      { handleEvent: () => { throw new Error('boom'); } },
+      { handleEvent: event => received.push(event) },
+    ]);
+
+    stream.writeStdout(JSON.stringify({ Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' }) + '\n');
+
+    expect(received).toHaveLength(1);
+  });
+});
@@ -0,0 +1,106 @@
+/**
+ * Unit Tests for Go Test Executors
+ */
+
+import * as fs from 'fs/promises';
+import { GoToolchainExecutor, PrebuiltBinaryExecutor } from '../../../src/services/testRunners/goTestExecutor';
+import { goPackageSelector } from '../../../src/services/goPackageSelector';
+
+jest.mock('fs/promises');
+jest.mock('../../../src/services/loggerService');
+
+const dirent = (name: string, dir: boolean = false) => ({ name, isDirectory: () => dir });
+
+describe('GoToolchainExecutor', () => {
+  const executor = new GoToolchainExecutor();
+
+  it('should build a go test command', () => {
+    const command = executor.command({
+      packages: ['example.com/calc'],
+      flags: ['-v', '-json', '-cover'],
+      run: '^(TestAdd)$',
+      coverProfile: '/ws/reports/coverage-0.out',
+    });
+
+    expect(command.argv).toEqual([
+      'go', 'test', '-v', '-json', '-cover', '-coverprofile=/ws/reports/coverage-0.out', '-run', '^(TestAdd)$', 'example.com/calc',
+    ]);
+    expect(command.decoder).toBeUndefined();
+  });
+
+  it('should resolve patterns to import paths with go list', async () => {
+    const listPackages = jest.spyOn(goPackageSelector, 'listPackages').mockImplementation(async (_root, _tags, patterns = []) =>
+      patterns.includes('./...')
+        ? [{ ImportPath: 'example.com/a', Dir: '/ws/a' }, { ImportPath: 'example.com/a/b', Dir: '/ws/a/b' }]
+        : [{ ImportPath: 'example.com/a/b', Dir: '/ws/a/b' }]
+    );
+
+    try {
+      await expect(executor.resolvePackages('/ws', ['./...'])).resolves.toEqual(['example.com/a', 'example.com/a/b']);
+      await expect(executor.resolvePackages('/ws', ['./a/b/...'], ['e2e'])).resolves.toEqual(['example.com/a/b']);
+      expect(listPackages).toHaveBeenLastCalledWith('/ws', ['e2e'], ['./a/b/...']);
+      await expect(executor.resolvePackages('/ws', [])).resolves.toEqual([]);
+      expect(listPackages).toHaveBeenCalledTimes(2);
+    } finally {
+      jest.restoreAllMocks();
+    }
+  });
+});
+
+describe('PrebuiltBinaryExecutor', () => {
+  const executor = new PrebuiltBinaryExecutor('/bins');
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  it('should translate go test flags into -test. flags', () => {
+    const command = executor.command({
+      packages: ['example.com/calc'],
+      flags: ['-v', '-json', '-cover', '-race', '-count=1'],
+      run: '^(TestAdd|TestSub)$',
+      coverProfile: '/ws/reports/coverage-0.out',
+    });
+
+    expect(command.argv).toEqual([
+      '/bins/example.com/calc.test',
+      '-test.v=true',
+      '-test.count=1',
+      '-test.coverprofile=/ws/reports/coverage-0.out',
+      '-test.run=^(TestAdd|TestSub)$',
+    ]);
+    expect(command.cwd).toBe('/bins/example.com');
+    expect(command.decoder).toBeDefined();
+  });
+
+  it('should refuse to run several packages in one binary', () => {
+    expect(() => executor.command({ packages: ['a', 'b'], flags: [] })).toThrow('exactly one package');
+  });
+
+  it('should list binaries under the directory as package labels', async () => {
+    (fs.readdir as jest.Mock).mockImplementation(async (dir: string) => {
+      switch (dir) {
+        case '/bins':
+          return [dirent('example.com', true), dirent('tools.test'), dirent('README.md')];
+        case '/bins/example.com':
+          return [dirent('mod', true)];
+        case '/bins/example.com/mod':
+          return [dirent('api.test'), dirent('store.test')];
+        default:
+          return [];
+      }
+    });
+
+    try {
+      await expect(executor.resolvePackages('/ws', ['./...'])).resolves.toEqual([
+        'example.com/mod/api', 'example.com/mod/store', 'tools',
+      ]);
+      await expect(executor.resolvePackages('/ws', ['example.com/mod/...'])).resolves.toEqual([
+        'example.com/mod/api', 'example.com/mod/store',
+      ]);
+      await expect(executor.resolvePackages('/ws', ['tools'])).resolves.toEqual(['tools']);
+    } finally {
+      (fs.readdir as jest.Mock).mockReset();
+    }
+  });
+});
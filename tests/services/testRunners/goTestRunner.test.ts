@@ -0,0 +1,2151 @@
+/**
+ * Unit Tests for Go Test Runner
+ */
+
+import { GoTestRunner } from '../../../src/services/testRunners/goTestRunner';
+import { RunSummary, TestExecutionOptions } from '../../../src/types/mcp';
+import * as child_process from 'child_process';
+import * as fs from 'fs/promises';
+import { EventEmitter } from 'events';
+
+jest.mock('child_process');
+jest.mock('fs/promises');
+jest.mock('../../../src/services/loggerService');
+jest.mock('../../../src/services/coverageParser');
+
+// Import mocked coverageParser
+import { coverageParser } from '../../../src/services/coverageParser';
+import { goPackageSelector } from '../../../src/services/goPackageSelector';
+import { TestResultCache } from '../../../src/services/testResultCache';
+import { sandboxService } from '../../../src/services/sandboxService';
+import { lintService } from '../../../src/services/lintService';
+import { webhookService } from '../../../src/services/webhookService';
+import { testLabelScanner } from '../../../src/services/testLabelScanner';
+import { FileResultStore } from '../../../src/services/resultStore';
+import { goroutineLeakCheck } from '../../../src/services/goroutineLeakCheck';
+import { DependencyUnhealthyError, serviceContainers } from '../../../src/services/serviceContainers';
+import { goFrameworkDetector } from '../../../src/services/goFrameworkDetector';
+import { GinkgoRunner } from '../../../src/services/testRunners/ginkgoRunner';
+import { GoFramework } from '../../../src/types/mcp';
+import { logger } from '../../../src/services/loggerService';
+
+/**
+ * Build go test -json output from event objects
+ */
+function jsonEvents(events: Record<string, any>[]): string {
+  return events.map(e => JSON.stringify(e)).join('\n') + '\n';
+}
+
+/**
+ * Fake a spawned go process that writes the given output and exits
+ */
+function fakeGoProcess(stdout: string, exitCode: number = 0, stderr: string = ''): any {
+  const child: any = new EventEmitter();
+  child.stdout = new EventEmitter();
+  child.stderr = new EventEmitter();
+  child.pid = 4242;
+  child.kill = jest.fn();
+
+  setImmediate(() => {
+    if (stderr) {
+      child.stderr.emit('data', Buffer.from(stderr));
+    }
+    child.stdout.emit('data', Buffer.from(stdout));
+    child.emit('close', exitCode);
+  });
+
+  return child;
+}
+
+describe('GoTestRunner', () => {
+  let runner: GoTestRunner;
+  const mockSpawn = child_process.spawn as unknown as jest.Mock;
+
+  const workspacePath = '/tmp/test-workspace';
+  const codeFilePath = '/tmp/test-workspace/calc.go';
+  const testFilePath = '/tmp/test-workspace/calc_test.go';
+  const options: TestExecutionOptions = {
+    timeout_seconds: 300,
+  };
+
+  const passingRun = jsonEvents([
+    { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+    { Action: 'output', Package: 'example.com/calc', Test: 'TestAdd', Output: '=== RUN   TestAdd\n' },
+    { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+    { Action: 'skip', Package: 'example.com/calc', Test: 'TestSlow', Elapsed: 0 },
+    { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.02 },
+  ]);
+
+  const failingRun = jsonEvents([
+    { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+    { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+    { Action: 'run', Package: 'example.com/calc', Test: 'TestTiming' },
+    { Action: 'output', Package: 'example.com/calc', Test: 'TestTiming', Output: '    calc_test.go:30: Error: too slow\n' },
+    { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.5 },
+    { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.6 },
+  ]);
+
+  const timingPass = jsonEvents([
+    { Action: 'pass', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+    { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.1 },
+  ]);
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    runner = new GoTestRunner();
+
+    (fs.access as jest.Mock).mockResolvedValue(undefined);
+    (coverageParser.parseGoCoverageProfile as jest.Mock).mockResolvedValue({
+      line_coverage: 80,
+      branch_coverage: 80,
+      function_coverage: 80,
+      lines_covered: 8,
+      lines_total: 10,
+      uncovered_lines: [],
+    });
+  });
+
+  describe('execute', () => {
+    it('should parse JSON events into per-test results', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+      expect(result.success).toBe(true);
+      expect(result.passed_tests).toBe(1);
+      expect(result.coverage_percentage).toBe(80);
+      expect(result.test_cases).toEqual([
+        expect.objectContaining({ package: 'example.com/calc', name: 'TestAdd', status: 'passed', duration_ms: 10 }),
+        expect.objectContaining({ package: 'example.com/calc', name: 'TestSlow', status: 'skipped' }),
+      ]);
+    });
+
+    it('should report build failures as errors', async () => {
+      const stdout = jsonEvents([
+        { Action: 'output', Package: 'example.com/broken', Output: 'FAIL\texample.com/broken [build failed]\n' },
+        { Action: 'fail', Package: 'example.com/broken', Elapsed: 0 },
+      ]);
+      mockSpawn.mockImplementation(() => fakeGoProcess(stdout, 1, './broken.go:3:1: syntax error: unexpected }'));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+      expect(result.success).toBe(false);
+      expect(result.test_cases).toEqual([
+        expect.objectContaining({
+          package: 'example.com/broken',
+          name: '[build failed]',
+          status: 'error',
+          output: './broken.go:3:1: syntax error: unexpected }',
+        }),
+      ]);
+    });
+
+    it('should attribute compiler errors to the package that failed to build', async () => {
+      const stdout = jsonEvents([
+        { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.01 },
+        { Action: 'output', Package: 'example.com/broken', Output: 'FAIL\texample.com/broken [build failed]\n' },
+        { Action: 'fail', Package: 'example.com/broken', Elapsed: 0, FailedBuild: 'example.com/broken [example.com/broken.test]' },
+      ]);
+      const stderr = '# example.com/broken [example.com/broken.test]\n./broken.go:3:1: syntax error: unexpected }\n';
+      mockSpawn.mockImplementation(() => fakeGoProcess(stdout, 1, stderr));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+      expect(result.test_cases![0].output).toBe(stderr);
+    });
+
+    it('should report broken packages and their dependents without stopping independent packages', async () => {
+      const stdout = jsonEvents([
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+        { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.01 },
+        { Action: 'output', Package: 'example.com/broken', Output: 'FAIL\texample.com/broken [build failed]\n' },
+        { Action: 'fail', Package: 'example.com/broken', Elapsed: 0, FailedBuild: 'example.com/broken [example.com/broken.test]' },
+        { Action: 'output', Package: 'example.com/api', Output: 'FAIL\texample.com/api [build failed]\n' },
+        { Action: 'fail', Package: 'example.com/api', Elapsed: 0, FailedBuild: 'example.com/broken [example.com/api.test]' },
+      ]);
+      const stderr = '# example.com/broken\n./broken.go:3:1: syntax error: unexpected }\n./broken.go:9:5: undefined: x\n';
+      mockSpawn.mockImplementation(() => fakeGoProcess(stdout, 1, stderr));
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: '', stderr: '' }));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          summary_json_path: '/tmp/reports/summary.json',
+        });
+
+        expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.status}`)).toEqual([
+          'example.com/calc TestAdd passed',
+          'example.com/broken [build failed] error',
+          'example.com/api [build failed] error',
+        ]);
+        expect(result.test_cases![1].failure_message).toBe('Build failed: ./broken.go:3:1: syntax error: unexpected }');
+        expect(result.test_cases![2].failure_message).toBe('Build failed: dependency example.com/broken does not compile');
+        expect(result.build_failures).toEqual([
+          {
+            package: 'example.com/broken',
+            errors: [
+              { file: './broken.go', line: 3, column: 1, message: 'syntax error: unexpected }' },
+              { file: './broken.go', line: 9, column: 5, message: 'undefined: x' },
+            ],
+            caused_by: undefined,
+          },
+          expect.objectContaining({ package: 'example.com/api', caused_by: 'example.com/broken' }),
+        ]);
+        expect(result.failures.map(f => f.test_name)).not.toContain('TestAdd');
+
+        const written = (fs.writeFile as jest.Mock).mock.calls.find(([file]) => file === '/tmp/reports/summary.json');
+        const summary = JSON.parse(written[1]);
+        expect(summary.build_failures.map((f: any) => f.package)).toEqual(['example.com/broken', 'example.com/api']);
+      } finally {
+        mockExecFile.mockReset();
+      }
+    });
+
+    it('should record a panic on the test that raised it and mark the rest of the package not run', async () => {
+      const stack = [
+        'goroutine 7 [running]:\n',
+        'testing.tRunner.func1.2({0x5f8e40, 0xc000018030})\n',
+        '\t/usr/local/go/src/testing/testing.go:1545 +0x238\n',
+        'example.com/calc.TestIndex(0xc000007860)\n',
+        '\t/src/calc/calc_test.go:14 +0x1d\n',
+      ];
+      const panicOutput = [
+        '--- FAIL: TestIndex (0.00s)\n',
+        'panic: runtime error: index out of range [3] with length 3 [recovered]\n',
+        '\tpanic: runtime error: index out of range [3] with length 3\n',
+        '\n',
+        ...stack,
+      ];
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+        { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestParallel' },
+        { Action: 'pause', Package: 'example.com/calc', Test: 'TestParallel' },
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestIndex' },
+        ...panicOutput.map(Output => ({ Action: 'output', Package: 'example.com/calc', Test: 'TestIndex', Output })),
+        { Action: 'fail', Package: 'example.com/calc', Test: 'TestIndex', Elapsed: 0 },
+        { Action: 'output', Package: 'example.com/calc', Output: 'FAIL\texample.com/calc\t0.012s\n' },
+        { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.012 },
+      ]), 1));
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+      ]);
+      jest.spyOn(testLabelScanner, 'scan').mockResolvedValue({
+        TestAdd: [], TestParallel: [], TestIndex: [], TestSub: [], ExampleAdd: [],
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+        expect(result.test_cases!.map(c => `${c.name} ${c.status}`)).toEqual([
+          'TestAdd passed',
+          'TestParallel not_run',
+          'TestIndex error',
+          'ExampleAdd not_run',
+          'TestSub not_run',
+        ]);
+        const panicked = result.test_cases!.find(c => c.name === 'TestIndex')!;
+        expect(panicked.panic).toEqual({
+          message: 'runtime error: index out of range [3] with length 3',
+          stack: stack.join(''),
+        });
+        expect(panicked.failure_message).toBe('panic: runtime error: index out of range [3] with length 3');
+        expect(result.test_cases!.find(c => c.name === 'TestSub')!.failure_message).toBe('Not run: TestIndex panicked');
+        expect(result.failed_tests).toBe(1);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should stream events to subscribers as they arrive', async () => {
+      const events: any[] = [];
+      runner = new GoTestRunner([{ handleEvent: event => events.push(event) }]);
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+
+      await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+      expect(events.map(e => `${e.action} ${e.test || ''}`.trim())).toEqual([
+        'run TestAdd',
+        'output TestAdd',
+        'pass TestAdd',
+        'skip TestSlow',
+        'pass',
+      ]);
+      expect(events[2]).toEqual(expect.objectContaining({
+        package: 'example.com/calc',
+        test: 'TestAdd',
+        elapsed_ms: 10,
+        stream: 'stdout',
+      }));
+    });
+
+    it('should reject when go exits with an unexpected code', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess('', 2, 'flag provided but not defined: -bogus\n'));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+      expect(result.success).toBe(false);
+      expect(result.failures[0].test_name).toBe('go_test_execution');
+      expect(result.stderr).toContain('flag provided but not defined');
+    });
+
+    it('should skip packages with cached passing results', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: 'go1.22.5\n', stderr: '' }));
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+      ]);
+      jest.spyOn(TestResultCache.prototype, 'computeKeys').mockResolvedValue(new Map([
+        ['example.com/calc', 'calc-key'],
+        ['example.com/store', 'store-key'],
+      ]));
+      jest.spyOn(TestResultCache.prototype, 'get').mockImplementation(async key => key === 'calc-key'
+        ? {
+          package: 'example.com/calc',
+          test_cases: [{ package: 'example.com/calc', name: 'TestAdd', status: 'passed' as const, duration_ms: 10, output: '' }],
+          stored_at: '2024-01-01T00:00:00.000Z',
+        }
+        : undefined);
+      const put = jest.spyOn(TestResultCache.prototype, 'put').mockResolvedValue(true);
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'pass', Package: 'example.com/store', Test: 'TestGet', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/store', Elapsed: 0.01 },
+      ])));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          cache_dir: '/tmp/alcs-cache',
+        });
+
+        const args = mockSpawn.mock.calls[0][1];
+        expect(args).toContain('example.com/store');
+        expect(args).not.toContain('example.com/calc');
+        expect(result.passed_tests).toBe(2);
+        expect(result.test_cases).toEqual(expect.arrayContaining([
+          expect.objectContaining({ package: 'example.com/calc', name: 'TestAdd', cached: true }),
+        ]));
+        expect(put).toHaveBeenCalledWith('store-key', expect.objectContaining({ package: 'example.com/store' }));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should resolve relative package patterns before the cache and framework detection', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: 'go1.22.5\n', stderr: '' }));
+      const all = [
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+        { ImportPath: 'example.com/store/sql', Dir: '/tmp/test-workspace/store/sql' },
+      ];
+      jest.spyOn(goPackageSelector, 'listPackages').mockImplementation(async (_root, _tags, patterns = ['./...']) =>
+        all.filter(p => patterns.some(pattern => pattern === './...' || pattern === p.ImportPath
+          || (pattern === './store/...' && p.ImportPath.startsWith('example.com/store'))))
+      );
+      jest.spyOn(goFrameworkDetector, 'resolve').mockResolvedValue(GoFramework.Standard);
+      jest.spyOn(TestResultCache.prototype, 'computeKeys').mockResolvedValue(new Map(all.map(p => [p.ImportPath, `${p.ImportPath}-key`])));
+      jest.spyOn(TestResultCache.prototype, 'get').mockResolvedValue(undefined);
+      jest.spyOn(TestResultCache.prototype, 'put').mockResolvedValue(true);
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'pass', Package: 'example.com/store', Test: 'TestGet', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/store', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/store/sql', Test: 'TestQuery', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/store/sql', Elapsed: 0.01 },
+      ])));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          packages: ['./store/...'],
+          cache_dir: '/tmp/alcs-cache',
+          detect_framework: true,
+        });
+
+        const args = mockSpawn.mock.calls[0][1];
+        expect(args).toEqual(expect.arrayContaining(['example.com/store', 'example.com/store/sql']));
+        expect(args).not.toContain('example.com/calc');
+        expect(result.passed_tests).toBe(2);
+      } finally {
+        mockExecFile.mockReset();
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should bypass the cache with no_cache', async () => {
+      const listPackages = jest.spyOn(goPackageSelector, 'listPackages');
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+
+      try {
+        await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          cache_dir: '/tmp/alcs-cache',
+          no_cache: true,
+        });
+
+        expect(listPackages).not.toHaveBeenCalled();
+        expect(mockSpawn.mock.calls[0][1]).toContain('./...');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should record data races alongside assertion failures with race enabled', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: '1\n', stderr: '' }));
+      const raceOutput = [
+        '==================\n',
+        'WARNING: DATA RACE\n',
+        'Write at 0x00c0000a4010 by goroutine 8:\n',
+        '  example.com/calc.(*Counter).Inc()\n',
+        '\n',
+        'Previous read at 0x00c0000a4010 by goroutine 7:\n',
+        '  example.com/calc.(*Counter).Value()\n',
+        '==================\n',
+      ];
+      const events = (test: string, assertion?: string) => [
+        { Action: 'run', Package: 'example.com/calc', Test: test },
+        ...raceOutput.map(Output => ({ Action: 'output', Package: 'example.com/calc', Test: test, Output })),
+        ...(assertion ? [{ Action: 'output', Package: 'example.com/calc', Test: test, Output: assertion }] : []),
+        { Action: 'fail', Package: 'example.com/calc', Test: test, Elapsed: 0.01 },
+      ];
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        ...events('TestCounter', '    counter_test.go:22: Error: expected 2, got 1\n'),
+        ...events('TestRaceOnly'),
+        { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.02 },
+      ]), 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, race: true });
+
+      expect(mockSpawn.mock.calls[0][1]).toContain('-race');
+      expect(result.data_races).toHaveLength(2);
+      expect(result.data_races![0]).toEqual(expect.objectContaining({
+        address: '0x00c0000a4010',
+        package: 'example.com/calc',
+        test: 'TestCounter',
+      }));
+
+      const counter = result.test_cases!.find(c => c.name === 'TestCounter')!;
+      expect(counter.failure_message).toBe('expected 2, got 1');
+      expect(counter.data_races).toHaveLength(1);
+
+      const raceOnly = result.test_cases!.find(c => c.name === 'TestRaceOnly')!;
+      expect(raceOnly.failure_message).toBe('Data race detected');
+    });
+
+    it('should fail clearly when race is requested without cgo', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: '0\n', stderr: '' }));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, race: true });
+
+      expect(result.success).toBe(false);
+      expect(result.failures[0].error_message).toContain('CGO_ENABLED=1');
+      expect(mockSpawn).not.toHaveBeenCalled();
+    });
+
+    it('should report an OOM-killed sandbox as out of memory', async () => {
+      const stdout = jsonEvents([
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestHuge' },
+        { Action: 'output', Package: 'example.com/calc', Output: 'signal: killed\n' },
+        { Action: 'fail', Package: 'example.com/calc', Elapsed: 1.2 },
+      ]);
+      const spawnInSandbox = jest.spyOn(sandboxService, 'spawnInSandbox')
+        .mockImplementation(async () => ({ child: fakeGoProcess(stdout, 137), containerId: 'alcs-test-1' }));
+      jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: true });
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          sandbox: true,
+          memory: '2g',
+        });
+
+        const [config, command, mountPath] = spawnInSandbox.mock.calls[0];
+        expect(config.memory_limit_mb).toBe(2048);
+        expect(command.slice(0, 2)).toEqual(['go', 'test']);
+        expect(mountPath).toBe(workspacePath);
+        expect(result.success).toBe(false);
+        expect(result.out_of_memory).toBe(true);
+        expect(result.failures[0].error_message).toBe('Out of memory: exceeded the 2048MB container memory limit');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should report artifacts copied out of the sandbox container', async () => {
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      jest.spyOn(sandboxService, 'spawnInSandbox')
+        .mockImplementation(async () => ({ child: fakeGoProcess(failingRun, 1), containerId: 'alcs-test-1' }));
+      const finishContainer = jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({
+        oomKilled: false,
+        artifacts: {
+          dir: '/tmp/test-workspace/reports/artifacts/alcs-test-1',
+          warnings: ['Failed to copy artifact /go/traces from alcs-test-1: permission denied'],
+        },
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          sandbox: true,
+          artifacts: ['/go/test-output', '/go/traces'],
+        });
+
+        const config = finishContainer.mock.calls[0][2]!;
+        expect(config.artifact_paths).toEqual(['/go/test-output', '/go/traces']);
+        expect(config.artifacts_dir).toBe('/tmp/test-workspace/reports/artifacts');
+        expect(result.artifact_dirs).toEqual(['/tmp/test-workspace/reports/artifacts/alcs-test-1']);
+        expect(result.artifact_warnings).toHaveLength(1);
+        expect(result.failed_tests).toBe(1);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should inject env and secrets into the test container only', async () => {
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      const executeInSandbox = jest.spyOn(sandboxService, 'executeInSandbox').mockResolvedValue({
+        exitCode: 0, stdout: '1\n', stderr: '', timedOut: false, killedBySignal: false, oomKilled: false,
+      });
+      const spawnInSandbox = jest.spyOn(sandboxService, 'spawnInSandbox')
+        .mockImplementation(async () => ({ child: fakeGoProcess(passingRun), containerId: 'alcs-test-1' }));
+      jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: false });
+      (fs.readFile as jest.Mock).mockResolvedValueOnce('tok-123\n');
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          sandbox: true,
+          race: true,
+          env: ['DATABASE_URL=postgres://db:5432/app'],
+          secret_files: ['API_TOKEN=/run/secrets/api_token'],
+        });
+
+        expect(result.success).toBe(true);
+        expect(fs.readFile).toHaveBeenCalledWith('/run/secrets/api_token', 'utf-8');
+
+        const testConfig = spawnInSandbox.mock.calls[0][0];
+        expect(testConfig.env).toEqual(expect.objectContaining({ DATABASE_URL: 'postgres://db:5432/app' }));
+        expect(testConfig.env).not.toHaveProperty('API_TOKEN');
+        expect(testConfig.secret_env).toEqual({ API_TOKEN: 'tok-123' });
+
+        // The go env check for -race shares the sandbox but not the test's variables
+        const helperConfig = executeInSandbox.mock.calls[0][0];
+        expect(helperConfig.env).not.toHaveProperty('DATABASE_URL');
+        expect(helperConfig.secret_env).toBeUndefined();
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should snapshot the workspace once for a copy mount and remove it afterwards', async () => {
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      const snapshotWorkspace = jest.spyOn(sandboxService, 'snapshotWorkspace').mockResolvedValue('alcs-src-1');
+      const removeWorkspaceSnapshot = jest.spyOn(sandboxService, 'removeWorkspaceSnapshot').mockResolvedValue();
+      const spawnInSandbox = jest.spyOn(sandboxService, 'spawnInSandbox')
+        .mockImplementation(async () => ({ child: fakeGoProcess(passingRun), containerId: 'alcs-test-1' }));
+      jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: false });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          sandbox: true,
+          mount: 'copy',
+          mount_exclude: ['testdata/'],
+        });
+
+        expect(result.success).toBe(true);
+        expect(snapshotWorkspace).toHaveBeenCalledTimes(1);
+        expect(snapshotWorkspace.mock.calls[0].slice(1)).toEqual([workspacePath, workspacePath, ['testdata/']]);
+
+        const config = spawnInSandbox.mock.calls[0][0];
+        expect(config.mount).toBe('copy');
+        expect(config.source_volume).toBe('alcs-src-1');
+        expect(config.output_paths).toEqual(['/tmp/test-workspace/reports', '/tmp/test-workspace/.cache']);
+        expect(removeWorkspaceSnapshot).toHaveBeenCalledWith('alcs-src-1');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should run tests against healthy services on their network and stop them afterwards', async () => {
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      const start = jest.spyOn(serviceContainers, 'start').mockResolvedValue({
+        network: 'alcs-svc-1',
+        containers: ['alcs-svc-1-postgres'],
+        env: { POSTGRES_HOST: 'postgres', POSTGRES_PORT: '5432' },
+      });
+      const stop = jest.spyOn(serviceContainers, 'stop').mockResolvedValue();
+      const spawnInSandbox = jest.spyOn(sandboxService, 'spawnInSandbox')
+        .mockImplementation(async () => ({ child: fakeGoProcess(failingRun, 1), containerId: 'alcs-test-1' }));
+      jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: false });
+      const services = [{ name: 'postgres', image: 'postgres:16', ports: [5432], health_cmd: ['pg_isready'] }];
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          sandbox: true,
+          services,
+          env: ['POSTGRES_PORT=6543'],
+        });
+
+        expect(result.failed_tests).toBe(1);
+        expect(start).toHaveBeenCalledWith(services, { sandbox: true, enableNetwork: undefined });
+        const config = spawnInSandbox.mock.calls[0][0];
+        expect(config.network).toBe('alcs-svc-1');
+        // Explicit env entries win over the injected addresses
+        expect(config.env).toEqual(expect.objectContaining({ POSTGRES_HOST: 'postgres', POSTGRES_PORT: '6543' }));
+        expect(stop).toHaveBeenCalledWith(expect.objectContaining({ network: 'alcs-svc-1' }));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should not run tests when a service is unhealthy', async () => {
+      jest.spyOn(serviceContainers, 'start')
+        .mockRejectedValue(new DependencyUnhealthyError('postgres', 'pg_isready still failing after 60s (60 checks)'));
+      const stop = jest.spyOn(serviceContainers, 'stop').mockResolvedValue();
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          services: [{ name: 'postgres', image: 'postgres:16', health_cmd: ['pg_isready'] }],
+        });
+
+        expect(result.success).toBe(false);
+        expect(result.failures[0].error_message).toBe('Dependency postgres unhealthy: pg_isready still failing after 60s (60 checks)');
+        expect(mockSpawn).not.toHaveBeenCalled();
+        expect(stop).not.toHaveBeenCalled(); // start cleans up after itself
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should fail before running anything on a malformed env entry', async () => {
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, env: ['DATABASE_URL'] });
+
+      expect(result.success).toBe(false);
+      expect(result.failures[0].error_message).toContain('Invalid env entry');
+      expect(mockSpawn).not.toHaveBeenCalled();
+    });
+
+    it('should run the suite once per Go version and flag version-specific failures', async () => {
+      const ensureImage = jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      const spawnInSandbox = jest.spyOn(sandboxService, 'spawnInSandbox').mockImplementation(async config => ({
+        child: fakeGoProcess(config.image === 'golang:1.23-alpine' ? failingRun : jsonEvents([
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.01 },
+          { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.02 },
+        ]), config.image === 'golang:1.23-alpine' ? 1 : 0),
+        containerId: 'alcs-test-1',
+      }));
+      jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: false });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          go_versions: ['1.22', '1.23'],
+        });
+
+        expect(ensureImage.mock.calls.slice(0, 2).map(c => c[0])).toEqual(['golang:1.22-alpine', 'golang:1.23-alpine']);
+        expect(spawnInSandbox.mock.calls.map(c => c[0].image)).toEqual(['golang:1.22-alpine', 'golang:1.23-alpine']);
+        expect(result.success).toBe(false);
+        expect(result.matrix!.runs.map(r => `${r.go_version} ${r.success}`)).toEqual(['1.22 true', '1.23 false']);
+        expect(result.matrix!.tests.filter(t => t.version_specific)).toEqual([{
+          package: 'example.com/calc',
+          name: 'TestTiming',
+          statuses: { '1.22': 'passed', '1.23': 'failed' },
+          version_specific: true,
+        }]);
+        expect(result.failures.map(f => f.test_name)).toEqual(['[go1.23] TestTiming']);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should union coverage across Go versions and keep each version\'s profile', async () => {
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      let image = '';
+      jest.spyOn(sandboxService, 'spawnInSandbox').mockImplementation(async config => {
+        image = config.image;
+        return { child: fakeGoProcess(passingRun), containerId: 'alcs-test-1' };
+      });
+      jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: false });
+      const profiles: Record<string, string> = {
+        'golang:1.22-alpine': 'mode: set\nexample.com/calc/calc.go:3.1,5.2 2 1\nexample.com/calc/calc.go:7.1,9.2 2 0\n',
+        'golang:1.23-alpine': 'mode: set\nexample.com/calc/calc.go:3.1,5.2 2 0\nexample.com/calc/calc.go:7.1,9.2 2 1\n' +
+          'example.com/calc/iter_go123.go:1.1,2.2 1 0\n',
+      };
+      (fs.readFile as jest.Mock).mockImplementation(async (file: string) =>
+        file === '/tmp/test-workspace/reports/coverage.out' ? profiles[image] : undefined);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          go_versions: ['1.22', '1.23'],
+        });
+
+        const unified = result.matrix!.unified_coverage!;
+        expect(unified.percentage).toBe(80);
+        expect(unified.version_specific_files).toEqual(['example.com/calc/iter_go123.go']);
+        expect(unified.profile_path).toBe('/tmp/test-workspace/reports/coverage.out');
+
+        const written = (file: string) => (fs.writeFile as jest.Mock).mock.calls.filter(c => c[0] === file).map(c => c[1]);
+        expect(written('/tmp/test-workspace/reports/coverage-go1.22.out')).toEqual([profiles['golang:1.22-alpine']]);
+        expect(written('/tmp/test-workspace/reports/coverage-go1.23.out')).toEqual([profiles['golang:1.23-alpine']]);
+        expect(written('/tmp/test-workspace/reports/coverage.out').pop()).toBe(
+          'mode: set\nexample.com/calc/calc.go:3.1,5.2 2 1\nexample.com/calc/calc.go:7.1,9.2 2 1\n' +
+          'example.com/calc/iter_go123.go:1.1,2.2 1 0\n'
+        );
+        expect(fs.rm).toHaveBeenCalledWith('/tmp/test-workspace/reports/coverage.out', { force: true });
+      } finally {
+        jest.restoreAllMocks();
+        (fs.readFile as jest.Mock).mockReset();
+      }
+    });
+
+    it('should run packages in parallel with stable ordering and isolate start failures', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/c', Dir: '/tmp/test-workspace/c' },
+      ]);
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => {
+        const pkg = args[args.length - 1];
+        if (pkg === 'example.com/c') {
+          const child: any = new EventEmitter();
+          child.stdout = new EventEmitter();
+          child.stderr = new EventEmitter();
+          setImmediate(() => child.emit('error', new Error('spawn go EAGAIN')));
+          return child;
+        }
+        return fakeGoProcess(jsonEvents([
+          { Action: 'pass', Package: pkg, Test: 'TestOne', Elapsed: 0.01 },
+          { Action: 'pass', Package: pkg, Elapsed: 0.01 },
+        ]));
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, parallel: 2 });
+
+        expect(mockSpawn).toHaveBeenCalledTimes(3);
+        expect(mockSpawn.mock.calls.map(c => c[1][c[1].length - 1])).toEqual(['example.com/a', 'example.com/b', 'example.com/c']);
+        expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.status}`)).toEqual([
+          'example.com/a TestOne passed',
+          'example.com/b TestOne passed',
+          'example.com/c [package] error',
+        ]);
+        expect(result.test_cases![2].output).toContain('spawn go EAGAIN');
+        expect(result.success).toBe(false);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should trace each package through the run log with the run ID', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+      ]);
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => {
+        const pkg = args[args.length - 1];
+        return fakeGoProcess(jsonEvents([{ Action: 'pass', Package: pkg, Elapsed: 0.01 }]), pkg === 'example.com/b' ? 1 : 0);
+      });
+
+      try {
+        await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, parallel: 2 });
+
+        const entries = (logger.log as unknown as jest.Mock).mock.calls;
+        const runId = entries[0][2].run_id;
+        expect(runId).toEqual(expect.any(String));
+        expect(entries.every(e => e[2].run_id === runId)).toBe(true);
+
+        const trace = (pkg: string) => entries.filter(e => e[2].package === pkg).map(e => e[1]);
+        expect(trace('example.com/a')).toEqual(['Package scheduled', 'Package started', 'Package completed']);
+        expect(entries).toContainEqual([
+          'debug', 'Package completed', expect.objectContaining({ package: 'example.com/b', exit_code: 1 }),
+        ]);
+        expect(entries.map(e => e[1])).toContain('Run finished');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should resolve labels to -run names per package and skip packages with none', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+      ]);
+      jest.spyOn(testLabelScanner, 'scan').mockImplementation(async dir => dir.endsWith('store')
+        ? { TestCheckout: ['integration', 'slow'], TestRefund: ['integration'], TestUnit: [] }
+        : { TestAdd: [] });
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'pass', Package: 'example.com/store', Test: 'TestRefund', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/store', Elapsed: 0.01 },
+      ])));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          labels: ['integration'],
+          exclude_labels: ['slow'],
+        });
+
+        expect(mockSpawn).toHaveBeenCalledTimes(1);
+        const args: string[] = mockSpawn.mock.calls[0][1];
+        expect(args).toEqual(expect.arrayContaining(['-run', '^(TestRefund)$', 'example.com/store']));
+        expect(result.test_cases!.map(c => c.name)).toEqual(['TestRefund']);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should run a tag-gated test only when its build tag is supplied', async () => {
+      const listPackages = jest.spyOn(goPackageSelector, 'listPackages').mockImplementation(async (_root, tags = []) => [{
+        ImportPath: 'example.com/store',
+        Dir: '/tmp/test-workspace/store',
+        TestGoFiles: ['store_test.go', ...(tags.includes('integration') ? ['checkout_integration_test.go'] : [])],
+      }]);
+      jest.spyOn(testLabelScanner, 'scan').mockImplementation(async (_dir, files = []) => files.includes('checkout_integration_test.go')
+        ? { TestUnit: [], TestCheckout: ['db'] }
+        : { TestUnit: [] });
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => {
+        const compiled = args.includes('-tags=integration,e2e') ? ['TestUnit', 'TestCheckout'] : ['TestUnit'];
+        const tests = args[args.indexOf('-run') + 1].replace(/^\^\(|\)\$$/g, '').split('|').filter(t => compiled.includes(t));
+        return fakeGoProcess(jsonEvents([
+          ...tests.map(test => ({ Action: 'pass', Package: 'example.com/store', Test: test, Elapsed: 0.01 })),
+          { Action: 'pass', Package: 'example.com/store', Elapsed: 0.01 },
+        ]));
+      });
+
+      try {
+        const untagged = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, labels: ['db'] });
+        expect(mockSpawn).not.toHaveBeenCalled();
+        expect(untagged.test_cases!.map(c => c.name)).not.toContain('TestCheckout');
+
+        const tagged = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          labels: ['db'],
+          tags: ['integration', 'e2e'],
+        });
+        expect(listPackages).toHaveBeenLastCalledWith(workspacePath, ['integration', 'e2e']);
+        expect(mockSpawn.mock.calls[0][1]).toEqual(expect.arrayContaining(['-tags=integration,e2e', '-run', '^(TestCheckout)$']));
+        expect(tagged.test_cases!.map(c => `${c.name} ${c.status}`)).toEqual(['TestCheckout passed']);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should report goroutines a package leaks as a failed package', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/worker', Name: 'worker', Dir: '/tmp/test-workspace/worker', TestGoFiles: ['worker_test.go'] },
+      ]);
+      const prepare = jest.spyOn(goroutineLeakCheck, 'prepare').mockResolvedValue('/tmp/test-workspace/reports/leakcheck/overlay.json');
+      const report = [
+        'alcs: goroutine leak check: 1 goroutines leaked\n',
+        'goroutine 21 [chan receive]:\n',
+        'example.com/worker.(*Pool).loop(0xc000120000)\n',
+        '\t/tmp/test-workspace/worker/pool.go:42 +0x5d\n',
+        '\n',
+        'alcs: end goroutine leak check\n',
+      ];
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'pass', Package: 'example.com/worker', Test: 'TestStart', Elapsed: 0.01 },
+        ...report.map(Output => ({ Action: 'output', Package: 'example.com/worker', Output })),
+        { Action: 'fail', Package: 'example.com/worker', Elapsed: 1.01 },
+      ]), 1));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, leak_check: ['**/worker'] });
+
+        expect(prepare).toHaveBeenCalledWith(expect.any(Array), ['**/worker'], '/tmp/test-workspace/reports/leakcheck');
+        expect(mockSpawn.mock.calls[0][1]).toContain('-overlay=/tmp/test-workspace/reports/leakcheck/overlay.json');
+        expect(result.success).toBe(false);
+        expect(result.goroutine_leaks).toEqual([expect.objectContaining({
+          package: 'example.com/worker',
+          goroutine: 21,
+          function: 'example.com/worker.(*Pool).loop',
+        })]);
+        expect(result.test_cases!.find(c => c.name === '[goroutine leak]')).toEqual(expect.objectContaining({
+          status: 'error',
+          failure_message: '1 goroutines still running after the tests finished',
+        }));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should retry under the leak check and keep a test failed when the retry leaks', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/worker', Name: 'worker', Dir: '/tmp/test-workspace/worker', TestGoFiles: ['worker_test.go'] },
+      ]);
+      jest.spyOn(goroutineLeakCheck, 'prepare').mockResolvedValue('/tmp/test-workspace/reports/leakcheck/overlay.json');
+      const report = [
+        'alcs: goroutine leak check: 1 goroutines leaked\n',
+        'goroutine 21 [chan receive]:\n',
+        'example.com/worker.(*Pool).loop(0xc000120000)\n',
+        '\t/tmp/test-workspace/worker/pool.go:42 +0x5d\n',
+        '\n',
+        'alcs: end goroutine leak check\n',
+      ];
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'fail', Package: 'example.com/worker', Test: 'TestStart', Elapsed: 0.01 },
+          { Action: 'fail', Package: 'example.com/worker', Elapsed: 0.02 },
+        ]), 1))
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'pass', Package: 'example.com/worker', Test: 'TestStart', Elapsed: 0.01 },
+          ...report.map(Output => ({ Action: 'output', Package: 'example.com/worker', Output })),
+          { Action: 'fail', Package: 'example.com/worker', Elapsed: 1.01 },
+        ]), 1));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          leak_check: ['**/worker'],
+          retries: 1,
+        });
+
+        expect(mockSpawn.mock.calls[1][1]).toEqual(expect.arrayContaining([
+          '-overlay=/tmp/test-workspace/reports/leakcheck/overlay.json', '-count=1', '-run', '^(TestStart)$',
+        ]));
+        const start = result.test_cases!.find(c => c.name === 'TestStart')!;
+        expect([start.status, start.flaky, start.attempts]).toEqual(['failed', undefined, 2]);
+        expect(result.success).toBe(false);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should shard a package by historical timings and record new timings', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+      ]);
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        callback(null, { stdout: 'TestMigrate\nTestQuery\nTestScan\nBenchmarkQuery\nok  \texample.com/store\t0.01s\n', stderr: '' });
+      });
+      (fs.readFile as jest.Mock).mockResolvedValueOnce(JSON.stringify({
+        'example.com/store': { TestMigrate: 9000, TestQuery: 4000, TestScan: 4000 },
+      }));
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => {
+        const tests = args[args.indexOf('-run') + 1].replace(/^\^\(|\)\$$/g, '').split('|');
+        return fakeGoProcess(jsonEvents([
+          ...tests.map(test => ({ Action: 'pass', Package: 'example.com/store', Test: test, Elapsed: 1 })),
+          { Action: 'pass', Package: 'example.com/store', Elapsed: 1 },
+        ]));
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          shards: 2,
+          timings_path: '/tmp/timings.json',
+        });
+
+        expect(mockSpawn.mock.calls.map(c => c[1][c[1].indexOf('-run') + 1])).toEqual([
+          '^(TestMigrate)$',
+          '^(TestQuery|TestScan)$',
+        ]);
+        expect(result.passed_tests).toBe(3);
+
+        const timings = (fs.writeFile as jest.Mock).mock.calls.find(([file]) => file === '/tmp/timings.json')![1];
+        expect(JSON.parse(timings)).toEqual({ 'example.com/store': { TestMigrate: 1000, TestQuery: 1000, TestScan: 1000 } });
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should shard only the tests the run pattern selects', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+      ]);
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        callback(null, { stdout: 'TestMigrate\nTestQuery\nTestScan\nok  \texample.com/store\t0.01s\n', stderr: '' });
+      });
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([{ Action: 'pass', Package: 'example.com/store', Elapsed: 1 }])));
+
+      try {
+        await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          shards: 2,
+          run: '^Test(Query|Scan)$/fast',
+        });
+
+        expect(mockSpawn.mock.calls.map(c => c[1][c[1].indexOf('-run') + 1]).sort()).toEqual([
+          '^(TestQuery)$/fast',
+          '^(TestScan)$/fast',
+        ]);
+      } finally {
+        mockExecFile.mockReset();
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should plan a sharded sandbox run without building or running anything', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+      ]);
+      jest.spyOn(testLabelScanner, 'scan').mockImplementation(async dir => dir.endsWith('store')
+        ? { TestMigrate: [], TestQuery: [], TestScan: [] }
+        : { TestAdd: [] });
+      const ensureImage = jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      const executeInSandbox = jest.spyOn(sandboxService, 'executeInSandbox');
+      (fs.readFile as jest.Mock).mockResolvedValueOnce(JSON.stringify({
+        'example.com/store': { TestMigrate: 9000, TestQuery: 4000, TestScan: 4000 },
+      }));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          dry_run: true,
+          sandbox: true,
+          parallel: 2,
+          shards: 2,
+          timings_path: '/tmp/timings.json',
+          plan_json_path: '/tmp/reports/plan.json',
+        });
+
+        expect(result.success).toBe(true);
+        expect(result.plan).toEqual({
+          since_ref: undefined,
+          packages: ['example.com/calc', 'example.com/store'],
+          cached: [],
+          ginkgo_suites: [],
+          processes: [
+            { packages: ['example.com/calc'], tests: undefined, estimated_ms: undefined },
+            { packages: ['example.com/store'], tests: ['TestMigrate'], estimated_ms: 9000 },
+            { packages: ['example.com/store'], tests: ['TestQuery', 'TestScan'], estimated_ms: 8000 },
+          ],
+          concurrency: 2,
+          images: ['golang:1.21-alpine'],
+          containers: 3,
+          estimated_duration_ms: 9000,
+          unestimated_processes: 1,
+        });
+        expect(mockSpawn).not.toHaveBeenCalled();
+        expect(ensureImage).not.toHaveBeenCalled();
+        expect(executeInSandbox).not.toHaveBeenCalled();
+
+        const written = (fs.writeFile as jest.Mock).mock.calls.find(([file]) => file === '/tmp/reports/plan.json')![1];
+        expect(JSON.parse(written)).toEqual(JSON.parse(JSON.stringify(result.plan)));
+        expect(logger.info).toHaveBeenCalledWith(expect.stringContaining('3 go test processes, up to 2 at once'));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should list the selected tests with labels and timings without running them', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+        { ImportPath: 'example.com/store', Dir: '/tmp/test-workspace/store' },
+      ]);
+      jest.spyOn(testLabelScanner, 'scan').mockImplementation(async dir => dir.endsWith('store')
+        ? { TestMigrate: ['integration'], TestQuery: [] }
+        : { TestAdd: ['fast'], TestDiv: [] });
+      const listOutput = [
+        'TestAdd', 'TestDiv', 'BenchmarkAdd', 'ExampleAdd', 'ok  \texample.com/calc\t0.002s',
+        '?   \texample.com/cmd\t[no test files]',
+        'TestMigrate', 'TestQuery', 'ok  \texample.com/store\t(cached)',
+        'FAIL\texample.com/broken [build failed]', 'FAIL', '',
+      ].join('\n');
+      mockExecFile.mockImplementation((cmd, args, opts, callback) =>
+        callback(Object.assign(new Error('exit status 1'), { stdout: listOutput, stderr: '' })));
+      (fs.readFile as jest.Mock).mockResolvedValueOnce(JSON.stringify({ 'example.com/calc': { TestAdd: 12 } }));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          list: true,
+          tags: ['e2e'],
+          exclude_labels: ['integration'],
+          timings_path: '/tmp/timings.json',
+          list_json_path: '/tmp/reports/tests.json',
+        });
+
+        expect(mockExecFile.mock.calls[0].slice(0, 2)).toEqual(['go', ['test', '-list', '.', '-tags=e2e', './...']]);
+        expect(mockSpawn).not.toHaveBeenCalled();
+        expect(result.inventory).toEqual({
+          schemaVersion: 1,
+          since_ref: undefined,
+          tags: ['e2e'],
+          packages: ['example.com/calc', 'example.com/store'],
+          unlisted_packages: ['example.com/broken'],
+          tests: [
+            { id: 'example.com/calc.ExampleAdd', package: 'example.com/calc', name: 'ExampleAdd', labels: [], duration_ms: undefined },
+            { id: 'example.com/calc.TestAdd', package: 'example.com/calc', name: 'TestAdd', labels: ['fast'], duration_ms: 12 },
+            { id: 'example.com/calc.TestDiv', package: 'example.com/calc', name: 'TestDiv', labels: [], duration_ms: undefined },
+            { id: 'example.com/store.TestQuery', package: 'example.com/store', name: 'TestQuery', labels: [], duration_ms: undefined },
+          ],
+        });
+        expect(result.success).toBe(false);
+        expect(result.failures.map(f => f.test_name)).toEqual(['example.com/broken [list failed]']);
+
+        const written = (fs.writeFile as jest.Mock).mock.calls.find(([file]) => file === '/tmp/reports/tests.json')![1];
+        expect(JSON.parse(written)).toEqual(JSON.parse(result.stdout));
+      } finally {
+        jest.restoreAllMocks();
+        mockExecFile.mockReset();
+      }
+    });
+
+    it('should rerun packages go replayed from its cache and drop their cached coverage', async () => {
+      mockSpawn
+        .mockReturnValueOnce(fakeGoProcess(jsonEvents([
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0 },
+          { Action: 'output', Package: 'example.com/calc', Output: 'ok  \texample.com/calc\t(cached)\tcoverage: 0.0% of statements\n' },
+          { Action: 'pass', Package: 'example.com/calc', Elapsed: 0 },
+          { Action: 'pass', Package: 'example.com/store', Test: 'TestQuery', Elapsed: 0.2 },
+          { Action: 'output', Package: 'example.com/store', Output: 'ok  \texample.com/store\t0.210s\tcoverage: 100.0% of statements\n' },
+          { Action: 'pass', Package: 'example.com/store', Elapsed: 0.21 },
+        ])))
+        .mockReturnValueOnce(fakeGoProcess(jsonEvents([
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+          { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.02 },
+        ])));
+      const profiles: Record<string, string> = {
+        '/tmp/test-workspace/reports/coverage.out': 'mode: set\nexample.com/calc/calc.go:1.1,2.2 1 0\nexample.com/store/db.go:1.1,2.2 1 1\n',
+        '/tmp/test-workspace/reports/coverage-fresh.out': 'mode: set\nexample.com/calc/calc.go:1.1,2.2 1 1\n',
+      };
+      (fs.readFile as jest.Mock).mockImplementation(async (file: string) => profiles[file]);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+        expect(mockSpawn).toHaveBeenCalledTimes(2);
+        const rerun = mockSpawn.mock.calls[1][1];
+        expect(rerun).toContain('-count=1');
+        expect(rerun).toContain('-coverprofile=/tmp/test-workspace/reports/coverage-fresh.out');
+        expect(rerun[rerun.length - 1]).toBe('example.com/calc');
+
+        expect(result.cache_bypassed).toEqual(['example.com/calc']);
+        expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.duration_ms}`)).toEqual([
+          'example.com/store TestQuery 200',
+          'example.com/calc TestAdd 10',
+        ]);
+        const written = (fs.writeFile as jest.Mock).mock.calls.filter(([file]) => file === '/tmp/test-workspace/reports/coverage.out');
+        expect(written[0][1]).toBe('mode: set\nexample.com/calc/calc.go:1.1,2.2 1 1\nexample.com/store/db.go:1.1,2.2 1 1\n');
+      } finally {
+        (fs.readFile as jest.Mock).mockReset();
+      }
+    });
+
+    it('should pass count through to go test instead of rerunning cached packages', async () => {
+      mockSpawn.mockReturnValueOnce(fakeGoProcess(passingRun));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, count: 3 });
+
+      expect(mockSpawn).toHaveBeenCalledTimes(1);
+      expect(mockSpawn.mock.calls[0][1]).toContain('-count=3');
+      expect(result.cache_bypassed).toBeUndefined();
+    });
+
+    it('should keep a test failed when a later -count repetition passes', async () => {
+      mockSpawn.mockReturnValueOnce(fakeGoProcess(jsonEvents([
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestTiming' },
+        { Action: 'output', Package: 'example.com/calc', Test: 'TestTiming', Output: '    calc_test.go:12: Error: too slow\n' },
+        { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestTiming' },
+        { Action: 'pass', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestTiming' },
+        { Action: 'pass', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+        { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.3 },
+      ]), 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, count: 3 });
+
+      expect(result.test_cases!.map(c => `${c.name} ${c.status}`)).toEqual(['TestTiming failed']);
+      expect(result.test_cases![0].output).toContain('Error: too slow');
+      expect(result.failures.map(f => f.test_name)).toEqual(['TestTiming']);
+      expect(result.success).toBe(false);
+    });
+
+    it('should kill in-flight tests and mark unfinished work cancelled', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+      ]);
+      const controller = new AbortController();
+      const child: any = new EventEmitter();
+      child.stdout = new EventEmitter();
+      child.stderr = new EventEmitter();
+      child.pid = 4242;
+      child.kill = jest.fn();
+
+      mockSpawn.mockImplementation(() => {
+        setImmediate(() => {
+          child.stdout.emit('data', Buffer.from(jsonEvents([
+            { Action: 'run', Package: 'example.com/a', Test: 'TestOne' },
+            { Action: 'pass', Package: 'example.com/a', Test: 'TestOne', Elapsed: 0.01 },
+            { Action: 'run', Package: 'example.com/a', Test: 'TestTwo' },
+          ])));
+          controller.abort();
+        });
+        return child;
+      });
+
+      const killSpy = jest.spyOn(process, 'kill').mockImplementation(((pid: number, signal: string) => {
+        if (signal === 'SIGKILL') {
+          setImmediate(() => child.emit('close', null));
+        }
+        return true;
+      }) as any);
+
+      try {
+        const result = await runner.execute(
+          workspacePath, codeFilePath, testFilePath, { ...options, parallel: 1 }, controller.signal
+        );
+
+        expect(killSpy).toHaveBeenCalledWith(-4242, 'SIGKILL');
+        expect(mockSpawn).toHaveBeenCalledTimes(1);
+        expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.status}`)).toEqual([
+          'example.com/a TestOne passed',
+          'example.com/a TestTwo cancelled',
+          'example.com/b [cancelled] cancelled',
+        ]);
+        expect(result.cancelled).toBe(true);
+        expect(result.success).toBe(false);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should stop on the first failure and tear down in-flight containers', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+        { ImportPath: 'example.com/c', Dir: '/tmp/test-workspace/c' },
+      ]);
+      jest.spyOn(sandboxService, 'ensureImage').mockResolvedValue();
+      const hanging = new Map<string, any>();
+
+      const spawnInSandbox = jest.spyOn(sandboxService, 'spawnInSandbox').mockImplementation(async (config, command) => {
+        const pkg = command[command.length - 1];
+        const containerId = `alcs-${pkg.split('/').pop()}`;
+        if (pkg === 'example.com/a') {
+          return {
+            containerId,
+            child: fakeGoProcess(jsonEvents([
+              { Action: 'run', Package: pkg, Test: 'TestBroken' },
+              { Action: 'fail', Package: pkg, Test: 'TestBroken', Elapsed: 0.01 },
+              { Action: 'fail', Package: pkg, Elapsed: 0.02 },
+            ]), 1),
+          };
+        }
+
+        const child: any = new EventEmitter();
+        child.stdout = new EventEmitter();
+        child.stderr = new EventEmitter();
+        child.kill = jest.fn();
+        setImmediate(() => child.stdout.emit('data', Buffer.from(jsonEvents([
+          { Action: 'run', Package: pkg, Test: 'TestSlow' },
+        ]))));
+        hanging.set(containerId, child);
+        return { child, containerId };
+      });
+      const signalContainer = jest.spyOn(sandboxService, 'signalContainer').mockImplementation(async id => {
+        const child = hanging.get(id);
+        if (child) {
+          setImmediate(() => child.emit('close', 137));
+        }
+      });
+      const finishContainer = jest.spyOn(sandboxService, 'finishContainer').mockResolvedValue({ oomKilled: false });
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: '', stderr: '' }));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          sandbox: true,
+          parallel: 2,
+          fail_fast: true,
+          summary_json_path: '/tmp/reports/summary.json',
+        });
+
+        // c was still queued when a failed, so it never got a container
+        expect(spawnInSandbox).toHaveBeenCalledTimes(2);
+        expect(signalContainer).toHaveBeenCalledWith('alcs-b', 'SIGKILL');
+        expect(finishContainer.mock.calls.map(c => c[0]).sort()).toEqual(['alcs-a', 'alcs-b']);
+        expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.status}`)).toEqual([
+          'example.com/a TestBroken failed',
+          'example.com/b TestSlow cancelled',
+          'example.com/c [cancelled] cancelled',
+        ]);
+        expect(result.success).toBe(false);
+        expect(result.aborted_early).toBe(true);
+        expect(result.cancelled).toBeUndefined();
+
+        const written = (fs.writeFile as jest.Mock).mock.calls.find(([file]) => file === '/tmp/reports/summary.json');
+        const summary = JSON.parse(written[1]);
+        expect(summary.aborted_early).toBe(true);
+        expect(summary.exit_status).toBe(1);
+        expect(summary.totals).toEqual(expect.objectContaining({ failed: 1, cancelled: 2 }));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should run prebuilt test binaries and parse their verbose output', async () => {
+      (fs.readdir as jest.Mock).mockResolvedValueOnce([{ name: 'calc.test', isDirectory: () => false }]);
+      mockSpawn.mockImplementation(() => fakeGoProcess([
+        '=== RUN   TestAdd',
+        '--- PASS: TestAdd (0.00s)',
+        '=== RUN   TestDiv',
+        '    calc_test.go:20: division by zero',
+        '--- FAIL: TestDiv (0.01s)',
+        'FAIL',
+        '',
+      ].join('\n'), 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        test_binaries_dir: '/bins',
+        race: true,
+        cache_dir: '/tmp/cache',
+      });
+
+      expect(mockSpawn).toHaveBeenCalledTimes(1);
+      const [program, args, spawnOptions] = mockSpawn.mock.calls[0];
+      expect(program).toBe('/bins/calc.test');
+      expect(args).toEqual(['-test.v=true', '-test.coverprofile=/tmp/test-workspace/reports/coverage-0.out']);
+      expect(spawnOptions.cwd).toBe('/bins');
+      expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.status}`)).toEqual([
+        'calc TestAdd passed',
+        'calc TestDiv failed',
+      ]);
+      expect(result.failures[0].stack_trace).toContain('division by zero');
+      expect(result.success).toBe(false);
+    });
+
+    it('should derive a stable shuffle seed per package from the base seed', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+      ]);
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => {
+        const pkg = args[args.length - 1];
+        const seed = args.find(a => a.startsWith('-shuffle='))!.slice('-shuffle='.length);
+        return fakeGoProcess(jsonEvents([
+          { Action: 'output', Package: pkg, Output: `-test.shuffle ${seed}\n` },
+          { Action: 'run', Package: pkg, Test: 'TestOne' },
+          { Action: 'fail', Package: pkg, Test: 'TestOne', Elapsed: 0.01 },
+          { Action: 'fail', Package: pkg, Elapsed: 0.01 },
+        ]), 1);
+      });
+      const seedsOf = () => mockSpawn.mock.calls.map(c => c[1].find((a: string) => a.startsWith('-shuffle=')));
+
+      try {
+        const first = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, parallel: 2, shuffle: 42 });
+        const firstSeeds = seedsOf();
+        mockSpawn.mockClear();
+        const second = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, parallel: 2, shuffle: 42 });
+
+        expect(new Set(firstSeeds).size).toBe(2);
+        expect(seedsOf()).toEqual(firstSeeds);
+        expect(first.shuffle!.base_seed).toBe(42);
+        expect(Object.keys(first.shuffle!.seeds).sort()).toEqual(['example.com/a', 'example.com/b']);
+        expect(`-shuffle=${first.shuffle!.seeds['example.com/a']}`).toBe(firstSeeds[0]);
+        expect(second.shuffle).toEqual(first.shuffle);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should pick and record a base seed for shuffle on', async () => {
+      jest.spyOn(Date, 'now').mockReturnValue(1700000000000);
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'output', Package: 'example.com/calc', Output: '-test.shuffle 1700000000000\n' },
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+        { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+        { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.01 },
+      ])));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          shuffle: 'on',
+          cache_dir: '/tmp/cache',
+        });
+
+        expect(mockSpawn.mock.calls[0][1]).toContain('-shuffle=1700000000000');
+        expect(result.shuffle).toEqual({ base_seed: 1700000000000, seeds: { 'example.com/calc': 1700000000000 } });
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should merge lint findings and fail on the severity threshold', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      const findings = [
+        { linter: 'errcheck', file: 'calc.go', line: 3, col: 2, message: 'unchecked error', severity: 'error' as const },
+        { linter: 'godot', file: 'calc.go', line: 1, col: 1, message: 'missing period', severity: 'info' as const },
+      ];
+      const run = jest.spyOn(lintService, 'run').mockResolvedValue(findings);
+
+      try {
+        const lenient = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          lint: true,
+          lint_config_path: '/tmp/test-workspace/.golangci.yml',
+        });
+        expect(run).toHaveBeenCalledWith(workspacePath, expect.objectContaining({
+          config_path: '/tmp/test-workspace/.golangci.yml',
+        }));
+        expect(lenient.success).toBe(true);
+        expect(lenient.lint_findings).toEqual(findings);
+
+        const strict = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          lint: true,
+          lint_fail_on_severity: 'warning',
+        });
+        expect(strict.success).toBe(false);
+        expect(strict.passed_tests).toBe(1);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should keep test results when golangci-lint crashes', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      jest.spyOn(lintService, 'run').mockRejectedValue(new Error('golangci-lint failed (exit 3): timeout'));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, lint: true });
+
+        expect(result.success).toBe(false);
+        expect(result.passed_tests).toBe(1);
+        expect(result.failures).toEqual([
+          expect.objectContaining({ test_name: 'golangci_lint_execution', error_message: expect.stringContaining('exit 3') }),
+        ]);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should exclude generated and vendored files from the coverage profile', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      (fs.readFile as jest.Mock).mockResolvedValueOnce(`mode: set
+example.com/calc/calc.go:3.1,5.2 2 1
+example.com/calc/calc.pb.go:1.1,9.2 40 0
+`);
+
+      await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        coverage_exclude: ['*.pb.go', 'vendor/'],
+      });
+
+      expect(fs.writeFile).toHaveBeenCalledWith(
+        '/tmp/test-workspace/reports/coverage.out',
+        'mode: set\nexample.com/calc/calc.go:3.1,5.2 2 1\n',
+        'utf-8'
+      );
+      expect(coverageParser.parseGoCoverageProfile).toHaveBeenCalledWith('/tmp/test-workspace/reports/coverage.out');
+    });
+
+    it('should fail the run when changed lines are under the required coverage', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      (fs.readFile as jest.Mock).mockResolvedValueOnce(`mode: set
+example.com/calc/calc.go:4.24,6.2 1 1
+example.com/calc/calc.go:8.24,9.12 1 1
+example.com/calc/calc.go:9.12,11.3 1 0
+`);
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        const stdout = args[0] === 'diff' ? [
+          '+++ b/calc.go',
+          '@@ -8,0 +8,4 @@',
+          '+func Div(a, b int) int {',
+          '+\tif b == 0 {',
+          '+\t\treturn 0',
+          '+\t}',
+          '',
+        ].join('\n') : '';
+        callback(null, { stdout, stderr: '' });
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          changed_coverage_base: 'origin/main',
+          changed_coverage_minimum: 80,
+        });
+
+        expect(mockExecFile).toHaveBeenCalledWith(
+          'git',
+          ['diff', '--unified=0', '--relative', '--no-color', '--no-ext-diff', 'origin/main...HEAD'],
+          expect.objectContaining({ cwd: workspacePath }),
+          expect.any(Function)
+        );
+        expect(result.changed_coverage).toEqual(expect.objectContaining({
+          covered_lines: 1,
+          executable_lines: 2,
+          percentage: 50,
+          required: 80,
+        }));
+        expect(result.success).toBe(false);
+        expect(result.failures).toContainEqual(expect.objectContaining({
+          test_name: 'changed_line_coverage',
+          location: 'example.com/calc/calc.go:10',
+        }));
+      } finally {
+        mockExecFile.mockReset();
+      }
+    });
+
+    it('should write the JSON summary, JUnit and TAP reports from the same run summary', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1));
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => {
+        const stdout = cmd === 'git' ? 'abc123def456\n' : 'go1.23.4\n';
+        callback(null, { stdout, stderr: '' });
+      });
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        summary_json_path: '/tmp/reports/summary.json',
+        junit_output_path: '/tmp/reports/junit.xml',
+        tap_output_path: '/tmp/reports/results.tap',
+      });
+
+      const writes = new Map((fs.writeFile as jest.Mock).mock.calls.map(([file, content]) => [file, content]));
+      const summary = JSON.parse(writes.get('/tmp/reports/summary.json'));
+      expect(result.success).toBe(false);
+      expect(summary.schemaVersion).toBe(1);
+      expect(summary.metadata).toEqual(expect.objectContaining({ go_version: 'go1.23.4', git_sha: 'abc123def456' }));
+      expect(summary.totals).toEqual({ passed: 1, failed: 1, skipped: 0, cancelled: 0, not_run: 0, panicked: 0, total: 2 });
+      expect(summary.durations.slowest.map((t: any) => t.name)).toEqual(['TestTiming', 'TestAdd']);
+      expect(summary.coverage.percentage).toBe(80);
+      expect(summary.exit_status).toBe(1);
+      expect(writes.get('/tmp/reports/junit.xml')).toContain(`timestamp="${summary.metadata.started_at}"`);
+      expect(writes.get('/tmp/reports/results.tap')).toMatch(/^TAP version 13\n1\.\.2\n/);
+    });
+
+    it('should not fail the run when a report cannot be written', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: 'go1.23.4\n', stderr: '' }));
+      (fs.writeFile as jest.Mock).mockImplementation(async (file: string) => {
+        if (file !== '/tmp/reports/results.tap') {
+          throw new Error(`EACCES: permission denied, open '${file}'`);
+        }
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          summary_json_path: '/tmp/reports/summary.json',
+          junit_output_path: '/tmp/reports/junit.xml',
+          tap_output_path: '/tmp/reports/results.tap',
+        });
+
+        expect(result.success).toBe(true);
+        expect(logger.warn).toHaveBeenCalledWith(expect.stringContaining('Failed to write JSON summary report: EACCES'));
+        expect(logger.warn).toHaveBeenCalledWith(expect.stringContaining('Failed to write JUnit report: EACCES'));
+        expect(fs.writeFile).toHaveBeenCalledWith('/tmp/reports/results.tap', expect.stringContaining('TAP version 13'), 'utf-8');
+      } finally {
+        (fs.writeFile as jest.Mock).mockReset();
+        mockExecFile.mockReset();
+      }
+    });
+
+    it('should report the slowest tests when slowest is set', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, slowest: 1 });
+
+      expect(result.durations).toEqual({
+        total_ms: 510,
+        slowest: [{ package: 'example.com/calc', name: 'TestTiming', status: 'failed', duration_ms: 500 }],
+        packages: [{ package: 'example.com/calc', duration_ms: 510, tests: 2, percentage: 100 }],
+      });
+      expect(logger.info).toHaveBeenCalledWith(expect.stringContaining('Slowest 1 tests:'));
+    });
+
+    it('should keep the raw go test output of a failed package as a log', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1, 'go: downloading example.com/dep v1.0.0\n'));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+
+      const logPath = '/tmp/test-workspace/reports/artifacts/logs/example.com/calc.log';
+      const written = (fs.writeFile as jest.Mock).mock.calls.find(([file]) => file === logPath)![1];
+      expect(written).toContain('go: downloading example.com/dep v1.0.0');
+      expect(written).toContain('calc_test.go:30: Error: too slow');
+      expect(result.package_logs).toEqual({ 'example.com/calc': logPath });
+      expect(result.test_cases!.every(c => c.log_path === logPath)).toBe(true);
+    });
+
+    it('should keep no logs when keep_logs is none', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, keep_logs: 'none' });
+
+      expect((fs.writeFile as jest.Mock).mock.calls.some(([file]) => String(file).includes('/logs/'))).toBe(false);
+      expect(result.package_logs).toBeUndefined();
+    });
+
+    it('should skip logs of passing packages unless keep_logs is all', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+
+      const failedOnly = await runner.execute(workspacePath, codeFilePath, testFilePath, options);
+      const all = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, keep_logs: 'all' });
+
+      expect(failedOnly.package_logs).toBeUndefined();
+      expect(all.package_logs).toEqual({ 'example.com/calc': '/tmp/test-workspace/reports/artifacts/logs/example.com/calc.log' });
+    });
+
+    it('should diff the run against a baseline summary', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1));
+      const baseline = {
+        schemaVersion: 1,
+        tests: [
+          { package: 'example.com/calc', name: 'TestAdd', status: 'passed', duration_ms: 1, output: '' },
+          { package: 'example.com/calc', name: 'TestTiming', status: 'passed', duration_ms: 1, output: '' },
+          { package: 'example.com/calc', name: 'TestOld', status: 'failed', duration_ms: 1, output: '' },
+        ],
+      };
+      (fs.readFile as jest.Mock).mockImplementation(async (file: string) => {
+        if (file === '/tmp/main/summary.json') {
+          return JSON.stringify(baseline);
+        }
+        throw Object.assign(new Error('ENOENT'), { code: 'ENOENT' });
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          baseline_summary_path: '/tmp/main/summary.json',
+        });
+
+        expect(result.test_diff!.newly_failing).toEqual([{
+          package: 'example.com/calc',
+          name: 'TestTiming',
+          baseline_status: 'passed',
+          current_status: 'failed',
+        }]);
+        expect(result.test_diff!.removed.map(t => t.name)).toEqual(['TestOld']);
+        expect(result.test_diff!.still_failing).toEqual([]);
+      } finally {
+        (fs.readFile as jest.Mock).mockReset();
+      }
+    });
+
+    it('should diff against the latest stored run and store this one', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1));
+      const baseline = {
+        schemaVersion: 1,
+        tests: [{ package: 'example.com/calc', name: 'TestTiming', status: 'passed', duration_ms: 1, output: '' }],
+      } as RunSummary;
+      jest.spyOn(FileResultStore.prototype, 'list').mockResolvedValue(['run-1', 'run-2']);
+      const get = jest.spyOn(FileResultStore.prototype, 'get').mockResolvedValue(baseline);
+      const put = jest.spyOn(FileResultStore.prototype, 'put').mockResolvedValue(undefined);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          result_store: '/mnt/alcs-results',
+          baseline_run_id: 'latest',
+        });
+
+        expect(get).toHaveBeenCalledWith('run-2');
+        expect(result.test_diff!.newly_failing.map(t => t.name)).toEqual(['TestTiming']);
+        const [runId, stored] = put.mock.calls[0];
+        expect(runId).toBe(stored.metadata.run_id);
+        expect(result.stored_run_id).toBe(runId);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should not fail the run when the result store is unreachable', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      jest.spyOn(FileResultStore.prototype, 'put').mockRejectedValue(new Error('EACCES: permission denied'));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, result_store: '/mnt/alcs-results' });
+
+        expect(result.success).toBe(true);
+        expect(result.stored_run_id).toBeUndefined();
+        expect(logger.warn).toHaveBeenCalledWith(expect.stringContaining('EACCES: permission denied'));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should checkpoint the run in the result store after every package', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+      ]);
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => {
+        const pkg = args[args.length - 1];
+        const verdict = pkg === 'example.com/b' ? 'fail' : 'pass';
+        return fakeGoProcess(jsonEvents([
+          { Action: verdict, Package: pkg, Test: 'TestOne', Elapsed: 0.01 },
+          { Action: verdict, Package: pkg, Elapsed: 0.01 },
+        ]), verdict === 'fail' ? 1 : 0);
+      });
+      const stored: [string, RunSummary][] = [];
+      jest.spyOn(FileResultStore.prototype, 'put').mockImplementation(async (runId, summary) => {
+        stored.push([runId, JSON.parse(JSON.stringify(summary))]);
+      });
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          parallel: 1,
+          result_store: '/mnt/alcs-results',
+          checkpoint: true,
+        });
+
+        expect(stored.map(([runId]) => runId)).toEqual(Array(4).fill(result.checkpoint_run_id));
+        expect(stored.map(([, summary]) => summary.checkpoint!.runs!.map(r => r.status))).toEqual([
+          ['pending', 'pending'],
+          ['passed', 'pending'],
+          ['passed', 'failed'],
+          ['passed', 'failed'],
+        ]);
+        expect(stored[1][1].tests.map(t => `${t.package} ${t.name}`)).toEqual(['example.com/a TestOne']);
+        expect(stored[3][1].exit_status).toBe(1);
+        expect(result.stored_run_id).toBe(result.checkpoint_run_id);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should not diff a checkpointed run against its own checkpoint', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+      ]);
+      mockSpawn.mockImplementation(() => fakeGoProcess(jsonEvents([
+        { Action: 'fail', Package: 'example.com/b', Test: 'TestOne', Elapsed: 0.01 },
+        { Action: 'fail', Package: 'example.com/b', Elapsed: 0.01 },
+      ]), 1));
+      const baseline = {
+        schemaVersion: 1,
+        tests: [{ package: 'example.com/b', name: 'TestOne', status: 'passed', duration_ms: 1, output: '' }],
+      } as RunSummary;
+      const stored = new Map<string, RunSummary>();
+      jest.spyOn(FileResultStore.prototype, 'put').mockImplementation(async (runId, summary) => {
+        stored.set(runId, JSON.parse(JSON.stringify(summary)));
+      });
+      jest.spyOn(FileResultStore.prototype, 'list').mockImplementation(async () => ['run-1', ...stored.keys()]);
+      const get = jest.spyOn(FileResultStore.prototype, 'get').mockImplementation(async runId => stored.get(runId) || baseline);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          result_store: '/mnt/alcs-results',
+          checkpoint: true,
+          baseline_run_id: 'latest',
+        });
+
+        expect(get).not.toHaveBeenCalledWith(result.checkpoint_run_id);
+        expect(get).toHaveBeenCalledWith('run-1');
+        expect(result.test_diff!.newly_failing.map(t => t.name)).toEqual(['TestOne']);
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should resume a checkpointed run, rerunning only the packages that did not finish', async () => {
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/a', Dir: '/tmp/test-workspace/a' },
+        { ImportPath: 'example.com/b', Dir: '/tmp/test-workspace/b' },
+      ]);
+      mockSpawn.mockImplementation((cmd: string, args: string[]) => fakeGoProcess(jsonEvents([
+        { Action: 'pass', Package: args[args.length - 1], Test: 'TestOne', Elapsed: 0.01 },
+        { Action: 'pass', Package: args[args.length - 1], Elapsed: 0.01 },
+      ])));
+      const checkpoint = {
+        schemaVersion: 1,
+        tests: [{ package: 'example.com/a', name: 'TestOne', status: 'failed', duration_ms: 10, output: 'a_test.go:5: Error: wrong\n' }],
+        build_failures: [],
+        coverage: { percentage: 0 },
+        checkpoint: {
+          selection: { packages: ['./...'] },
+          runs: [{ package: 'example.com/a', status: 'failed' }, { package: 'example.com/b', status: 'pending' }],
+        },
+      } as unknown as RunSummary;
+      jest.spyOn(FileResultStore.prototype, 'get').mockResolvedValue(checkpoint);
+      const put = jest.spyOn(FileResultStore.prototype, 'put').mockResolvedValue(undefined);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          result_store: '/mnt/alcs-results',
+          resume_run_id: 'run-1',
+        });
+
+        expect(mockSpawn).toHaveBeenCalledTimes(1);
+        expect(mockSpawn.mock.calls[0][1]).toContain('example.com/b');
+        expect(result.test_cases!.map(c => `${c.package} ${c.name} ${c.status}${c.resumed ? ' resumed' : ''}`)).toEqual([
+          'example.com/b TestOne passed',
+          'example.com/a TestOne failed resumed',
+        ]);
+        expect(result.success).toBe(false);
+        expect(result.failures.map(f => f.test_name)).toEqual(['TestOne']);
+        const [runId, stored] = put.mock.calls[put.mock.calls.length - 1];
+        expect(runId).toBe('run-1');
+        expect(stored.checkpoint!.runs!.map(r => r.status)).toEqual(['failed', 'passed']);
+        expect(result.checkpoint_run_id).toBe('run-1');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should refuse to resume a checkpoint with options selecting other tests', async () => {
+      jest.spyOn(FileResultStore.prototype, 'get').mockResolvedValue({
+        schemaVersion: 1,
+        tests: [],
+        checkpoint: { selection: { packages: ['./...'], tags: ['integration'] } },
+      } as unknown as RunSummary);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          result_store: '/mnt/alcs-results',
+          resume_run_id: 'run-1',
+        });
+
+        expect(mockSpawn).not.toHaveBeenCalled();
+        expect(result.success).toBe(false);
+        expect(result.failures[0].error_message).toBe('Cannot resume run run-1: tags differs from its checkpoint');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should post the summary to the webhook once the run finishes', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(failingRun, 1));
+      const notify = jest.spyOn(webhookService, 'notify').mockResolvedValue(false);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          webhook_url: 'https://hooks.example.com/ci',
+          webhook_template: '{"text": "{{status}}"}',
+        });
+
+        expect(notify).toHaveBeenCalledWith(
+          expect.objectContaining({ totals: expect.objectContaining({ failed: 1 }), exit_status: 1 }),
+          { url: 'https://hooks.example.com/ci', template: '{"text": "{{status}}"}' }
+        );
+        // An undeliverable webhook does not change the outcome
+        expect(result.failures.map(f => f.test_name)).toContain('TestTiming');
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should run benchmarks and fail on regressions against the baseline', async () => {
+      const stdout = jsonEvents([
+        { Action: 'start', Package: 'example.com/parse' },
+        { Action: 'output', Package: 'example.com/parse', Output: 'goos: linux\n' },
+        { Action: 'run', Package: 'example.com/parse', Test: 'BenchmarkParse' },
+        { Action: 'output', Package: 'example.com/parse', Test: 'BenchmarkParse', Output: 'BenchmarkParse-8   \t' },
+        { Action: 'output', Package: 'example.com/parse', Test: 'BenchmarkParse', Output: '  1000\t   1300 ns/op\t  64 B/op\t  2 allocs/op\n' },
+        { Action: 'output', Package: 'example.com/parse', Output: 'BenchmarkNew-8 \t 50\t 99 ns/op\t 0 B/op\t 0 allocs/op\n' },
+        { Action: 'pass', Package: 'example.com/parse', Elapsed: 2.1 },
+      ]);
+      mockSpawn.mockImplementation(() => fakeGoProcess(stdout));
+      (fs.readFile as jest.Mock).mockResolvedValueOnce(JSON.stringify([
+        { package: 'example.com/parse', name: 'BenchmarkParse', procs: 8, iterations: 1000, ns_per_op: 1000, bytes_per_op: 64, allocs_per_op: 2 },
+      ]));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        bench: 'Parse',
+        bench_baseline_path: '/tmp/bench-baseline.json',
+        bench_threshold_percent: 20,
+      });
+
+      const args = mockSpawn.mock.calls[0][1];
+      expect(args).toEqual(expect.arrayContaining(['-run=^$', '-bench=Parse', '-benchmem']));
+      expect(args.some((a: string) => a.startsWith('-coverprofile'))).toBe(false);
+      expect(result.benchmarks).toEqual([
+        expect.objectContaining({ name: 'BenchmarkParse', ns_per_op: 1300, bytes_per_op: 64, allocs_per_op: 2 }),
+        expect.objectContaining({ name: 'BenchmarkNew', ns_per_op: 99 }),
+      ]);
+      expect(result.benchmark_comparison?.added).toEqual(['example.com/parse BenchmarkNew']);
+      expect(result.success).toBe(false);
+      expect(result.failures).toEqual([
+        expect.objectContaining({ test_name: 'BenchmarkParse', error_message: expect.stringContaining('ns_per_op regressed +30.0%') }),
+      ]);
+    });
+
+    it('should route detected Ginkgo suites to the Ginkgo runner', async () => {
+      mockSpawn.mockImplementation(() => fakeGoProcess(passingRun));
+      jest.spyOn(goPackageSelector, 'listPackages').mockResolvedValue([
+        { ImportPath: 'example.com/calc', Dir: '/tmp/test-workspace/calc' },
+        { ImportPath: 'example.com/calc/api', Dir: '/tmp/test-workspace/api' },
+      ]);
+      const resolve = jest.spyOn(goFrameworkDetector, 'resolve').mockImplementation(async importPath =>
+        importPath.endsWith('/api') ? GoFramework.Ginkgo : GoFramework.Standard
+      );
+      const runSuites = jest.spyOn(GinkgoRunner.prototype, 'runSuites').mockResolvedValue({
+        exitCode: 1,
+        stdout: '',
+        stderr: '',
+        testCases: [{
+          package: 'API',
+          name: 'API creates users',
+          status: 'failed',
+          duration_ms: 4,
+          output: '',
+          failure_message: 'Expected 201',
+        }],
+        failures: [{ test_name: 'API creates users', error_message: 'Expected 201', stack_trace: '', location: 'unknown' }],
+        coverageProfilePath: '/tmp/test-workspace/reports/ginkgo/coverage.out',
+      });
+
+      try {
+        const overrides = { 'example.com/calc/legacy': GoFramework.Standard };
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          detect_framework: true,
+          framework_overrides: overrides,
+        });
+
+        expect(resolve).toHaveBeenCalledWith('example.com/calc', '/tmp/test-workspace/calc', overrides);
+        expect(mockSpawn.mock.calls[0][1]).toContain('example.com/calc');
+        expect(mockSpawn.mock.calls[0][1]).not.toContain('example.com/calc/api');
+        expect(runSuites.mock.calls[0][1]).toEqual(['./api']);
+        expect(result.success).toBe(false);
+        expect(result.passed_tests).toBe(1);
+        expect(result.failed_tests).toBe(1);
+        expect(result.test_cases).toEqual(expect.arrayContaining([
+          expect.objectContaining({ name: 'TestAdd', status: 'passed' }),
+          expect.objectContaining({ name: 'API creates users', status: 'failed' }),
+        ]));
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should mark a test flaky when it passes on retry', async () => {
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(failingRun, 1))
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.3 },
+        ]), 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        retries: 3,
+      });
+
+      const timing = result.test_cases!.find(c => c.name === 'TestTiming')!;
+      expect(timing.status).toBe('passed');
+      expect(timing.flaky).toBe(true);
+      expect(timing.attempts).toBe(4);
+      expect(result.failed_tests).toBe(0);
+      expect(result.failures).toHaveLength(0);
+      expect(result.success).toBe(false); // fail_on_flaky defaults to true
+
+      // Every retry of the package runs in one invocation, so it is built once
+      expect(mockSpawn).toHaveBeenCalledTimes(2);
+      const retryArgs = mockSpawn.mock.calls[1][1];
+      expect(retryArgs).toEqual(expect.arrayContaining(['-count=3', '-run', '^(TestTiming)$', 'example.com/calc']));
+    });
+
+    it('should treat flaky tests as success when fail_on_flaky is false', async () => {
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(failingRun, 1))
+        .mockImplementationOnce(() => fakeGoProcess(timingPass));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        retries: 2,
+        fail_on_flaky: false,
+      });
+
+      expect(result.success).toBe(true);
+    });
+
+    it('should keep a test failed when every retry fails', async () => {
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(failingRun, 1))
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.2 },
+        ]), 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+        ...options,
+        retries: 2,
+      });
+
+      const timing = result.test_cases!.find(c => c.name === 'TestTiming')!;
+      expect(timing.status).toBe('failed');
+      expect(timing.flaky).toBeUndefined();
+      expect(timing.attempts).toBe(3);
+      expect(result.failed_tests).toBe(1);
+      expect(mockSpawn).toHaveBeenCalledTimes(2);
+    });
+
+    it('should retry every failing test of a package in one invocation', async () => {
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.2 },
+        ]), 1))
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Test: 'TestTiming', Elapsed: 0.1 },
+          { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.4 },
+        ]), 1));
+
+      const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, retries: 2 });
+
+      expect(mockSpawn).toHaveBeenCalledTimes(2);
+      expect(mockSpawn.mock.calls[1][1]).toEqual(expect.arrayContaining(['-count=2', '-run', '^(TestAdd|TestTiming)$']));
+      const add = result.test_cases!.find(c => c.name === 'TestAdd')!;
+      const timing = result.test_cases!.find(c => c.name === 'TestTiming')!;
+      expect([add.status, add.flaky, add.attempts]).toEqual(['passed', true, 3]);
+      expect([timing.status, timing.flaky, timing.attempts]).toEqual(['failed', undefined, 3]);
+      expect(result.failed_tests).toBe(1);
+    });
+
+    it('should stop retrying once the run is cancelled', async () => {
+      const controller = new AbortController();
+      const retry: any = new EventEmitter();
+      retry.stdout = new EventEmitter();
+      retry.stderr = new EventEmitter();
+      retry.pid = 4343;
+      retry.kill = jest.fn();
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(failingRun, 1))
+        .mockImplementationOnce(() => {
+          setImmediate(() => controller.abort());
+          return retry;
+        });
+      const killSpy = jest.spyOn(process, 'kill').mockImplementation(((pid: number, signal: string) => {
+        if (signal === 'SIGKILL') {
+          setImmediate(() => retry.emit('close', null));
+        }
+        return true;
+      }) as any);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, { ...options, retries: 3 }, controller.signal);
+
+        expect(killSpy).toHaveBeenCalledWith(-4343, 'SIGKILL');
+        expect(mockSpawn).toHaveBeenCalledTimes(2);
+        const timing = result.test_cases!.find(c => c.name === 'TestTiming')!;
+        expect(timing.status).toBe('failed');
+        expect(timing.attempts).toBeUndefined();
+      } finally {
+        jest.restoreAllMocks();
+      }
+    });
+
+    it('should retry under -race and keep a racy test failed when a retry passes', async () => {
+      const mockExecFile = child_process.execFile as unknown as jest.Mock;
+      mockExecFile.mockImplementation((cmd, args, opts, callback) => callback(null, { stdout: '1\n', stderr: '' }));
+      const racyRun = jsonEvents([
+        { Action: 'run', Package: 'example.com/calc', Test: 'TestCounter' },
+        ...[
+          '==================\n',
+          'WARNING: DATA RACE\n',
+          'Write at 0x00c0000a4010 by goroutine 8:\n',
+          '  example.com/calc.(*Counter).Inc()\n',
+          '\n',
+          'Previous read at 0x00c0000a4010 by goroutine 7:\n',
+          '  example.com/calc.(*Counter).Value()\n',
+          '==================\n',
+        ].map(Output => ({ Action: 'output', Package: 'example.com/calc', Test: 'TestCounter', Output })),
+        { Action: 'fail', Package: 'example.com/calc', Test: 'TestCounter', Elapsed: 0.01 },
+        { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.02 },
+      ]);
+      mockSpawn
+        .mockImplementationOnce(() => fakeGoProcess(racyRun, 1))
+        .mockImplementationOnce(() => fakeGoProcess(jsonEvents([
+          { Action: 'pass', Package: 'example.com/calc', Test: 'TestCounter', Elapsed: 0.01 },
+          { Action: 'pass', Package: 'example.com/calc', Elapsed: 0.01 },
+        ])));
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          race: true,
+          retries: 1,
+          fail_on_flaky: false,
+        });
+
+        expect(mockSpawn.mock.calls[1][1]).toEqual(expect.arrayContaining(['-race', '-count=1', '-run', '^(TestCounter)$']));
+        const counter = result.test_cases!.find(c => c.name === 'TestCounter')!;
+        expect(counter.status).toBe('failed');
+        expect(counter.flaky).toBeUndefined();
+        expect(counter.attempts).toBe(2);
+        expect(counter.data_races).toHaveLength(1);
+        expect(result.success).toBe(false);
+      } finally {
+        mockExecFile.mockReset();
+      }
+    });
+
+    it('should send SIGQUIT to a hung test and capture the goroutine dump', async () => {
+      const child: any = new EventEmitter();
+      child.stdout = new EventEmitter();
+      child.stderr = new EventEmitter();
+      child.pid = 4242;
+      child.kill = jest.fn();
+
+      mockSpawn.mockImplementation(() => {
+        setImmediate(() => {
+          child.stdout.emit('data', Buffer.from(jsonEvents([
+            { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+            { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0 },
+            { Action: 'run', Package: 'example.com/calc', Test: 'TestHang' },
+          ])));
+        });
+        return child;
+      });
+
+      const killSpy = jest.spyOn(process, 'kill').mockImplementation(((pid: number, signal: string) => {
+        if (signal === 'SIGQUIT') {
+          child.stdout.emit('data', Buffer.from(jsonEvents([
+            { Action: 'output', Package: 'example.com/calc', Test: 'TestHang', Output: 'SIGQUIT: quit\n' },
+            { Action: 'output', Package: 'example.com/calc', Test: 'TestHang', Output: 'goroutine 7 [chan receive]:\n' },
+            { Action: 'fail', Package: 'example.com/calc', Test: 'TestHang', Elapsed: 0.2 },
+            { Action: 'fail', Package: 'example.com/calc', Elapsed: 0.2 },
+          ])));
+          setImmediate(() => child.emit('close', 1));
+        }
+        return true;
+      }) as any);
+
+      try {
+        const result = await runner.execute(workspacePath, codeFilePath, testFilePath, {
+          ...options,
+          test_timeout_seconds: 0.05,
+        });
+
+        expect(killSpy).toHaveBeenCalledWith(-4242, 'SIGQUIT');
+        const hung = result.test_cases!.find(c => c.name === 'TestHang')!;
+        expect(hung.status).toBe('timed_out');
+        expect(hung.goroutine_dump).toContain('goroutine 7 [chan receive]');
+        expect(result.test_cases!.find(c => c.name === 'TestAdd')!.status).toBe('passed');
+        expect(result.success).toBe(false);
+      } finally {
+        killSpy.mockRestore();
+      }
+    });
+  });
+});
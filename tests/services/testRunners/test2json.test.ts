@@ -0,0 +1,101 @@
+/**
+ * Unit Tests for Test2JSON Converter
+ */
+
+import { Test2JsonConverter } from '../../../src/services/testRunners/test2json';
+
+describe('Test2JsonConverter', () => {
+  const events = (jsonLines: string) => jsonLines.trim().split('\n').map(line => JSON.parse(line));
+
+  it('should convert verbose test output into go test -json events', () => {
+    let clock = 1000;
+    const converter = new Test2JsonConverter('example.com/calc', () => clock);
+
+    const out = converter.write([
+      '=== RUN   TestAdd',
+      '--- PASS: TestAdd (0.01s)',
+      '=== RUN   TestDiv',
+      '    calc_test.go:12: want 2, got 3',
+      '--- FAIL: TestDiv (0.02s)',
+      'FAIL',
+      'coverage: 75.0% of statements',
+      '',
+    ].join('\n'));
+    clock = 2500;
+
+    expect(events(out + converter.end(1))).toEqual([
+      { Action: 'start', Package: 'example.com/calc' },
+      { Action: 'run', Package: 'example.com/calc', Test: 'TestAdd' },
+      { Action: 'output', Package: 'example.com/calc', Test: 'TestAdd', Output: '=== RUN   TestAdd\n' },
+      { Action: 'output', Package: 'example.com/calc', Test: 'TestAdd', Output: '--- PASS: TestAdd (0.01s)\n' },
+      { Action: 'pass', Package: 'example.com/calc', Test: 'TestAdd', Elapsed: 0.01 },
+      { Action: 'run', Package: 'example.com/calc', Test: 'TestDiv' },
+      { Action: 'output', Package: 'example.com/calc', Test: 'TestDiv', Output: '=== RUN   TestDiv\n' },
+      { Action: 'output', Package: 'example.com/calc', Test: 'TestDiv', Output: '    calc_test.go:12: want 2, got 3\n' },
+      { Action: 'output', Package: 'example.com/calc', Test: 'TestDiv', Output: '--- FAIL: TestDiv (0.02s)\n' },
+      { Action: 'fail', Package: 'example.com/calc', Test: 'TestDiv', Elapsed: 0.02 },
+      { Action: 'output', Package: 'example.com/calc', Output: 'FAIL\n' },
+      { Action: 'output', Package: 'example.com/calc', Output: 'coverage: 75.0% of statements\n' },
+      { Action: 'fail', Package: 'example.com/calc', Elapsed: 1.5 },
+    ]);
+  });
+
+  it('should attribute interleaved parallel output by === NAME and CONT lines', () => {
+    const converter = new Test2JsonConverter('example.com/p');
+
+    const out = events(converter.write([
+      '=== RUN   TestA',
+      '=== PAUSE TestA',
+      '=== RUN   TestB',
+      '=== CONT  TestA',
+      '    a_test.go:5: from A',
+      '=== NAME  TestB',
+      '    b_test.go:9: from B',
+      '',
+    ].join('\n')));
+
+    expect(out.filter(e => e.Action !== 'output').map(e => `${e.Action} ${e.Test}`)).toEqual([
+      'start undefined',
+      'run TestA',
+      'pause TestA',
+      'run TestB',
+      'cont TestA',
+    ]);
+    expect(out.find(e => e.Output === '    a_test.go:5: from A\n').Test).toBe('TestA');
+    expect(out.find(e => e.Output === '    b_test.go:9: from B\n').Test).toBe('TestB');
+  });
+
+  it('should parse indented subtest results', () => {
+    const converter = new Test2JsonConverter('example.com/p');
+
+    const out = events(converter.write('--- FAIL: TestT (0.00s)\n    --- SKIP: TestT/slow (0.00s)\n'));
+
+    expect(out.filter(e => e.Action !== 'output').map(e => `${e.Action} ${e.Test}`)).toEqual([
+      'start undefined',
+      'fail TestT',
+      'skip TestT/slow',
+    ]);
+  });
+
+  it('should buffer partial lines across chunks', () => {
+    const converter = new Test2JsonConverter('example.com/p');
+
+    expect(converter.write('=== RUN   Test')).toBe(JSON.stringify({ Action: 'start', Package: 'example.com/p' }) + '\n');
+    const out = events(converter.write('One\n') + converter.end(0));
+
+    expect(out.map(e => e.Action)).toEqual(['run', 'output', 'pass']);
+    expect(out[0].Test).toBe('TestOne');
+  });
+
+  it('should flush a trailing line without a newline', () => {
+    const converter = new Test2JsonConverter('example.com/p');
+    converter.write('');
+
+    const out = events(converter.end(2));
+
+    expect(out).toEqual([
+      expect.objectContaining({ Action: 'fail' }),
+    ]);
+    expect(events(new Test2JsonConverter('example.com/p').end(0))[0].Action).toBe('start');
+  });
+});
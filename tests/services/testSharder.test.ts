@@ -0,0 +1,96 @@
+/**
+ * Unit Tests for Test Sharder
+ */
+
+import * as fs from 'fs/promises';
+import * as os from 'os';
+import * as path from 'path';
+import { TestSharder } from '../../src/services/testSharder';
+
+jest.mock('../../src/services/loggerService');
+
+describe('TestSharder', () => {
+  let sharder: TestSharder;
+
+  beforeEach(() => {
+    sharder = new TestSharder();
+  });
+
+  describe('balance', () => {
+    it('should place the slowest tests first on the lightest shard', () => {
+      const shards = sharder.balance([
+        { name: 'TestA', duration_ms: 50 },
+        { name: 'TestB', duration_ms: 30 },
+        { name: 'TestC', duration_ms: 20 },
+        { name: 'TestD', duration_ms: 20 },
+        { name: 'TestE', duration_ms: 10 },
+      ], 2);
+
+      expect(shards).toEqual([['TestA', 'TestD'], ['TestB', 'TestC', 'TestE']]);
+    });
+
+    it('should deal tests round-robin by name without history', () => {
+      const shards = sharder.balance([{ name: 'TestC' }, { name: 'TestA' }, { name: 'TestB' }], 2);
+
+      expect(shards).toEqual([['TestA', 'TestC'], ['TestB']]);
+    });
+
+    it('should assume the average duration for tests without history', () => {
+      const shards = sharder.balance([
+        { name: 'TestA', duration_ms: 100 },
+        { name: 'TestB' },
+        { name: 'TestC', duration_ms: 20 },
+      ], 2);
+
+      expect(shards).toEqual([['TestA'], ['TestB', 'TestC']]);
+    });
+
+    it('should not return more shards than tests', () => {
+      expect(sharder.balance([{ name: 'TestOnly', duration_ms: 5 }], 4)).toEqual([['TestOnly']]);
+      expect(sharder.balance([], 4)).toEqual([]);
+    });
+  });
+
+  describe('estimateWallTime', () => {
+    it('should start each job on the first free worker, in order', () => {
+      expect(sharder.estimateWallTime([9000, 4000, 4000, 1000], 2)).toBe(9000);
+      expect(sharder.estimateWallTime([1000, 9000, 4000], 2)).toBe(9000);
+      expect(sharder.estimateWallTime([3000, 2000], 1)).toBe(5000);
+      expect(sharder.estimateWallTime([], 4)).toBe(0);
+    });
+  });
+
+  describe('updateTimings', () => {
+    it('should record top-level durations and keep timings of tests that did not run', async () => {
+      const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'alcs-timings-'));
+      try {
+        const timingsPath = path.join(dir, 'ci', 'timings.json');
+        await fs.mkdir(path.dirname(timingsPath), { recursive: true });
+        await fs.writeFile(timingsPath, JSON.stringify({
+          'example.com/api': { TestGet: 100 },
+          'example.com/store': { TestMigrate: 9000, TestRemoved: 50 },
+        }));
+
+        await sharder.updateTimings(timingsPath, [
+          { package: 'example.com/store', name: 'TestMigrate', status: 'passed', duration_ms: 7000, output: '' },
+          { package: 'example.com/store', name: 'TestMigrate/v2', status: 'passed', duration_ms: 6000, output: '' },
+          { package: 'example.com/store', name: 'TestSlow', status: 'skipped', duration_ms: 0, output: '' },
+          { package: 'example.com/store', name: '[build failed]', status: 'error', duration_ms: 0, output: '' },
+          { package: 'example.com/calc', name: 'TestAdd', status: 'failed', duration_ms: 3, output: '' },
+        ]);
+
+        expect(await sharder.readTimings(timingsPath)).toEqual({
+          'example.com/api': { TestGet: 100 },
+          'example.com/store': { TestMigrate: 7000, TestRemoved: 50 },
+          'example.com/calc': { TestAdd: 3 },
+        });
+      } finally {
+        await fs.rm(dir, { recursive: true, force: true });
+      }
+    });
+
+    it('should treat a missing timings file as no history', async () => {
+      await expect(sharder.readTimings(path.join(os.tmpdir(), 'alcs-no-such-timings.json'))).resolves.toEqual({});
+    });
+  });
+});
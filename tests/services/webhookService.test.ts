@@ -0,0 +1,119 @@
+/**
+ * Unit Tests for Webhook Service
+ */
+
+import MockAdapter from 'axios-mock-adapter';
+import axios from 'axios';
+import { WebhookService } from '../../src/services/webhookService';
+import { createRunSummary } from '../../src/services/reporters/jsonSummaryReporter';
+import { logger } from '../../src/services/loggerService';
+
+jest.mock('../../src/services/loggerService');
+
+describe('WebhookService', () => {
+  let mock: MockAdapter;
+  let sleep: jest.Mock;
+  let service: WebhookService;
+  const url = 'https://hooks.example.com/services/T000/B000/secret';
+
+  const summary = createRunSummary({
+    framework: 'go-test',
+    startedAt: new Date('2026-01-01T00:00:00Z'),
+    finishedAt: new Date('2026-01-01T00:00:05Z'),
+    gitSha: 'abc123',
+    tests: [
+      { package: 'example.com/mod/api', name: 'TestGet', status: 'passed', duration_ms: 10 },
+      { package: 'example.com/mod/api', name: 'TestPut', status: 'failed', duration_ms: 20, failure_message: 'want 200, got "500"' },
+    ],
+    coveragePercentage: 81.5,
+    success: false,
+  });
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+    mock = new MockAdapter(axios);
+    sleep = jest.fn().mockResolvedValue(undefined);
+    service = new WebhookService({ maxRetries: 2, baseDelayMs: 100, maxDelayMs: 1000, sleep });
+  });
+
+  afterEach(() => {
+    mock.restore();
+  });
+
+  describe('notify', () => {
+    it('should post a compact summary by default', async () => {
+      mock.onPost(url).reply(200);
+
+      await expect(service.notify(summary, { url })).resolves.toBe(true);
+
+      const body = JSON.parse(mock.history.post[0].data);
+      expect(body).toEqual(expect.objectContaining({
+        status: 'failed',
+        totals: summary.totals,
+        coverage: { percentage: 81.5 },
+        exit_status: 1,
+        failed_tests: [{ package: 'example.com/mod/api', name: 'TestPut', failure_message: 'want 200, got "500"' }],
+      }));
+      expect(body.tests).toBeUndefined();
+      expect(mock.history.post[0].headers!['Content-Type']).toBe('application/json');
+    });
+
+    it('should retry 5xx responses with backoff', async () => {
+      mock.onPost(url).replyOnce(502).onPost(url).replyOnce(503).onPost(url).replyOnce(200);
+
+      await expect(service.notify(summary, { url })).resolves.toBe(true);
+
+      expect(mock.history.post).toHaveLength(3);
+      expect(sleep.mock.calls).toEqual([[100], [200]]);
+    });
+
+    it('should not retry 4xx responses', async () => {
+      mock.onPost(url).reply(404);
+
+      await expect(service.notify(summary, { url })).resolves.toBe(false);
+
+      expect(mock.history.post).toHaveLength(1);
+      expect(logger.warn).toHaveBeenCalledWith(expect.stringContaining('HTTP 404'));
+    });
+
+    it('should give up without throwing when retries run out', async () => {
+      mock.onPost(url).networkError();
+
+      await expect(service.notify(summary, { url })).resolves.toBe(false);
+
+      expect(mock.history.post).toHaveLength(3);
+      expect(logger.warn).toHaveBeenCalledWith(expect.stringContaining('failed after 3 attempts'));
+    });
+
+    it('should keep the secret path out of the logs', async () => {
+      mock.onPost(url).reply(500);
+
+      await service.notify(summary, { url });
+
+      const logged = (logger.warn as jest.Mock).mock.calls.map(c => c[0]).join('\n');
+      expect(logged).toContain('https://hooks.example.com');
+      expect(logged).not.toContain('secret');
+    });
+  });
+
+  describe('renderBody', () => {
+    it('should fill placeholders from the summary', () => {
+      const body = service.renderBody(
+        summary,
+        '{"text": "Run {{status}}: {{ totals.failed }}/{{totals.total}} failed at {{metadata.git_sha}}"}'
+      );
+
+      expect(JSON.parse(body)).toEqual({ text: 'Run failed: 1/2 failed at abc123' });
+    });
+
+    it('should escape values for JSON strings', () => {
+      const body = service.renderBody(summary, '{"text": "{{tests.1.failure_message}}"}');
+
+      expect(JSON.parse(body)).toEqual({ text: 'want 200, got "500"' });
+    });
+
+    it('should render unknown placeholders as empty', () => {
+      expect(service.renderBody(summary, '{"text": "{{metadata.go_version}}{{nope.nothing}}"}')).toBe('{"text": ""}');
+    });
+  });
+});
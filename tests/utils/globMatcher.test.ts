@@ -0,0 +1,30 @@
+import { globToRegExp, matchesAnyGlob } from '../../src/utils/globMatcher';
+
+describe('globToRegExp', () => {
+  const cases: [string, string, boolean][] = [
+    ['internal/auth/**', 'example.com/mod/internal/auth', true],
+    ['internal/auth/**', 'example.com/mod/internal/auth/oauth/token.go', true],
+    ['internal/auth/**', 'example.com/mod/internal/authz', false],
+    ['*_gen.go', 'example.com/mod/store/db_gen.go', true],
+    ['*_gen.go', 'example.com/mod/store/db.go', false],
+    ['*.pb.go', 'example.com/mod/api/v1/service.pb.go', true],
+    ['vendor/', 'example.com/mod/vendor/github.com/lib/pq/conn.go', true],
+    ['**/mocks/**', 'example.com/mod/store/mocks/db.go', true],
+    ['/cmd/*', 'cmd/server', true],
+    ['/cmd/*', 'example.com/mod/cmd/server', false],
+    ['store/?.go', 'example.com/mod/store/a.go', true],
+  ];
+
+  cases.forEach(([pattern, filePath, expected]) => {
+    it(`should ${expected ? '' : 'not '}match ${filePath} with ${pattern}`, () => {
+      expect(globToRegExp(pattern).test(filePath)).toBe(expected);
+    });
+  });
+});
+
+describe('matchesAnyGlob', () => {
+  it('should match when any pattern matches', () => {
+    expect(matchesAnyGlob('example.com/mod/store/db_gen.go', ['*.pb.go', '*_gen.go'])).toBe(true);
+    expect(matchesAnyGlob('example.com/mod/store/db.go', ['*.pb.go', '*_gen.go'])).toBe(false);
+  });
+});
@@ -0,0 +1,39 @@
+import { colorEnabled, createPalette, stripAnsi } from '../../src/utils/terminalColor';
+
+describe('colorEnabled', () => {
+  const tty = { isTTY: true };
+  const pipe = { isTTY: false };
+
+  it('should color a terminal and not a pipe in auto mode', () => {
+    expect(colorEnabled('auto', tty, {})).toBe(true);
+    expect(colorEnabled('auto', pipe, {})).toBe(false);
+  });
+
+  it('should respect NO_COLOR and dumb terminals in auto mode', () => {
+    expect(colorEnabled('auto', tty, { NO_COLOR: '1' })).toBe(false);
+    expect(colorEnabled('auto', tty, { NO_COLOR: '' })).toBe(true);
+    expect(colorEnabled('auto', tty, { TERM: 'dumb' })).toBe(false);
+  });
+
+  it('should let always and never override detection', () => {
+    expect(colorEnabled('always', pipe, { NO_COLOR: '1' })).toBe(true);
+    expect(colorEnabled('never', tty, {})).toBe(false);
+  });
+});
+
+describe('createPalette', () => {
+  it('should wrap text in escape sequences when enabled', () => {
+    expect(createPalette(true).red('FAIL')).toBe('\x1b[31mFAIL\x1b[39m');
+    expect(createPalette(true).bold('')).toBe('');
+  });
+
+  it('should leave text untouched when disabled', () => {
+    expect(createPalette(false).red('FAIL')).toBe('FAIL');
+  });
+});
+
+describe('stripAnsi', () => {
+  it('should remove color and line control sequences', () => {
+    expect(stripAnsi('\r\x1b[K\x1b[32mok\x1b[39m \x1b[1mexample.com/calc\x1b[22m')).toBe('\rok example.com/calc');
+  });
+});
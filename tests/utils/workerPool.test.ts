@@ -0,0 +1,80 @@
+import { runWorkerPool } from '../../src/utils/workerPool';
+import { CancelledError } from '../../src/utils/cancellation';
+
+describe('runWorkerPool', () => {
+  const delay = (ms: number) => new Promise(resolve => setTimeout(resolve, ms));
+
+  it('should never exceed the concurrency limit', async () => {
+    let active = 0;
+    let peak = 0;
+
+    await runWorkerPool([1, 2, 3, 4, 5, 6], 2, async () => {
+      active++;
+      peak = Math.max(peak, active);
+      await delay(5);
+      active--;
+    });
+
+    expect(peak).toBe(2);
+  });
+
+  it('should return outcomes in input order regardless of completion order', async () => {
+    const outcomes = await runWorkerPool([30, 5, 15], 3, async ms => {
+      await delay(ms);
+      return ms * 2;
+    });
+
+    expect(outcomes.map(o => o.ok && o.value)).toEqual([60, 10, 30]);
+  });
+
+  it('should let idle workers take queued items while a slow item runs', async () => {
+    const started: string[] = [];
+
+    await runWorkerPool(['slow', 'a', 'b', 'c'], 2, async item => {
+      started.push(item);
+      await delay(item === 'slow' ? 40 : 2);
+    });
+
+    expect(started).toEqual(['slow', 'a', 'b', 'c']);
+  });
+
+  it('should record failures without losing other results', async () => {
+    const outcomes = await runWorkerPool(['ok', 'bad', 'ok2'], 1, async item => {
+      if (item === 'bad') {
+        throw new Error('container failed to start');
+      }
+      return item.toUpperCase();
+    });
+
+    expect(outcomes[0]).toEqual({ item: 'ok', ok: true, value: 'OK' });
+    expect(outcomes[1]).toEqual(expect.objectContaining({ item: 'bad', ok: false }));
+    expect(outcomes[2]).toEqual({ item: 'ok2', ok: true, value: 'OK2' });
+  });
+
+  it('should handle an empty item list', async () => {
+    expect(await runWorkerPool([], 4, async () => 1)).toEqual([]);
+  });
+
+  it('should stop taking new items once cancelled', async () => {
+    const controller = new AbortController();
+    const started: number[] = [];
+
+    const outcomes = await runWorkerPool([1, 2, 3, 4], 1, async item => {
+      started.push(item);
+      if (item === 2) {
+        controller.abort();
+      }
+      return item;
+    }, controller.signal);
+
+    expect(started).toEqual([1, 2]);
+    expect(outcomes.slice(0, 2)).toEqual([
+      { item: 1, ok: true, value: 1 },
+      { item: 2, ok: true, value: 2 },
+    ]);
+    for (const outcome of outcomes.slice(2)) {
+      expect(outcome.ok).toBe(false);
+      expect(!outcome.ok && outcome.error).toBeInstanceOf(CancelledError);
+    }
+  });
+});